@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WithArgs annotates err with positional arguments, such as those of the
+// call that failed, for when naming each value with WithData is more than
+// it is worth:
+//
+//	return errors.WithArgs(err, lockID, userID)
+//
+// The arguments are recorded as data under the keys arg0, arg1, and so on,
+// and printed by %+v, in order, in a section of their own.
+// If err is nil, WithArgs returns nil. If there are no args, err is returned
+// unchanged.
+func WithArgs(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if len(args) == 0 {
+		return err
+	}
+	return runHooks(&withArgs{err, append([]interface{}(nil), args...)}, HookWithData)
+}
+
+// argKey returns the data key of the i'th argument recorded with WithArgs.
+func argKey(i int) string {
+	return "arg" + strconv.Itoa(i)
+}
+
+type withArgs struct {
+	error
+	args []interface{}
+}
+
+func (w *withArgs) Unwrap() error { return w.error }
+
+// data returns the arguments keyed as described for WithArgs.
+func (w *withArgs) data() map[string]interface{} {
+	data := make(map[string]interface{}, len(w.args))
+	for i, v := range w.args {
+		data[argKey(i)] = v
+	}
+	return data
+}
+
+// DataCache returns the arguments, keyed as described for WithArgs, and
+// every key/value pair of the wrapped errors.
+func (w *withArgs) DataCache() map[string]interface{} {
+	kv := w.data()
+	for k, v := range treeData(w.error) {
+		if _, ok := kv[k]; !ok {
+			kv[k] = v
+		}
+	}
+	return kv
+}
+
+func (w *withArgs) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, w) {
+				return
+			}
+			c := formatConfig()
+			fmt.Fprintf(s, "%+v%s%s", w.Unwrap(), c.Separator, argsSection(w.args, c))
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like w.
+func (w *withArgs) GoString() string {
+	var b strings.Builder
+	for _, v := range w.args {
+		fmt.Fprintf(&b, ", %#v", v)
+	}
+	return fmt.Sprintf("errors.WithArgs(%#v%s)", w.error, b.String())
+}
+
+// argsSection returns the section printed with %+v for args: the label and
+// the arguments rendered by formatValue, as in a call.
+func argsSection(args []interface{}, c FormatConfig) string {
+	vals := make([]string, len(args))
+	for i, v := range args {
+		vals[i] = formatValue(v)
+	}
+	return c.ArgsLabel + "(" + strings.Join(vals, ", ") + ")"
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWithArgs(t *testing.T) {
+	err := WithData(WithArgs(io.EOF, "lock-1", 42, time.Second), "user", "ann")
+
+	if got := err.Error(); got != "EOF" {
+		t.Errorf("Error: got %q, want %q", got, "EOF")
+	}
+	for key, want := range map[string]interface{}{"arg0": "lock-1", "arg1": 42, "arg2": time.Second} {
+		if got, _ := GetValue(err, key); got != want {
+			t.Errorf("GetValue(%q): got %v, want %v", key, got, want)
+		}
+	}
+	want := "EOF\n" +
+		"ERROR ARGS: (lock-1, 42, 1s)\n" +
+		"ERROR DATA: map[user:ann]"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	if got, want := FormatData(err), "ERROR ARGS: (lock-1, 42, 1s)\nERROR DATA: map[user:ann]"; got != want {
+		t.Errorf("FormatData:\n got %q\nwant %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%#v", WithArgs(io.EOF, "x", 1)), `errors.WithArgs(&errors.errorString{s:"EOF"}, "x", 1)`; got != want {
+		t.Errorf("%%#v: got %q, want %q", got, want)
+	}
+
+	if WithArgs(nil, 1) != nil {
+		t.Error("WithArgs(nil): want nil")
+	}
+	if WithArgs(io.EOF) != io.EOF {
+		t.Error("WithArgs without args: want err unchanged")
+	}
+}
@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// The data keys under which Retry describes each failed attempt. The time
+// the attempt ran is recorded under KeyElapsed.
+const (
+	// KeyAttempt holds the 1-based number of the attempt.
+	KeyAttempt = "attempt"
+	// KeyDelay holds how long Retry waited before the attempt.
+	KeyDelay = "delay"
+)
+
+// A Backoff returns how long Retry should wait after attempt number attempt
+// failed with err, and whether it should try again at all.
+type Backoff func(err error, attempt int) (time.Duration, bool)
+
+// Retry calls fn until it succeeds, up to attempts times, waiting between
+// attempts for the delay returned by backoff. A nil backoff retries at once.
+// Retry stops early if an attempt fails with an error marked with
+// MarkPermanent, if backoff says not to try again, or if ctx is done.
+//
+// If every attempt fails, Retry returns a single error wrapping each
+// attempt's error annotated with its number, the delay before it, and the
+// time it ran, under KeyAttempt, KeyDelay, and KeyElapsed. Its message is
+// that of the last attempt, and formatting it with %+v prints a table of the
+// attempts, after the AttemptsLabel of the FormatConfig, followed by the
+// last attempt's error with %+v:
+//
+//	err := errors.Retry(ctx, 3, errors.NextDelay, func(ctx context.Context) error {
+//	        return client.Acquire(ctx, lockID)
+//	})
+//
+// Is, As, Walk, and GetValue search every attempt's error; Attempts returns
+// them.
+func Retry(ctx context.Context, attempts int, backoff Backoff, fn func(ctx context.Context) error) error {
+	var r retryError
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		start := now()
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		r.errs = append(r.errs, attachData(err, []interface{}{
+			KeyAttempt, attempt,
+			KeyDelay, delay,
+			KeyElapsed, since(start),
+		}))
+		if attempt >= attempts || IsPermanent(err) {
+			break
+		}
+		delay = 0
+		if backoff != nil {
+			var ok bool
+			if delay, ok = backoff(err, attempt); !ok {
+				break
+			}
+		}
+		if !sleep(ctx, delay) {
+			break
+		}
+	}
+	return runHooks(&r, HookWrap)
+}
+
+// sleep waits for d according to the Clock set with SetClock, and reports
+// whether it did so before ctx was done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	c, stop := after(d)
+	defer stop()
+	select {
+	case <-c:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Attempts returns the errors of the failed attempts aggregated by the
+// shallowest error returned by Retry in err's chain, in order, or nil if
+// there is none.
+func Attempts(err error) []error {
+	var r *retryError
+	if !As(err, &r) {
+		return nil
+	}
+	return append([]error(nil), r.errs...)
+}
+
+// retryError aggregates the errors of the attempts made by Retry.
+type retryError struct {
+	errs []error
+}
+
+func (r *retryError) Error() string { return r.errs[len(r.errs)-1].Error() }
+
+// Unwrap returns the errors of the attempts.
+func (r *retryError) Unwrap() []error { return r.errs }
+
+// Is reports whether any attempt's error matches target, for Go releases
+// whose errors.Is does not follow Unwrap() []error.
+func (r *retryError) Is(target error) bool {
+	for _, err := range r.errs {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first attempt's error that matches target, for Go releases
+// whose errors.As does not follow Unwrap() []error.
+func (r *retryError) As(target interface{}) bool {
+	for _, err := range r.errs {
+		if As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *retryError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, r.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, r) {
+				return
+			}
+			c := formatConfig()
+			io.WriteString(s, attemptsTable(r.errs, c))
+			fmt.Fprintf(s, "%s%+v", c.Separator, r.errs[len(r.errs)-1])
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, r.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", r.Error())
+	}
+}
+
+// GoString returns a Go expression describing r. Retry cannot be called
+// from an expression, so the attempts' errors are shown joined.
+func (r *retryError) GoString() string {
+	var b strings.Builder
+	b.WriteString("errors.Join(")
+	for i, err := range r.errs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#v", err)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// attemptsTable returns a line counting errs followed by one aligned row per
+// attempt: its number, the delay before it, the time it ran, and its
+// message.
+func attemptsTable(errs []error, c FormatConfig) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%d\n", c.AttemptsLabel, len(errs))
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	io.WriteString(tw, "ATTEMPT\tDELAY\tELAPSED\tERROR\n")
+	for _, err := range errs {
+		data := err.(*withData).data
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%s\n", data[KeyAttempt], data[KeyDelay], data[KeyElapsed], err.Error())
+	}
+	tw.Flush()
+	return strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\n"), "\n", c.Separator)
+}
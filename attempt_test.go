@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	defer SetClock(SetClock(ClockFunc(func() time.Time { return at })))
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, 5, nil, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry succeeding on attempt 3: got (%v, %d calls)", err, calls)
+	}
+
+	var delays []int
+	backoff := func(err error, attempt int) (time.Duration, bool) {
+		delays = append(delays, attempt)
+		return time.Nanosecond, true
+	}
+	calls = 0
+	err = Retry(ctx, 3, backoff, func(context.Context) error {
+		calls++
+		return fmt.Errorf("attempt %d: %w", calls, io.EOF)
+	})
+	if got := err.Error(); got != "attempt 3: EOF" {
+		t.Errorf("Error: got %q, want %q", got, "attempt 3: EOF")
+	}
+	if !Is(err, io.EOF) {
+		t.Error("Is: want a match on the attempts' errors")
+	}
+	if !reflect.DeepEqual(delays, []int{1, 2}) {
+		t.Errorf("backoff called after attempts %v, want [1 2]", delays)
+	}
+	attempts := Attempts(Wrap(err, "acquiring"))
+	if len(attempts) != 3 {
+		t.Fatalf("Attempts: got %d, want 3", len(attempts))
+	}
+	for i, a := range attempts {
+		if got, _ := GetValue(a, KeyAttempt); got != i+1 {
+			t.Errorf("attempt %d: %s: got %v", i+1, KeyAttempt, got)
+		}
+	}
+	if got, _ := GetValue(attempts[1], KeyDelay); got != time.Nanosecond {
+		t.Errorf("attempt 2: %s: got %v, want 1ns", KeyDelay, got)
+	}
+	want := "FAILED ATTEMPTS: 3\n" +
+		"ATTEMPT  DELAY  ELAPSED  ERROR\n" +
+		"1        0s     0s       attempt 1: EOF\n" +
+		"2        1ns    0s       attempt 2: EOF\n" +
+		"3        1ns    0s       attempt 3: EOF\n" +
+		"attempt 3: EOF\n" +
+		"ERROR DATA: map[attempt:3 delay:1ns elapsed:0s]"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+
+	calls = 0
+	Retry(ctx, 5, nil, func(context.Context) error {
+		calls++
+		return MarkPermanent(io.EOF)
+	})
+	if calls != 1 {
+		t.Errorf("Retry with a permanent error: got %d calls, want 1", calls)
+	}
+	calls = 0
+	Retry(ctx, 5, func(error, int) (time.Duration, bool) { return 0, false }, func(context.Context) error {
+		calls++
+		return io.EOF
+	})
+	if calls != 1 {
+		t.Errorf("Retry with a backoff refusing: got %d calls, want 1", calls)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	Retry(canceled, 5, nil, func(context.Context) error {
+		calls++
+		return io.EOF
+	})
+	if calls != 1 {
+		t.Errorf("Retry with a canceled context: got %d calls, want 1", calls)
+	}
+
+	if Attempts(io.EOF) != nil {
+		t.Error("Attempts without Retry: want nil")
+	}
+}
+
+// fakeTimer is a TimerClock whose After returns at once, recording the delays.
+type fakeTimer struct {
+	at     time.Time
+	delays []time.Duration
+}
+
+func (f *fakeTimer) Now() time.Time { return f.at }
+
+func (f *fakeTimer) After(d time.Duration) <-chan time.Time {
+	f.delays = append(f.delays, d)
+	f.at = f.at.Add(d)
+	c := make(chan time.Time, 1)
+	c <- f.at
+	return c
+}
+
+func TestRetryClockAndLayout(t *testing.T) {
+	clk := &fakeTimer{at: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	defer SetClock(SetClock(clk))
+	backoff := func(err error, attempt int) (time.Duration, bool) { return time.Hour, true }
+	fail := func(context.Context) error { return New("lock offline") }
+
+	err := Retry(context.Background(), 3, backoff, fail)
+	if !reflect.DeepEqual(clk.delays, []time.Duration{time.Hour, time.Hour}) {
+		t.Errorf("TimerClock.After: got %v, want [1h 1h]", clk.delays)
+	}
+
+	defer SetFormatConfig(SetFormatConfig(FormatConfig{AttemptsLabel: "RETRIES: "}))
+	out := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(out, "RETRIES: 3\nATTEMPT") {
+		t.Errorf("%%+v: want the AttemptsLabel, got:\n%s", out)
+	}
+	snap, perr := Parse(out)
+	if perr != nil {
+		t.Fatalf("Parse: %v", perr)
+	}
+	var msgs []string
+	for _, l := range snap.Layers {
+		if l.Message != "" {
+			msgs = append(msgs, l.Message)
+		}
+	}
+	if !reflect.DeepEqual(msgs, []string{"lock offline"}) {
+		t.Errorf("Parse: got messages %q, want only the last attempt's", msgs)
+	}
+}
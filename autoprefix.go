@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// autoPrefix is non-zero when Wrap, Wrapf, and WrapWithData prefix their
+// messages with the name of their caller.
+var autoPrefix int32
+
+// AutoPrefix sets whether Wrap, Wrapf, and WrapWithData prefix their message
+// with the short name of the calling function, as WrapAuto does, so that
+// messages stay informative even when the text given is terse. It is off by
+// default.
+func AutoPrefix(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&autoPrefix, v)
+}
+
+// WrapAuto returns an error annotating err with a stack trace at the point
+// WrapAuto is called, and the supplied message prefixed with the short name
+// of the calling function:
+//
+//	func (s *Store) SaveLock(l *Lock) error {
+//	        ...
+//	        return errors.WrapAuto(err, "encoding") // "store.(*Store).SaveLock: encoding: ..."
+//	}
+//
+// If message is empty, the name alone is used.
+// If err is nil, WrapAuto returns nil.
+func WrapAuto(err error, message string) error {
+	if err == nil {
+		nilWrap("WrapAuto")
+		return nil
+	}
+	err = &withMessage{
+		error: err,
+		msg:   callerPrefix(message),
+	}
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// maybePrefix returns message prefixed as by callerPrefix if AutoPrefix is
+// enabled, and message unchanged otherwise. Like callerPrefix, it must be
+// called directly by an exported function.
+func maybePrefix(message string) string {
+	if atomic.LoadInt32(&autoPrefix) == 0 {
+		return message
+	}
+	return callerPrefixAt(4, message)
+}
+
+// callerPrefix returns message prefixed with the short name of the caller
+// of the exported function calling callerPrefix.
+func callerPrefix(message string) string {
+	return callerPrefixAt(4, message)
+}
+
+// callerPrefixAt returns message prefixed with the short name of the
+// function skip frames up the stack of callerPrefixAt's caller, counting
+// runtime.Callers itself.
+func callerPrefixAt(skip int, message string) string {
+	name := callerName(skip)
+	if name == "" || message == "" {
+		return name + message
+	}
+	return name + ": " + message
+}
+
+// callerName returns the short name of the function that runtime.Callers(skip)
+// would report first if called in callerName's caller, or "" if there is none.
+func callerName(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	return shortFuncName(frame.Function)
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+type autoStore struct{}
+
+func (autoStore) save() error { return WrapAuto(io.EOF, "encoding") }
+
+func (autoStore) load() error { return Wrapf(io.EOF, "decoding %d", 1) }
+
+func TestWrapAuto(t *testing.T) {
+	if got, want := (autoStore{}).save().Error(), "lib_errors.autoStore.save: encoding: EOF"; got != want {
+		t.Errorf("WrapAuto: got %q, want %q", got, want)
+	}
+	if got, want := WrapAuto(io.EOF, "").Error(), "lib_errors.TestWrapAuto: EOF"; got != want {
+		t.Errorf("WrapAuto without a message: got %q, want %q", got, want)
+	}
+	if WrapAuto(nil, "x") != nil {
+		t.Error("WrapAuto(nil): want nil")
+	}
+}
+
+func TestAutoPrefix(t *testing.T) {
+	AutoPrefix(true)
+	defer AutoPrefix(false)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(io.EOF, "reading"), "lib_errors.TestAutoPrefix: reading: EOF"},
+		{WrapWithData(io.EOF, "reading", "k", 1), "lib_errors.TestAutoPrefix: reading: EOF"},
+		{(autoStore{}).load(), "lib_errors.autoStore.load: decoding 1: EOF"},
+		{WithMessage(io.EOF, "reading"), "reading: EOF"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
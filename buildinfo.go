@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Keys under which BuildInfo reports the build of the running binary.
+const (
+	KeyBuildModule   = "build_module"
+	KeyBuildVersion  = "build_version"
+	KeyBuildRevision = "build_revision"
+	KeyBuildDirty    = "build_dirty"
+)
+
+var (
+	buildInfoOnce    sync.Once
+	buildInfoKeyVals []interface{}
+)
+
+// BuildInfo returns key/value pairs describing the build of the running
+// binary, read from runtime/debug.ReadBuildInfo: the main module path and
+// version and, when the binary was built from a VCS checkout, the revision
+// and whether the working tree had uncommitted changes. It returns nil if
+// the binary carries no build information.
+//
+// To attach the build to every error, pass it to SetGlobalData:
+//
+//	errors.SetGlobalData(append(errors.BuildInfo(), "service", "lockd")...)
+func BuildInfo() []interface{} {
+	buildInfoOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		buildInfoKeyVals = buildInfoFrom(info)
+	})
+	return append([]interface{}(nil), buildInfoKeyVals...)
+}
+
+// buildInfoFrom extracts the pairs returned by BuildInfo from info.
+func buildInfoFrom(info *debug.BuildInfo) []interface{} {
+	keyVals := []interface{}{
+		KeyBuildModule, info.Main.Path,
+		KeyBuildVersion, info.Main.Version,
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			keyVals = append(keyVals, KeyBuildRevision, s.Value)
+		case "vcs.modified":
+			keyVals = append(keyVals, KeyBuildDirty, s.Value == "true")
+		}
+	}
+	return keyVals
+}
+
+// WithBuildInfo annotates err with the pairs returned by BuildInfo, tying the
+// error's stack traces to the exact build that produced them.
+// If err is nil, WithBuildInfo returns nil.
+func WithBuildInfo(err error) error {
+	return WithData(err, BuildInfo()...)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"testing"
+)
+
+func TestBuildInfoFrom(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/noke-inc/lockd", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "0123abc"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	want := []interface{}{
+		KeyBuildModule, "github.com/noke-inc/lockd",
+		KeyBuildVersion, "v1.2.3",
+		KeyBuildRevision, "0123abc",
+		KeyBuildDirty, true,
+	}
+	if got := buildInfoFrom(info); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildInfoFrom: got %v, want %v", got, want)
+	}
+
+	defer SetKeyPolicy(SetKeyPolicy(KeyPattern(regexp.MustCompile(`^acme\.`))))
+	err := WithData(io.EOF, buildInfoFrom(info)...)
+	if got := GetAllData(err); len(got) != 4 {
+		t.Errorf("GetAllData with KeyPattern: got %v, want the build keys kept", got)
+	}
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	if got := WithBuildInfo(nil); got != nil {
+		t.Errorf("WithBuildInfo(nil): got %#v, expected nil", got)
+	}
+
+	err := WithBuildInfo(io.EOF)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("no build information available")
+	}
+	if got, _ := GetValue(err, KeyBuildModule); got != info.Main.Path {
+		t.Errorf("GetValue(%s): got %v, want %v", KeyBuildModule, got, info.Main.Path)
+	}
+}
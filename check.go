@@ -0,0 +1,50 @@
+package errors
+
+// checkPanic is the value Check panics with, so that Handle can tell it
+// apart from other panics.
+type checkPanic struct{ err error }
+
+func (p checkPanic) Error() string { return "errors.Check without errors.Handle: " + p.err.Error() }
+func (p checkPanic) Unwrap() error { return p.err }
+
+// Check panics if err is not nil, with err annotated with a stack trace at
+// the point Check is called. The panic is recovered by a deferred Handle in
+// the same function, which returns the error instead, so that long
+// sequential functions need not repeat "if err != nil { return ... }":
+//
+//	func load(path string) (cfg Config, err error) {
+//	        defer errors.Handle(&err, "loading config")
+//	        buf, err := os.ReadFile(path)
+//	        errors.Check(err)
+//	        errors.Check(json.Unmarshal(buf, &cfg))
+//	        return cfg, nil
+//	}
+//
+// Check must only be used in functions that defer Handle.
+func Check(err error) {
+	if err == nil {
+		return
+	}
+	panic(checkPanic{runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)})
+}
+
+// Handle recovers a panic raised by Check and stores its error in *errp.
+// If the function then returns a non-nil error, by Check or otherwise,
+// Handle annotates it with message, unless message is empty. Other panics
+// are propagated unchanged.
+// Handle must be called directly by a defer statement.
+func Handle(errp *error, message string) {
+	if r := recover(); r != nil {
+		p, ok := r.(checkPanic)
+		if !ok {
+			panic(r)
+		}
+		*errp = p.err
+	}
+	if *errp != nil && message != "" {
+		*errp = WithMessage(*errp, message)
+	}
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func parsePair(a, b string) (sum int, err error) {
+	defer Handle(&err, "parsing pair")
+	x, err := strconv.Atoi(a)
+	Check(err)
+	y, err := strconv.Atoi(b)
+	Check(err)
+	return x + y, nil
+}
+
+func TestCheckHandle(t *testing.T) {
+	if sum, err := parsePair("1", "2"); err != nil || sum != 3 {
+		t.Errorf("parsePair: got %d, %v, want 3", sum, err)
+	}
+
+	_, err := parsePair("1", "x")
+	if got, want := err.Error(), `parsing pair: strconv.Atoi: parsing "x": invalid syntax`; got != want {
+		t.Fatalf("parsePair: got %q, want %q", got, want)
+	}
+	var ws *withStack
+	if !As(err, &ws) {
+		t.Fatal("parsePair: no stack trace")
+	}
+	if got := fmt.Sprintf("%n", ws.StackTrace()[0]); got != "parsePair" {
+		t.Errorf("parsePair: stack starts in %s, want parsePair", got)
+	}
+
+	returned := func() (err error) {
+		defer Handle(&err, "closing")
+		return io.EOF
+	}
+	if err := returned(); err == nil || err.Error() != "closing: EOF" {
+		t.Errorf("Handle: got %v, want closing: EOF", err)
+	}
+
+	other := func() (err error) {
+		defer Handle(&err, "")
+		panic("boom")
+	}
+	if v := recovered(func() { other() }); v != "boom" {
+		t.Errorf("Handle: got panic %v, want boom", v)
+	}
+
+	unhandled, _ := recovered(func() { Check(io.EOF) }).(error)
+	if unhandled == nil || !Is(unhandled, io.EOF) {
+		t.Errorf("Check: got panic %v, want an error wrapping EOF", unhandled)
+	}
+}
@@ -0,0 +1,109 @@
+package errors
+
+import "sync"
+
+// A Classification says who may see a data value, from ClassPublic, which
+// anyone may, to ClassSecret, which must not leave the process in the clear.
+// Each sink errors are written to declares the highest Classification it may
+// emit, and LimitData removes the values above it.
+type Classification uint8
+
+// The Classifications, in increasing order of sensitivity. Keys that were
+// never classified are ClassInternal.
+const (
+	// ClassPublic data may be shown to the clients of a service, such as
+	// the code and Kind of an error. It is emitted by Public.
+	ClassPublic Classification = iota
+	// ClassInternal data may be logged and shown on debug endpoints, but
+	// not to clients.
+	ClassInternal
+	// ClassSecret data, such as credentials or personal data, is only kept
+	// in memory and by sinks that protect it.
+	ClassSecret
+)
+
+var classificationNames = [...]string{
+	ClassPublic:   "public",
+	ClassInternal: "internal",
+	ClassSecret:   "secret",
+}
+
+// String returns the lower-case name of the Classification.
+func (c Classification) String() string {
+	if int(c) < len(classificationNames) {
+		return classificationNames[c]
+	}
+	return classificationNames[ClassSecret]
+}
+
+var classifications = struct {
+	sync.RWMutex
+	byKey map[string]Classification
+}{
+	byKey: make(map[string]Classification),
+}
+
+// ClassifyKeys sets the Classification of the data recorded under keys,
+// overriding the defaults described for KeyClassification. ClassifyKeys is
+// meant to be called during program initialization:
+//
+//	errors.ClassifyKeys(errors.ClassSecret, "password", "session_token")
+//	errors.ClassifyKeys(errors.ClassPublic, "lock_id")
+func ClassifyKeys(c Classification, keys ...string) {
+	classifications.Lock()
+	defer classifications.Unlock()
+	for _, k := range keys {
+		classifications.byKey[k] = c
+	}
+}
+
+// KeyClassification returns the Classification of the data recorded under
+// key: the one set with ClassifyKeys, or ClassPublic for the PublicDataKeys,
+// or ClassInternal.
+func KeyClassification(key string) Classification {
+	classifications.RLock()
+	c, ok := classifications.byKey[key]
+	classifications.RUnlock()
+	if ok {
+		return c
+	}
+	for _, k := range PublicDataKeys {
+		if k == key {
+			return ClassPublic
+		}
+	}
+	return ClassInternal
+}
+
+// LimitData returns err with the data classified above max removed from
+// every error of this package in its tree, for a sink that may only emit
+// data up to max. Like MapChain, it rebuilds the errors whose data changed,
+// but it also descends into the errors wrapped by multi-errors and sealed
+// errors, those attached with WithSecondary and WithSuppressed, and errors
+// recorded as data values. Data set with SetGlobalData is not affected.
+// If err is nil, LimitData returns nil.
+func LimitData(err error, max Classification) error {
+	limited, _ := mapTree(err, func(l Layer) Layer {
+		for k := range l.Data {
+			if KeyClassification(k) > max {
+				delete(l.Data, k)
+			}
+		}
+		return l
+	})
+	return limited
+}
+
+// LimitedData returns the key/value pairs that GetAllData returns for
+// LimitData(err, max), without the global data classified above max, for
+// sinks that write data rather than whole errors.
+// If err is nil, LimitedData returns nil.
+func LimitedData(err error, max Classification) map[string]interface{} {
+	kv := GetAllData(LimitData(err, max))
+	for k := range kv {
+		if KeyClassification(k) > max {
+			delete(kv, k)
+		}
+	}
+	return kv
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyClassification(t *testing.T) {
+	ClassifyKeys(ClassSecret, "classify_password")
+	ClassifyKeys(ClassPublic, "classify_lock_id")
+
+	tests := []struct {
+		key  string
+		want Classification
+	}{
+		{KeyCode, ClassPublic},
+		{"classify_lock_id", ClassPublic},
+		{"classify_password", ClassSecret},
+		{"classify_unknown", ClassInternal},
+	}
+	for _, tt := range tests {
+		if got := KeyClassification(tt.key); got != tt.want {
+			t.Errorf("KeyClassification(%q): got %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	if got := Classification(9).String(); got != "secret" {
+		t.Errorf("String of an unknown Classification: got %q, want %q", got, "secret")
+	}
+}
+
+func TestLimitData(t *testing.T) {
+	ClassifyKeys(ClassSecret, "classify_password")
+	ClassifyKeys(ClassPublic, "classify_lock_id")
+	err := WithCode(WrapWithData(io.EOF, "opening", "classify_password", "hunter2", "host", "db1", "classify_lock_id", 7), "lock_jammed")
+
+	tests := []struct {
+		max  Classification
+		want map[string]interface{}
+	}{
+		{ClassSecret, map[string]interface{}{KeyCode: "lock_jammed", "classify_password": "hunter2", "host": "db1", "classify_lock_id": 7}},
+		{ClassInternal, map[string]interface{}{KeyCode: "lock_jammed", "host": "db1", "classify_lock_id": 7}},
+		{ClassPublic, map[string]interface{}{KeyCode: "lock_jammed", "classify_lock_id": 7}},
+	}
+	for _, tt := range tests {
+		limited := LimitData(err, tt.max)
+		if got := GetAllData(limited); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LimitData(%v): got %v, want %v", tt.max, got, tt.want)
+		}
+		if !Is(limited, io.EOF) || limited.Error() != "opening: EOF" {
+			t.Errorf("LimitData(%v): got %v, want the chain kept", tt.max, limited)
+		}
+	}
+	if got, want := Public(err).(DataError).DataCache(), tests[2].want; !reflect.DeepEqual(got, want) {
+		t.Errorf("Public: got %v, want %v", got, want)
+	}
+	if LimitData(nil, ClassPublic) != nil {
+		t.Error("LimitData(nil): want nil")
+	}
+}
+
+func TestLimitDataTree(t *testing.T) {
+	ClassifyKeys(ClassSecret, "classify_password")
+	secret := func() error { return WrapWithData(io.EOF, "opening", "classify_password", "hunter2", "host", "db1") }
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"sealed", WithKind(Seal(secret()), KindUnavailable)},
+		{"joined", Wrap(Join(New("first"), secret()), "closing")},
+		{"secondary", WithSecondary(New("primary"), secret())},
+		{"suppressed", Suppress(New("primary"), secret())},
+		{"data value", WithData(New("primary"), "cause", secret())},
+	}
+	for _, tt := range tests {
+		limited := LimitData(tt.err, ClassInternal)
+		got := fmt.Sprintf("%+v", limited)
+		if strings.Contains(got, "hunter2") {
+			t.Errorf("%s: %%+v: got %q, want the secret removed", tt.name, got)
+		}
+		if !strings.Contains(got, "db1") {
+			t.Errorf("%s: %%+v: got %q, want the internal data kept", tt.name, got)
+		}
+		if limited.Error() != tt.err.Error() {
+			t.Errorf("%s: Error: got %q, want %q", tt.name, limited.Error(), tt.err.Error())
+		}
+		if LimitData(tt.err, ClassSecret) != tt.err {
+			t.Errorf("%s: LimitData with nothing to remove: got a rebuilt error", tt.name)
+		}
+	}
+}
+
+func TestLimitedData(t *testing.T) {
+	ClassifyKeys(ClassSecret, "classify_token", "classify_password")
+	SetGlobalData("classify_token", "t0k3n", "region", "eu")
+	defer SetGlobalData()
+
+	err := WithData(WrapWithData(io.EOF, "opening", "classify_password", "hunter2", "host", "db1"), "cause", WithData(New("dialing"), "classify_password", "hunter2"))
+	got := LimitedData(err, ClassInternal)
+	for _, k := range []string{"classify_token", "classify_password"} {
+		if v, ok := got[k]; ok {
+			t.Errorf("LimitedData: %s = %v, want it removed", k, v)
+		}
+	}
+	if got["host"] != "db1" || got["region"] != "eu" {
+		t.Errorf("LimitedData: got %v, want the internal data kept", got)
+	}
+	if s := fmt.Sprintf("%+v", got["cause"]); strings.Contains(s, "hunter2") {
+		t.Errorf("LimitedData: cause: got %q, want the secret removed", s)
+	}
+	if LimitedData(nil, ClassSecret) != nil {
+		t.Error("LimitedData(nil): want nil")
+	}
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// KeyExitCode is the data key under which WithExitCode records the process
+// exit status for an error.
+const KeyExitCode = "exit_code"
+
+// WithExitCode annotates err with the status a command-line tool should exit
+// with when err is its final error.
+// If err is nil, WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	return WithData(err, KeyExitCode, code)
+}
+
+// ExitCode returns the process exit status for err: 0 if err is nil, the
+// shallowest status recorded with WithExitCode, or otherwise a status chosen
+// by the error's Kind following the BSD sysexits(3) conventions:
+//
+//	KindInvalid                     65 (EX_DATAERR)
+//	KindNotFound                    66 (EX_NOINPUT)
+//	KindUnavailable                 69 (EX_UNAVAILABLE)
+//	KindInternal                    70 (EX_SOFTWARE)
+//	KindTimeout, KindRateLimited    75 (EX_TEMPFAIL)
+//	KindUnauthenticated,
+//	KindPermission                  77 (EX_NOPERM)
+//	KindCanceled                    130 (terminated by Control-C)
+//	anything else                   1
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if v, ok := GetValue(err, KeyExitCode); ok {
+		if code, ok := v.(int); ok {
+			return code
+		}
+	}
+	switch KindOf(err) {
+	case KindInvalid:
+		return 65
+	case KindNotFound:
+		return 66
+	case KindUnavailable:
+		return 69
+	case KindInternal:
+		return 70
+	case KindTimeout, KindRateLimited:
+		return 75
+	case KindUnauthenticated, KindPermission:
+		return 77
+	case KindCanceled:
+		return 130
+	}
+	return 1
+}
+
+// Render writes err as the final output of a command-line tool. By default
+// it writes a single line holding the user message recorded with
+// WithUserMessage or, if there is none, err.Error(), preceded by the title
+// recorded with WithTitle, if any. If verbose is true it writes the full
+// annotated chain, including stack traces and data, as formatted with %+v.
+// Either is followed by one "hint: " line per hint returned by Hints.
+// If err is nil, Render writes nothing.
+func Render(w io.Writer, err error, verbose bool) {
+	if err == nil {
+		return
+	}
+	if verbose {
+		fmt.Fprintf(w, "error: %+v\n", err)
+	} else {
+		msg := UserMessage(err)
+		if msg == "" {
+			msg = err.Error()
+		}
+		if title := Title(err); title != "" {
+			msg = title + ": " + msg
+		}
+		fmt.Fprintf(w, "error: %s\n", msg)
+	}
+	for _, hint := range Hints(err) {
+		fmt.Fprintf(w, "hint: %s\n", hint)
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 1},
+		{WithKind(io.EOF, KindNotFound), 66},
+		{WithKind(io.EOF, KindPermission), 77},
+		{Wrap(WithKind(io.EOF, KindCanceled), "wrapped"), 130},
+		{WithExitCode(WithKind(io.EOF, KindNotFound), 3), 3},
+		{WithKind(WithExitCode(io.EOF, 3), KindNotFound), 3},
+		{WithExitCode(WithExitCode(io.EOF, 3), 4), 4},
+	}
+
+	for i, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("test %d: ExitCode(%v): got %d, want %d", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithExitCode(nil, 2); got != nil {
+		t.Errorf("WithExitCode(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		err     error
+		verbose bool
+		want    []string
+	}{
+		{nil, false, nil},
+		{Wrap(io.EOF, "reading config"), false, []string{"error: reading config: EOF"}},
+		{WithUserMessage(Wrap(io.EOF, "reading config"), "config file is truncated"), false, []string{"error: config file is truncated"}},
+		{WithTitle(WithUserMessage(io.EOF, "config file is truncated"), "Invalid config"), false, []string{"error: Invalid config: config file is truncated"}},
+		{WithHint(Wrap(io.EOF, "reading config"), "run app init"), false, []string{"error: reading config: EOF\nhint: run app init\n"}},
+		{WithHint(Wrap(io.EOF, "reading config"), "run app init"), true, []string{"reading config", "\nhint: run app init\n"}},
+		{WrapWithData(io.EOF, "reading config", "path", "/etc/app.yaml"), true, []string{
+			"error: EOF",
+			"reading config",
+			"ERROR DATA: map[path:/etc/app.yaml]",
+			"github.com/noke-inc/lib_errors.TestRender",
+		}},
+	}
+
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		Render(&buf, tt.err, tt.verbose)
+		got := buf.String()
+		if tt.want == nil {
+			if got != "" {
+				t.Errorf("test %d: Render(%v): got %q, want nothing", i+1, tt.err, got)
+			}
+			continue
+		}
+		if !strings.HasSuffix(got, "\n") {
+			t.Errorf("test %d: Render(%v): got %q, want trailing newline", i+1, tt.err, got)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("test %d: Render(%v): got %q, want it to contain %q", i+1, tt.err, got, w)
+			}
+		}
+	}
+}
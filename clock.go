@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// A Clock tells the time to the functions of this package that record when
+// something happened: timestamps, elapsed times, Retry-After dates, and
+// sampling rates.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time { return f() }
+
+// clock holds the Clock set with SetClock, wrapped in a clockHolder so that
+// atomic.Value always stores the same concrete type.
+var clock atomic.Value
+
+type clockHolder struct{ Clock }
+
+// SetClock makes the package tell the time with c instead of the system
+// clock, and returns the previous Clock. Setting a nil Clock restores the
+// system clock. It is intended for tests:
+//
+//	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+//	defer errors.SetClock(errors.SetClock(errors.ClockFunc(func() time.Time { return at })))
+func SetClock(c Clock) (previous Clock) {
+	h, _ := clock.Swap(clockHolder{c}).(clockHolder)
+	return h.Clock
+}
+
+// Now returns the current time according to the Clock set with SetClock,
+// for the subpackages and reporters that stamp errors, so that they follow
+// the Clock of tests too.
+func Now() time.Time { return now() }
+
+// now returns the current time according to the Clock set with SetClock.
+func now() time.Time {
+	if h, _ := clock.Load().(clockHolder); h.Clock != nil {
+		return h.Now()
+	}
+	return time.Now()
+}
+
+// A TimerClock is a Clock that can also wait. If the Clock set with
+// SetClock is a TimerClock, Retry waits between attempts with After instead
+// of a system timer, so that tests can run Retry without sleeping.
+type TimerClock interface {
+	Clock
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// after returns a channel that receives the time once d has elapsed
+// according to the Clock set with SetClock, if it is a TimerClock, or to the
+// system clock otherwise, and a function releasing the timer.
+func after(d time.Duration) (<-chan time.Time, func()) {
+	if h, _ := clock.Load().(clockHolder); h.Clock != nil {
+		if t, ok := h.Clock.(TimerClock); ok {
+			return t.After(d), func() {}
+		}
+	}
+	t := time.NewTimer(d)
+	return t.C, func() { t.Stop() }
+}
+
+// since returns the time elapsed since t according to now.
+func since(t time.Time) time.Duration {
+	return now().Sub(t)
+}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestSetClock(t *testing.T) {
+	c := &fakeClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if prev := SetClock(c); prev != nil {
+		t.Fatalf("SetClock: got previous %v, want nil", prev)
+	}
+	defer SetClock(nil)
+	if got := Now(); !got.Equal(c.t) {
+		t.Errorf("Now: got %v, want %v", got, c.t)
+	}
+
+	err := WithTimestamp(New("boom"))
+	if ts, ok := Timestamp(err); !ok || !ts.Equal(c.t) {
+		t.Errorf("Timestamp: got %v, %v, want %v", ts, ok, c.t)
+	}
+	timer := StartTimer()
+	c.Advance(90 * time.Second)
+	if age, _ := Age(err); age != 90*time.Second {
+		t.Errorf("Age: got %v, want 90s", age)
+	}
+	if d := timer.Elapsed(); d != 90*time.Second {
+		t.Errorf("Elapsed: got %v, want 90s", d)
+	}
+
+	s := NewSampler(1, 1)
+	if !s.Sample(err) || s.Sample(err) {
+		t.Fatal("Sample: want first sampled and second dropped")
+	}
+	c.Advance(time.Second)
+	if !s.Sample(err) {
+		t.Error("Sample: want sampled after the bucket refilled")
+	}
+
+	if prev := SetClock(nil); prev != Clock(c) {
+		t.Errorf("SetClock: got previous %v, want %v", prev, c)
+	}
+	if ts := now(); time.Since(ts) > time.Minute {
+		t.Errorf("now after restore: got %v, want the system time", ts)
+	}
+}
@@ -0,0 +1,212 @@
+// Command errgen generates typed constructors and sentinels for a set of
+// errors described by a JSON manifest, keeping the codes, kinds, and HTTP
+// statuses of a large error taxonomy consistent:
+//
+//	{
+//	        "errors": [
+//	                {
+//	                        "name": "LockNotFound",
+//	                        "message": "lock not found",
+//	                        "code": "lock_not_found",
+//	                        "kind": "not_found",
+//	                        "status": 404
+//	                }
+//	        ]
+//	}
+//
+// For each entry errgen emits a sentinel ErrLockNotFound and a constructor
+// LockNotFound(keyVals ...interface{}) error returning an error with a stack
+// trace that matches the sentinel with errors.Is and carries the code, kind,
+// and status. Only name and message are required; kind is one of the
+// snake_case names of errors.Kind.
+//
+// errgen is meant to be run by go generate:
+//
+//	//go:generate go run github.com/noke-inc/lib_errors/cmd/errgen -in errors.json -out errors_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strings"
+	"text/template"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// Manifest describes a set of errors to generate.
+type Manifest struct {
+	// Package is the package clause of the generated file. It defaults to
+	// the package being generated by go generate.
+	Package string  `json:"package"`
+	Errors  []Entry `json:"errors"`
+}
+
+// Entry describes a single error of a Manifest.
+type Entry struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Doc     string `json:"doc"`
+	Code    string `json:"code"`
+	Kind    string `json:"kind"`
+	Status  int    `json:"status"`
+}
+
+func main() {
+	in := flag.String("in", "errors.json", "manifest `file` to read")
+	out := flag.String("out", "errors_gen.go", "Go `file` to write")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package `name` of the generated file, if the manifest has none")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "errgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	buf, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	var m Manifest
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return errors.WrapJSONDecode(err, buf)
+	}
+	if m.Package == "" {
+		m.Package = pkg
+	}
+	src, err := generate(m)
+	if err != nil {
+		return errors.Wrap(err, in)
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+// generate returns the formatted source of the file generated for m.
+func generate(m Manifest) ([]byte, error) {
+	if !token.IsIdentifier(m.Package) {
+		return nil, errors.Errorf("invalid package name %q", m.Package)
+	}
+	names := make(map[string]bool)
+	var entries []entry
+	for i, e := range m.Errors {
+		switch {
+		case !token.IsIdentifier(e.Name) || !token.IsExported(e.Name):
+			return nil, errors.Errorf("errors[%d]: name %q is not an exported identifier", i, e.Name)
+		case names[e.Name]:
+			return nil, errors.Errorf("errors[%d]: duplicate name %q", i, e.Name)
+		case e.Message == "":
+			return nil, errors.Errorf("errors[%d]: %s has no message", i, e.Name)
+		case e.Status != 0 && (e.Status < 100 || e.Status > 599):
+			return nil, errors.Errorf("errors[%d]: %s has invalid HTTP status %d", i, e.Name, e.Status)
+		}
+		names[e.Name] = true
+		ge := entry{Entry: e}
+		if e.Kind != "" {
+			k, ok := kindIdent(e.Kind)
+			if !ok {
+				return nil, errors.Errorf("errors[%d]: %s has unknown kind %q", i, e.Name, e.Kind)
+			}
+			ge.KindIdent = k
+		}
+		entries = append(entries, ge)
+	}
+
+	var b bytes.Buffer
+	if err := fileTemplate.Execute(&b, struct {
+		Package string
+		Errors  []entry
+	}{m.Package, entries}); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// entry is an Entry prepared for fileTemplate.
+type entry struct {
+	Entry
+	KindIdent string
+}
+
+// Summary lists the code, kind, and status of e for its doc comment.
+func (e entry) Summary() string {
+	var parts []string
+	if e.Code != "" {
+		parts = append(parts, fmt.Sprintf("code %q", e.Code))
+	}
+	if e.KindIdent != "" {
+		parts = append(parts, "kind errors."+e.KindIdent)
+	}
+	if e.Status != 0 {
+		parts = append(parts, fmt.Sprintf("HTTP status %d", e.Status))
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	case 2:
+		return parts[0] + " and " + parts[1]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + ", and " + parts[len(parts)-1]
+}
+
+// kindIdent returns the identifier of the errors.Kind constant named name.
+func kindIdent(name string) (string, bool) {
+	for k := errors.KindUnknown + 1; k != errors.KindUnknown; k++ {
+		if k.String() != name {
+			continue
+		}
+		ident := "Kind"
+		for _, word := range strings.Split(name, "_") {
+			ident += strings.ToUpper(word[:1]) + word[1:]
+		}
+		return ident, true
+	}
+	return "", false
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by errgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import errors "github.com/noke-inc/lib_errors"
+
+// Sentinels matched with errors.Is by the errors returned from the
+// constructors of the same name.
+var (
+{{- range .Errors}}
+	Err{{.Name}} = errors.New({{printf "%q" .Message}})
+{{- end}}
+)
+{{range .Errors}}
+{{- if .Doc}}
+// {{.Name}} {{.Doc}}
+//
+{{- else}}
+// {{.Name}} returns an error matching Err{{.Name}}.
+{{- end}}
+// The error has a stack trace and is annotated with keyVals.
+{{- with .Summary}}
+// It has {{.}}.
+{{- end}}
+func {{.Name}}(keyVals ...interface{}) error {
+	return errors.WithData(errors.WithStack(Err{{.Name}}), append([]interface{}{
+		{{- if .Code}}
+		errors.KeyCode, {{printf "%q" .Code}},
+		{{- end}}
+		{{- if .KindIdent}}
+		errors.KeyKind, errors.{{.KindIdent}},
+		{{- end}}
+		{{- if .Status}}
+		errors.KeyStatusCode, {{.Status}},
+		{{- end}}
+	}, keyVals...)...)
+}
+{{end}}`))
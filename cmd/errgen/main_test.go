@@ -0,0 +1,72 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(Manifest{
+		Package: "lockerr",
+		Errors: []Entry{
+			{Name: "LockNotFound", Message: "lock not found", Code: "lock_not_found", Kind: "not_found", Status: 404},
+			{Name: "Jammed", Message: "lock jammed", Doc: "reports a bolt that failed to move.", Kind: "rate_limited"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by errgen. DO NOT EDIT.\n",
+		"\tErrLockNotFound = errors.New(\"lock not found\")\n",
+		"// It has code \"lock_not_found\", kind errors.KindNotFound, and HTTP status 404.\n",
+		"\t\terrors.KeyKind, errors.KindRateLimited,\n\t}, keyVals...)...)\n",
+		"// Jammed reports a bolt that failed to move.\n",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generate: output does not contain %q:\n%s", want, src)
+		}
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "errors_gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("lockerr", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("type check: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{Name: "lockMissing", Message: "m"}, "not an exported identifier"},
+		{Entry{Name: "Missing"}, "has no message"},
+		{Entry{Name: "Missing", Message: "m", Kind: "gone"}, `unknown kind "gone"`},
+		{Entry{Name: "Missing", Message: "m", Status: 42}, "invalid HTTP status 42"},
+	}
+	for _, tt := range tests {
+		_, err := generate(Manifest{Package: "lockerr", Errors: []Entry{tt.entry}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("generate(%+v): got %v, want error containing %q", tt.entry, err, tt.want)
+		}
+	}
+
+	dup := Entry{Name: "Missing", Message: "m"}
+	if _, err := generate(Manifest{Package: "lockerr", Errors: []Entry{dup, dup}}); err == nil {
+		t.Error("generate: want error for duplicate names")
+	}
+	if _, err := generate(Manifest{Errors: []Entry{dup}}); err == nil {
+		t.Error("generate: want error for missing package name")
+	}
+}
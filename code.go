@@ -0,0 +1,22 @@
+package errors
+
+// KeyCode is the data key under which WithCode records an error's code.
+const KeyCode = "code"
+
+// WithCode annotates err with a stable, machine-readable code (for example
+// "lock_offline") that clients can rely on instead of parsing messages.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code string) error {
+	return WithData(err, KeyCode, code)
+}
+
+// Code returns the shallowest code recorded with WithCode in err's chain, or
+// "" if there is none.
+func Code(err error) string {
+	if v, ok := GetValue(err, KeyCode); ok {
+		if code, ok := v.(string); ok {
+			return code
+		}
+	}
+	return ""
+}
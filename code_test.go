@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithCode(io.EOF, "lock_offline"), "lock_offline"},
+		{Wrap(WithCode(io.EOF, "lock_offline"), "reading state"), "lock_offline"},
+		{WithCode(WithCode(io.EOF, "inner"), "outer"), "outer"},
+		{WithData(io.EOF, KeyCode, 12), ""},
+	}
+
+	for i, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("test %d: Code(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithCode(nil, "code"); got != nil {
+		t.Errorf("WithCode(nil): got %#v, expected nil", got)
+	}
+}
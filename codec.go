@@ -0,0 +1,251 @@
+package errors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+)
+
+// A Codec encodes Snapshots for errors that cross process boundaries, such
+// as those persisted to queues or databases, and decodes them back. The
+// zero Codec encodes a Snapshot as JSON.
+type Codec struct {
+	// CompressAbove is the size in bytes above which the JSON encoding of
+	// a Snapshot is compressed with gzip. Zero or less disables compression.
+	// gzip is the only compression supported, as the others, such as zstd,
+	// are not in the standard library.
+	CompressAbove int
+	// MaxDecodedSize is the size in bytes that the JSON encoding of a
+	// Snapshot may reach once decompressed, so that a small compressed
+	// payload cannot exhaust memory when decoded. Zero means
+	// DefaultMaxDecodedSize; less than zero means no limit.
+	MaxDecodedSize int
+	// MaxEncodedSize is the size in bytes that encoded Snapshots must fit,
+	// such as the message size limit of a broker. Data values are dropped,
+	// largest first, until the encoding fits; the keys dropped are recorded
+	// in the outermost layer under KeyDroppedData. Zero or less means no
+	// limit.
+	MaxEncodedSize int
+	// SigningKey, if set, is the HMAC-SHA256 key that Encode signs
+	// payloads with and that Decode requires them to be signed with, so
+	// that the codes and user messages of errors relayed by intermediate
+	// hops can be trusted. The signature counts towards MaxEncodedSize.
+	SigningKey []byte
+}
+
+// signedPrefix starts a signed payload, followed by the HMAC-SHA256 of the
+// rest of it. It can start neither a JSON document nor a gzip stream.
+const signedPrefix = 0x01
+
+// ErrSignature is returned by Codec.Decode for a payload that is unsigned
+// or whose signature does not match the Codec's SigningKey.
+var ErrSignature = New("snapshot signature invalid")
+
+// DefaultMaxDecodedSize is the MaxDecodedSize of Codecs that do not set one.
+const DefaultMaxDecodedSize = 16 << 20
+
+// KeyDroppedData is the data key under which Codec.Encode records the keys
+// whose values it dropped to fit MaxEncodedSize.
+const KeyDroppedData = "dropped_data"
+
+// DefaultCodec is the Codec used by EncodeSnapshot and DecodeSnapshot.
+var DefaultCodec = &Codec{CompressAbove: 1024}
+
+// EncodeSnapshot encodes s with DefaultCodec.
+func EncodeSnapshot(s Snapshot) ([]byte, error) {
+	return DefaultCodec.Encode(s)
+}
+
+// DecodeSnapshot decodes b with DefaultCodec.
+func DecodeSnapshot(b []byte) (Snapshot, error) {
+	return DefaultCodec.Decode(b)
+}
+
+// Encode returns the JSON encoding of s, compressed with gzip if it is
+// larger than c.CompressAbove, and shrunk to fit c.MaxEncodedSize. Encode
+// returns an error if s does not fit even without data.
+func (c *Codec) Encode(s Snapshot) ([]byte, error) {
+	b, err := c.encode(s)
+	if err != nil || c.MaxEncodedSize <= 0 || len(b) <= c.MaxEncodedSize {
+		return b, err
+	}
+	s = s.copyData()
+	var dropped []string
+	for len(b) > c.MaxEncodedSize {
+		data, key := s.largestValue()
+		if data == nil {
+			return nil, Errorf("encoding snapshot: %d bytes without data exceeds MaxEncodedSize %d", len(b), c.MaxEncodedSize)
+		}
+		delete(data, key)
+		dropped = append(dropped, key)
+		warnData(DataDropped, key, "dropped to fit MaxEncodedSize")
+		if len(s.Layers) > 0 {
+			if s.Layers[0].Data == nil {
+				s.Layers[0].Data = make(map[string]interface{})
+			}
+			s.Layers[0].Data[KeyDroppedData] = dropped
+		}
+		if b, err = c.encode(s); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// encode returns the JSON encoding of s, compressed as configured.
+func (c *Codec) encode(s Snapshot) ([]byte, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, Wrap(err, "encoding snapshot")
+	}
+	if c.CompressAbove > 0 && len(b) > c.CompressAbove {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(b)
+		if err := zw.Close(); err != nil {
+			return nil, Wrap(err, "compressing snapshot")
+		}
+		b = buf.Bytes()
+	}
+	if len(c.SigningKey) > 0 {
+		b = append(append([]byte{signedPrefix}, c.sign(b)...), b...)
+	}
+	return b, nil
+}
+
+// sign returns the HMAC-SHA256 of b keyed with c.SigningKey.
+func (c *Codec) sign(b []byte) []byte {
+	mac := hmac.New(sha256.New, c.SigningKey)
+	mac.Write(b)
+	return mac.Sum(nil)
+}
+
+// Sign returns the signature of b with c.SigningKey, or nil if c has no
+// SigningKey, for protocols that propagate the fields of errors outside of
+// encoded Snapshots, such as the headers written by errhttp.
+func (c *Codec) Sign(b []byte) []byte {
+	if len(c.SigningKey) == 0 {
+		return nil
+	}
+	return c.sign(b)
+}
+
+// Verify returns an error wrapping ErrSignature unless sig is the signature
+// of b with c.SigningKey, as returned by Sign. If c has no SigningKey,
+// Verify accepts any signature, including none.
+func (c *Codec) Verify(b, sig []byte) error {
+	if len(c.SigningKey) == 0 {
+		return nil
+	}
+	if len(sig) == 0 {
+		return Wrap(ErrSignature, "verifying unsigned payload")
+	}
+	if !hmac.Equal(sig, c.sign(b)) {
+		return Wrap(ErrSignature, "verifying payload")
+	}
+	return nil
+}
+
+// Decode decodes a Snapshot encoded by Encode. Compressed payloads are
+// recognized whatever c.CompressAbove is, so the decoding side need not be
+// configured like the encoding one. If c.SigningKey is set, Decode returns
+// an error wrapping ErrSignature unless b was signed with it; otherwise
+// signatures are ignored. Decode returns an error if the JSON encoding
+// exceeds c.MaxDecodedSize.
+func (c *Codec) Decode(b []byte) (Snapshot, error) {
+	signed := len(b) > sha256.Size && b[0] == signedPrefix
+	if len(c.SigningKey) > 0 {
+		if !signed {
+			return Snapshot{}, Wrap(ErrSignature, "decoding unsigned snapshot")
+		}
+		if !hmac.Equal(b[1:1+sha256.Size], c.sign(b[1+sha256.Size:])) {
+			return Snapshot{}, Wrap(ErrSignature, "decoding snapshot")
+		}
+	}
+	if signed {
+		b = b[1+sha256.Size:]
+	}
+	max := c.MaxDecodedSize
+	if max == 0 {
+		max = DefaultMaxDecodedSize
+	}
+	if isGzip(b) {
+		zr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return Snapshot{}, Wrap(err, "decompressing snapshot")
+		}
+		r := io.Reader(zr)
+		if max > 0 {
+			r = io.LimitReader(zr, int64(max)+1)
+		}
+		if b, err = io.ReadAll(r); err != nil {
+			return Snapshot{}, Wrap(err, "decompressing snapshot")
+		}
+	}
+	if max > 0 && len(b) > max {
+		return Snapshot{}, Errorf("decoding snapshot: exceeds MaxDecodedSize %d", max)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return Snapshot{}, Wrap(err, "decoding snapshot")
+	}
+	return s, nil
+}
+
+// isGzip reports whether b starts with the gzip magic number, which cannot
+// start a JSON document.
+func isGzip(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b
+}
+
+// copyData returns a copy of s whose data maps can be modified without
+// affecting s.
+func (s Snapshot) copyData() Snapshot {
+	out := s
+	out.Layers = append([]SnapshotLayer(nil), s.Layers...)
+	for i, l := range out.Layers {
+		out.Layers[i].Data = copyMap(l.Data)
+	}
+	out.GlobalData = copyMap(s.GlobalData)
+	return out
+}
+
+func copyMap(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	kv := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		kv[k] = v
+	}
+	return kv
+}
+
+// largestValue returns the data map of s holding the value with the largest
+// JSON encoding, and its key, or nil if s has no data other than the keys
+// recorded under KeyDroppedData. Ties go to the outermost layer, and to the
+// first key in sorted order.
+func (s Snapshot) largestValue() (map[string]interface{}, string) {
+	var data map[string]interface{}
+	var key string
+	size := -1
+	consider := func(m map[string]interface{}) {
+		for _, k := range sortedKeys(m) {
+			if k == KeyDroppedData {
+				continue
+			}
+			b, _ := json.Marshal(m[k])
+			if len(b) > size {
+				data, key, size = m, k, len(b)
+			}
+		}
+	}
+	for _, l := range s.Layers {
+		consider(l.Data)
+	}
+	consider(s.GlobalData)
+	return data, key
+}
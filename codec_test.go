@@ -0,0 +1,179 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	small := NewSnapshot(WrapWithData(io.EOF, "reading", "lock_id", "7"))
+	large := NewSnapshot(WrapWithData(io.EOF, "reading", "blob", strings.Repeat("state ", 1000)))
+
+	tests := []struct {
+		codec    *Codec
+		snap     Snapshot
+		wantGzip bool
+	}{
+		{&Codec{}, large, false},
+		{&Codec{CompressAbove: 1024}, small, false},
+		{&Codec{CompressAbove: 1024}, large, true},
+	}
+	for i, tt := range tests {
+		b, err := tt.codec.Encode(tt.snap)
+		if err != nil {
+			t.Fatalf("test %d: Encode: %v", i+1, err)
+		}
+		if isGzip(b) != tt.wantGzip {
+			t.Errorf("test %d: compressed: got %v, want %v", i+1, isGzip(b), tt.wantGzip)
+		}
+		got, err := (&Codec{}).Decode(b)
+		if err != nil {
+			t.Fatalf("test %d: Decode: %v", i+1, err)
+		}
+		if want, _ := (&Codec{}).Decode(mustJSON(t, tt.snap)); !reflect.DeepEqual(got, want) {
+			t.Errorf("test %d: Decode: got %+v, want %+v", i+1, got, want)
+		}
+	}
+	if len(mustEncode(t, large)) >= len(mustJSON(t, large)) {
+		t.Error("EncodeSnapshot: want the large snapshot compressed")
+	}
+
+	if _, err := DecodeSnapshot([]byte{0x1f, 0x8b, 0}); err == nil {
+		t.Error("Decode of a truncated gzip payload: want an error")
+	}
+	if _, err := DecodeSnapshot([]byte("{")); err == nil {
+		t.Error("Decode of malformed JSON: want an error")
+	}
+}
+
+func TestCodecMaxDecodedSize(t *testing.T) {
+	bomb := NewSnapshot(WrapWithData(io.EOF, "reading", "blob", strings.Repeat("0", 1<<20)))
+	b, err := (&Codec{CompressAbove: 1}).Encode(bomb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Codec{MaxDecodedSize: 64 << 10}).Decode(b); err == nil {
+		t.Error("Decode over MaxDecodedSize: want an error")
+	}
+	if _, err := (&Codec{MaxDecodedSize: 64 << 10}).Decode(mustJSON(t, bomb)); err == nil {
+		t.Error("Decode of uncompressed JSON over MaxDecodedSize: want an error")
+	}
+	if _, err := (&Codec{MaxDecodedSize: 2 << 20}).Decode(b); err != nil {
+		t.Errorf("Decode within MaxDecodedSize: %v", err)
+	}
+	if _, err := (&Codec{MaxDecodedSize: -1}).Decode(b); err != nil {
+		t.Errorf("Decode without a limit: %v", err)
+	}
+}
+
+func mustJSON(t *testing.T, s Snapshot) []byte {
+	b, err := (&Codec{}).Encode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func mustEncode(t *testing.T, s Snapshot) []byte {
+	b, err := EncodeSnapshot(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCodecMaxEncodedSize(t *testing.T) {
+	var warnings []DataWarning
+	defer SetDataWarningHook(SetDataWarningHook(func(w DataWarning) { warnings = append(warnings, w) }))
+
+	err := WrapWithData(WithData(io.EOF, "blob", strings.Repeat("x", 2000), "lock_id", "7"), "reading", "body", strings.Repeat("y", 1000))
+	snap := NewSnapshot(err)
+	c := &Codec{MaxEncodedSize: 600}
+	b, encErr := c.Encode(snap)
+	if encErr != nil {
+		t.Fatal(encErr)
+	}
+	if len(b) > c.MaxEncodedSize {
+		t.Errorf("Encode: got %d bytes, want at most %d", len(b), c.MaxEncodedSize)
+	}
+	got, decErr := c.Decode(b)
+	if decErr != nil {
+		t.Fatal(decErr)
+	}
+	data := GetAllData(got.Err())
+	if want := []interface{}{"blob", "body"}; !reflect.DeepEqual(data[KeyDroppedData], want) {
+		t.Errorf("%s: got %v, want %v", KeyDroppedData, data[KeyDroppedData], want)
+	}
+	if data["lock_id"] != "7" {
+		t.Errorf("lock_id: got %v, want it kept", data["lock_id"])
+	}
+	if len(warnings) != 2 || warnings[0].Kind != DataDropped || warnings[0].Key != "blob" {
+		t.Errorf("warnings: got %+v", warnings)
+	}
+	if _, ok := snap.Layers[0].Data["body"]; !ok {
+		t.Error("Encode modified the Snapshot it was given")
+	}
+
+	if _, encErr := (&Codec{MaxEncodedSize: 10}).Encode(snap); encErr == nil {
+		t.Error("Encode of a Snapshot too large without data: want an error")
+	}
+}
+
+func TestCodecSigning(t *testing.T) {
+	snap := NewSnapshot(WithCode(WithUserMessage(io.EOF, "try again"), "lock_jammed"))
+	signer := &Codec{SigningKey: []byte("k1"), CompressAbove: 64}
+	b, err := signer.Encode(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := signer.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Code(got.Err()) != "lock_jammed" {
+		t.Errorf("Decode: got code %q", Code(got.Err()))
+	}
+	if _, err := (&Codec{}).Decode(b); err != nil {
+		t.Errorf("Decode without a key: %v", err)
+	}
+
+	tampered := append([]byte(nil), b...)
+	tampered[len(tampered)-3] ^= 1
+	unsigned, _ := (&Codec{}).Encode(snap)
+	tests := []struct {
+		name string
+		c    *Codec
+		b    []byte
+	}{
+		{"tampered", signer, tampered},
+		{"unsigned", signer, unsigned},
+		{"other key", &Codec{SigningKey: []byte("k2")}, b},
+	}
+	for _, tt := range tests {
+		if _, err := tt.c.Decode(tt.b); !Is(err, ErrSignature) {
+			t.Errorf("%s: got %v, want ErrSignature", tt.name, err)
+		}
+	}
+
+	payload := []byte("code=lock_jammed")
+	sig := signer.Sign(payload)
+	if err := signer.Verify(payload, sig); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	for _, tt := range []struct {
+		name         string
+		payload, sig []byte
+	}{
+		{"tampered", []byte("code=lock_ok"), sig},
+		{"unsigned", payload, nil},
+	} {
+		if err := signer.Verify(tt.payload, tt.sig); !Is(err, ErrSignature) {
+			t.Errorf("Verify %s: got %v, want ErrSignature", tt.name, err)
+		}
+	}
+	if (&Codec{}).Sign(payload) != nil || (&Codec{}).Verify(payload, nil) != nil {
+		t.Error("Sign and Verify without a key: want no signature required")
+	}
+}
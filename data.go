@@ -0,0 +1,42 @@
+package errors
+
+// GetValue returns the shallowest value recorded under key (using WithData or
+// WrapWithData) anywhere in err's chain, and whether such a value was found.
+// Errors wrapping several others are searched in the order of Walk.
+// If the chain has no value for key, the value set with SetGlobalData is
+// returned instead, unless key is one of the package's own keys, such as
+// KeyKind or KeyStatusCode: those describe an error, not the process, so
+// that KindOf, Code, HTTPStatus, and the like only report what was recorded
+// in the chain.
+func GetValue(err error, key string) (interface{}, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var v interface{}
+	var found bool
+	Walk(err, func(e error) bool {
+		switch e := e.(type) {
+		case *withData:
+			v, found = e.data[key]
+		case DataError:
+			v, found = e.DataCache()[key]
+		}
+		return !found
+	})
+	if found {
+		return v, true
+	}
+	if _, ok := reservedKeys[key]; ok {
+		return nil, false
+	}
+	return globalValue(key)
+}
+
+// DataError is implemented by errors that carry key/value pairs, such as
+// those returned by WithData and WrapWithData. DataCache returns the pairs
+// of the error and of every error it wraps, the shallowest value winning for
+// a duplicated key.
+type DataError interface {
+	error
+	DataCache() map[string]interface{}
+}
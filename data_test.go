@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestGetValue(t *testing.T) {
+	tests := []struct {
+		err    error
+		key    string
+		want   interface{}
+		wantOk bool
+	}{
+		{nil, "key", nil, false},
+		{io.EOF, "key", nil, false},
+		{WithData(io.EOF, "key", 1), "key", 1, true},
+		{WithData(io.EOF, "key", 1), "other", nil, false},
+		{WithData(WithData(io.EOF, "key", 1), "key", 2), "key", 2, true},
+		{Wrap(WithData(io.EOF, "key", 1), "wrapped"), "key", 1, true},
+		{WrapWithData(WithData(io.EOF, "deep", 1), "msg", "shallow", 2), "deep", 1, true},
+	}
+
+	for i, tt := range tests {
+		got, ok := GetValue(tt.err, tt.key)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: GetValue(%v, %q): got (%v, %v), want (%v, %v)", i+1, tt.err, tt.key, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
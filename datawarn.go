@@ -0,0 +1,74 @@
+package errors
+
+import "sync/atomic"
+
+// DataWarningKind identifies what happened to a key/value pair reported in
+// a DataWarning.
+type DataWarningKind uint8
+
+const (
+	// DataDropped is reported for pairs that could not be recorded: a key
+	// that is not a string, or a trailing key without a value.
+	DataDropped DataWarningKind = iota
+	// DataTruncated is reported for values shortened to fit a limit, such
+	// as the response body recorded by WrapHTTPResponse.
+	DataTruncated
+	// DataOverridden is reported for keys given twice in the same call, or
+	// shadowing a value recorded deeper in the chain.
+	DataOverridden
+	// DataRejected is reported for keys rejected by the KeyPolicy.
+	DataRejected
+)
+
+func (k DataWarningKind) String() string {
+	switch k {
+	case DataDropped:
+		return "dropped"
+	case DataTruncated:
+		return "truncated"
+	case DataOverridden:
+		return "overridden"
+	case DataRejected:
+		return "rejected"
+	}
+	return "unknown"
+}
+
+// A DataWarning describes a key/value pair that was not recorded as given.
+type DataWarning struct {
+	Kind   DataWarningKind
+	Key    string
+	Detail string
+}
+
+// A DataWarningHook observes DataWarnings. Like a Hook, it runs
+// synchronously on the constructing goroutine, so it should be cheap and
+// must not panic.
+type DataWarningHook func(w DataWarning)
+
+// dataWarningHook holds the DataWarningHook set with SetDataWarningHook.
+var dataWarningHook atomic.Value
+
+// SetDataWarningHook installs h to be called whenever a key/value pair is
+// dropped, truncated, overridden, or rejected while an error is constructed,
+// and returns the previous hook. Counting these lets observability teams
+// monitor the quality of error metadata rather than discover missing fields
+// during an outage. Setting a nil hook, the default, turns the warnings off.
+func SetDataWarningHook(h DataWarningHook) (previous DataWarningHook) {
+	previous, _ = dataWarningHook.Load().(DataWarningHook)
+	dataWarningHook.Store(h)
+	return previous
+}
+
+// dataWarningsEnabled reports whether a DataWarningHook is set.
+func dataWarningsEnabled() bool {
+	h, _ := dataWarningHook.Load().(DataWarningHook)
+	return h != nil
+}
+
+// warnData calls the DataWarningHook, if any.
+func warnData(kind DataWarningKind, key, detail string) {
+	if h, _ := dataWarningHook.Load().(DataWarningHook); h != nil {
+		h(DataWarning{kind, key, detail})
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetDataWarningHook(t *testing.T) {
+	var got []DataWarning
+	prev := SetDataWarningHook(func(w DataWarning) { got = append(got, w) })
+	defer SetDataWarningHook(prev)
+	defer SetKeyPolicy(SetKeyPolicy(SnakeCaseKeys))
+
+	err := WithData(New("boom"), "lock_id", "L-1", 7, "x", "lock_id", "L-2", "LockID", 1, "orphan")
+	WithKind(WithKind(err, KindNotFound), KindInternal)
+
+	defer func(n int) { HTTPBodySnippetLimit = n }(HTTPBodySnippetLimit)
+	HTTPBodySnippetLimit = 4
+	resp := &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader("overflowing"))}
+	WrapHTTPResponse(io.EOF, nil, resp)
+
+	want := []DataWarning{
+		{DataDropped, "7", "key is a int, not a string"},
+		{DataOverridden, "lock_id", "given twice in the same call"},
+		{DataRejected, "LockID", `invalid data key "LockID": not snake_case`},
+		{DataDropped, "orphan", "key without a value"},
+		{DataOverridden, KeyKind, "shadows a value recorded deeper in the chain"},
+		{DataTruncated, "http_body", "truncated to 4 bytes"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DataWarningHook:\n got %+v\nwant %+v", got, want)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "overflowing" {
+		t.Errorf("body after WrapHTTPResponse: got %q", body)
+	}
+}
@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// The data keys under which WrapDeadline describes the context of a failed
+// operation.
+const (
+	// KeyDeadline holds the context's deadline, if it has one.
+	KeyDeadline = "deadline"
+	// KeyDeadlineRemaining holds the time that was left before the deadline
+	// when the error was wrapped; it is negative once the deadline has passed.
+	KeyDeadlineRemaining = "deadline_remaining"
+	// KeyContextErr holds the state of the context: one of ContextActive,
+	// ContextCanceled, or ContextDeadlineExceeded.
+	KeyContextErr = "context_err"
+)
+
+// The states of a context recorded under KeyContextErr.
+const (
+	ContextActive           = "active"
+	ContextCanceled         = "canceled"
+	ContextDeadlineExceeded = "deadline_exceeded"
+)
+
+// WrapDeadline returns an error annotating err with a stack trace at the
+// point WrapDeadline is called, the supplied message, and the state of ctx:
+// its deadline and the time remaining before it under KeyDeadline and
+// KeyDeadlineRemaining, and under KeyContextErr whether ctx was still
+// active, canceled, or past its deadline. This tells a timeout of our own
+// (the context expired) apart from one reported by the other side (the
+// context was still active):
+//
+//	resp, err := client.Do(req.WithContext(ctx))
+//	if err != nil {
+//	        return errors.WrapDeadline(ctx, err, "fetching lock")
+//	}
+//
+// If err is nil, WrapDeadline returns nil.
+func WrapDeadline(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	keyVals := []interface{}{KeyContextErr, contextState(ctx)}
+	if deadline, ok := ctx.Deadline(); ok {
+		keyVals = append(keyVals,
+			KeyDeadline, deadline,
+			KeyDeadlineRemaining, deadline.Sub(now()),
+		)
+	}
+	err = &withMessage{
+		error: err,
+		msg:   message,
+	}
+	err = attachData(err, keyVals)
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// contextState returns the state of ctx as recorded under KeyContextErr.
+func contextState(ctx context.Context) string {
+	switch err := ctx.Err(); {
+	case err == nil:
+		return ContextActive
+	case err == context.DeadlineExceeded:
+		return ContextDeadlineExceeded
+	}
+	return ContextCanceled
+}
+
+// Deadline returns the shallowest deadline recorded with WrapDeadline in
+// err's chain, and whether there was one.
+func Deadline(err error) (time.Time, bool) {
+	if v, ok := GetValue(err, KeyDeadline); ok {
+		if t, ok := v.(time.Time); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// WrapInterrupt returns an error annotating err, the failure of an operation
+// run under ctx, with a stack trace at the point WrapInterrupt is called and
+// with the reason ctx is done: KindCanceled if it was canceled, typically by
+// the caller, or KindTimeout if its deadline passed. The state of ctx is
+// also recorded under KeyContextErr. This lets IsCanceled and IsDeadline
+// classify errors that do not wrap the context's error themselves, such as
+// those of drivers reporting an interrupted operation in their own words.
+// If ctx is not done, err is returned unchanged.
+// If err is nil, WrapInterrupt returns nil.
+func WrapInterrupt(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	state := contextState(ctx)
+	if state == ContextActive {
+		return err
+	}
+	kind := KindCanceled
+	if state == ContextDeadlineExceeded {
+		kind = KindTimeout
+	}
+	err = attachData(err, []interface{}{KeyKind, kind, KeyContextErr, state})
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// IsCanceled reports whether err is the result of a cancellation, as opposed
+// to a timeout: whether the shallowest interruption found by Walk in err's
+// chain is context.Canceled or an error annotated with KindCanceled.
+func IsCanceled(err error) bool {
+	return interruption(err) == KindCanceled
+}
+
+// IsDeadline reports whether err is the result of a timeout, as opposed to a
+// cancellation: whether the shallowest interruption found by Walk in err's
+// chain is context.DeadlineExceeded, an error annotated with KindTimeout, or
+// an error whose Timeout method reports true, as those of the net and os
+// packages do.
+func IsDeadline(err error) bool {
+	return interruption(err) == KindTimeout
+}
+
+// interruption returns KindCanceled or KindTimeout for the shallowest error
+// in err's chain that tells a cancellation or a timeout, and KindUnknown if
+// there is none.
+func interruption(err error) Kind {
+	kind := KindUnknown
+	Walk(err, func(e error) bool {
+		switch e {
+		case context.Canceled:
+			kind = KindCanceled
+		case context.DeadlineExceeded:
+			kind = KindTimeout
+		default:
+			if w, ok := e.(*withData); ok {
+				if k, _ := w.data[KeyKind].(Kind); k == KindCanceled || k == KindTimeout {
+					kind = k
+				}
+			} else if t, ok := e.(interface{ Timeout() bool }); ok && t.Timeout() {
+				kind = KindTimeout
+			}
+		}
+		return kind == KindUnknown
+	})
+	return kind
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWrapDeadline(t *testing.T) {
+	// The context measures its deadline against the system clock.
+	deadline := time.Now().Add(time.Hour)
+	at := deadline.Add(-2 * time.Second)
+	defer SetClock(SetClock(ClockFunc(func() time.Time { return at })))
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	err := WrapDeadline(ctx, io.EOF, "fetching lock")
+	if got := err.Error(); got != "fetching lock: EOF" {
+		t.Errorf("Error: got %q, want %q", got, "fetching lock: EOF")
+	}
+	if got, ok := Deadline(err); !ok || !got.Equal(deadline) {
+		t.Errorf("Deadline: got (%v, %v), want %v", got, ok, deadline)
+	}
+	if got, _ := GetValue(err, KeyDeadlineRemaining); got != 2*time.Second {
+		t.Errorf("%s: got %v, want 2s", KeyDeadlineRemaining, got)
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancelExpired()
+	tests := []struct {
+		ctx  context.Context
+		want string
+	}{
+		{ctx, ContextActive},
+		{canceled, ContextCanceled},
+		{expired, ContextDeadlineExceeded},
+	}
+	for _, tt := range tests {
+		err := WrapDeadline(tt.ctx, io.EOF, "fetching lock")
+		if got, _ := GetValue(err, KeyContextErr); got != tt.want {
+			t.Errorf("%s: got %v, want %v", KeyContextErr, got, tt.want)
+		}
+	}
+	if _, ok := Deadline(WrapDeadline(canceled, io.EOF, "x")); ok {
+		t.Error("Deadline without a context deadline: want false")
+	}
+
+	if WrapDeadline(ctx, nil, "x") != nil {
+		t.Error("WrapDeadline(nil): want nil")
+	}
+}
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string   { return "i/o timeout" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return true }
+
+func TestInterruptions(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancelExpired()
+	driverErr := New("query interrupted")
+
+	tests := []struct {
+		err          error
+		wantCanceled bool
+		wantDeadline bool
+	}{
+		{nil, false, false},
+		{io.EOF, false, false},
+		{context.Canceled, true, false},
+		{Wrap(context.Canceled, "reading"), true, false},
+		{Wrap(context.DeadlineExceeded, "reading"), false, true},
+		{timeoutError{true}, false, true},
+		{timeoutError{false}, false, false},
+		{WithKind(io.EOF, KindCanceled), true, false},
+		{WrapInterrupt(canceled, driverErr), true, false},
+		{WrapInterrupt(expired, driverErr), false, true},
+		{WrapInterrupt(context.Background(), driverErr), false, false},
+		{WrapInterrupt(expired, context.Canceled), false, true},
+	}
+	for i, tt := range tests {
+		if got := IsCanceled(tt.err); got != tt.wantCanceled {
+			t.Errorf("test %d: IsCanceled(%v): got %v, want %v", i+1, tt.err, got, tt.wantCanceled)
+		}
+		if got := IsDeadline(tt.err); got != tt.wantDeadline {
+			t.Errorf("test %d: IsDeadline(%v): got %v, want %v", i+1, tt.err, got, tt.wantDeadline)
+		}
+	}
+
+	if WrapInterrupt(context.Background(), driverErr) != driverErr {
+		t.Error("WrapInterrupt with an active context: want err unchanged")
+	}
+	if WrapInterrupt(canceled, nil) != nil {
+		t.Error("WrapInterrupt(nil): want nil")
+	}
+}
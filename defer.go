@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// DeferWrap annotates the error stored in *errp, if any, as by Wrapf. It is
+// meant to be deferred with a pointer to a named error result, annotating
+// every error the function returns in one place:
+//
+//	func load(path string) (cfg Config, err error) {
+//	        defer errors.DeferWrap(&err, "loading config %s", path)
+//	        ...
+//	}
+//
+// The stack trace is recorded when the function returns, at the return
+// statement. If *errp is nil, DeferWrap does nothing.
+func DeferWrap(errp *error, format string, args ...interface{}) {
+	if *errp == nil {
+		return
+	}
+	err := error(&withMessage{
+		error: *errp,
+		msg:   fmt.Sprintf(format, args...),
+	})
+	*errp = runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// DeferWrapD annotates the error stored in *errp, if any, as by
+// WrapWithData. Like DeferWrap, it is meant to be deferred:
+//
+//	func handle(r *http.Request) (err error) {
+//	        id := r.Header.Get("X-Request-Id")
+//	        defer errors.DeferWrapD(&err, "handling request", "request_id", id)
+//	        ...
+//	}
+//
+// As with any deferred call, message and keyVals are evaluated when the
+// defer statement runs, not when the function returns.
+// If *errp is nil, DeferWrapD does nothing.
+func DeferWrapD(errp *error, message string, keyVals ...interface{}) {
+	if *errp == nil {
+		return
+	}
+	err := error(&withMessage{
+		error: *errp,
+		msg:   message,
+	})
+	err = attachData(err, keyVals)
+	*errp = runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// CloseAndWrap closes c and, if Close fails, annotates its error as by
+// Wrapf and stores it in *errp, joined as by Join with any error already
+// stored there, so that neither the original failure nor the failure to
+// clean up is lost. It is meant to be deferred:
+//
+//	func save(name string, data []byte) (err error) {
+//	        f, err := os.Create(name)
+//	        if err != nil {
+//	                return errors.Wrap(err, "creating file")
+//	        }
+//	        defer errors.CloseAndWrap(&err, f, "closing %s", name)
+//	        ...
+//	}
+func CloseAndWrap(errp *error, c io.Closer, format string, args ...interface{}) {
+	cerr := c.Close()
+	if cerr == nil {
+		return
+	}
+	cerr = &withMessage{
+		error: cerr,
+		msg:   fmt.Sprintf(format, args...),
+	}
+	cerr = runHooks(&withStack{
+		cerr,
+		callers(),
+	}, HookWrap)
+	if *errp == nil {
+		*errp = cerr
+		return
+	}
+	*errp = Join(*errp, cerr)
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func loadConfig(path string, fail bool) (err error) {
+	defer DeferWrap(&err, "loading config %s", path)
+	if fail {
+		return io.EOF
+	}
+	return nil
+}
+
+func TestDeferWrap(t *testing.T) {
+	if err := loadConfig("a.json", false); err != nil {
+		t.Errorf("DeferWrap: got %v, want nil", err)
+	}
+
+	err := loadConfig("a.json", true)
+	if got, want := err.Error(), "loading config a.json: EOF"; got != want {
+		t.Fatalf("DeferWrap: got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%n", err.(*withStack).StackTrace()[0]); got != "loadConfig" {
+		t.Errorf("DeferWrap: stack starts in %s, want loadConfig", got)
+	}
+}
+
+func handleRequest(id string, fail bool) (err error) {
+	defer DeferWrapD(&err, "handling request", "request_id", id)
+	if fail {
+		return io.EOF
+	}
+	return nil
+}
+
+func TestDeferWrapD(t *testing.T) {
+	if err := handleRequest("r1", false); err != nil {
+		t.Errorf("DeferWrapD: got %v, want nil", err)
+	}
+
+	err := handleRequest("r1", true)
+	if got, want := err.Error(), "handling request: EOF"; got != want {
+		t.Fatalf("DeferWrapD: got %q, want %q", got, want)
+	}
+	if v, _ := GetValue(err, "request_id"); v != "r1" {
+		t.Errorf("DeferWrapD: request_id %v, want r1", v)
+	}
+	if got := fmt.Sprintf("%n", err.(*withStack).StackTrace()[0]); got != "handleRequest" {
+		t.Errorf("DeferWrapD: stack starts in %s, want handleRequest", got)
+	}
+}
+
+type closer struct{ err error }
+
+func (c closer) Close() error { return c.err }
+
+func saveFile(c io.Closer, fail bool) (err error) {
+	defer CloseAndWrap(&err, c, "closing %s", "state.json")
+	if fail {
+		return io.ErrShortWrite
+	}
+	return nil
+}
+
+func TestCloseAndWrap(t *testing.T) {
+	if err := saveFile(closer{}, false); err != nil {
+		t.Errorf("CloseAndWrap: got %v, want nil", err)
+	}
+	if err := saveFile(closer{}, true); err != io.ErrShortWrite {
+		t.Errorf("CloseAndWrap: got %v, want short write unchanged", err)
+	}
+
+	err := saveFile(closer{io.ErrClosedPipe}, false)
+	if got, want := err.Error(), "closing state.json: io: read/write on closed pipe"; got != want {
+		t.Fatalf("CloseAndWrap: got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%n", err.(*withStack).StackTrace()[0]); got != "saveFile" {
+		t.Errorf("CloseAndWrap: stack starts in %s, want saveFile", got)
+	}
+
+	err = saveFile(closer{io.ErrClosedPipe}, true)
+	if !Is(err, io.ErrShortWrite) || !Is(err, io.ErrClosedPipe) {
+		t.Errorf("CloseAndWrap: got %v, want both errors", err)
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Description presents an error and the errors it wraps as plain values
+// that debuggers and REPLs can display and navigate, where an error value
+// itself shows little more than an opaque embedded interface.
+type Description struct {
+	// Type is the dynamic type of the error.
+	Type string
+	// Message is the message added by the error, as in Layer.
+	Message string
+	// Data holds the key/value pairs recorded by the error.
+	Data map[string]interface{}
+	// Stack lists the stack trace recorded by the error, one
+	// "function file:line" entry per frame.
+	Stack []string
+	// Wrapped describes the errors wrapped by the error: one for a chain,
+	// several for a multi-error.
+	Wrapped []Description
+}
+
+// Describe returns a Description of err and the errors it wraps, following
+// the same errors as Walk.
+// If err is nil, Describe returns nil.
+func Describe(err error) *Description {
+	if err == nil {
+		return nil
+	}
+	d := describe(err)
+	return &d
+}
+
+func describe(err error) Description {
+	l := layerOf(err)
+	d := Description{
+		Type:    fmt.Sprintf("%T", err),
+		Message: l.Message,
+		Data:    l.Data,
+	}
+	for _, f := range l.Stack {
+		fn, file, line := f.Location()
+		d.Stack = append(d.Stack, fmt.Sprintf("%s %s:%d", fn, file, line))
+	}
+	if errs, ok := multiErrors(err); ok {
+		d.Message = ""
+		for _, e := range errs {
+			if e != nil {
+				d.Wrapped = append(d.Wrapped, describe(e))
+			}
+		}
+	} else if next := Unwrap(err); next != nil {
+		d.Wrapped = []Description{describe(next)}
+	}
+	return d
+}
+
+// goStringData returns data as the Go source of the keyVals arguments of
+// WithData, keys sorted, each pair preceded by ", ".
+func goStringData(data map[string]interface{}) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, ", %q, %#v", k, data[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestGoString(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{New("boom"), `errors.New("boom")`},
+		{Wrap(New("boom"), "reading"), `errors.Wrap(errors.New("boom"), "reading")`},
+		{WithStack(New("boom")), `errors.WithStack(errors.New("boom"))`},
+		{WithMessage(New("boom"), "reading"), `errors.WithMessage(errors.New("boom"), "reading")`},
+		{WithData(New("boom"), "user", "bob", "lock_id", 7), `errors.WithData(errors.New("boom"), "lock_id", 7, "user", "bob")`},
+		{Join(New("a"), New("b")), `errors.Join(errors.New("a"), errors.New("b"))`},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.want {
+			t.Errorf("%%#v: got %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	if d := Describe(nil); d != nil {
+		t.Errorf("Describe(nil): got %+v, want nil", d)
+	}
+
+	get := FakeFrame("github.com/acme/store.Get", "/src/store/store.go", 42)
+	defer SetStackProvider(SetStackProvider(FixedStack(get)))
+	err := WrapWithData(Join(New("a"), io.EOF), "reading", "lock_id", 7)
+
+	stack := []string{"github.com/acme/store.Get /src/store/store.go:42"}
+	want := &Description{Type: "*errors.withStack", Stack: stack, Wrapped: []Description{{
+		Type: "*errors.withData", Data: map[string]interface{}{"lock_id": 7}, Wrapped: []Description{{
+			Type: "*errors.withMessage", Message: "reading", Wrapped: []Description{{
+				Type: "*errors.multiError", Wrapped: []Description{
+					{Type: "*errors.fundamental", Message: "a", Stack: stack},
+					{Type: "*errors.errorString", Message: "EOF"},
+				},
+			}},
+		}},
+	}}}
+	if got := Describe(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Describe: got %+v, want %+v", got, want)
+	}
+}
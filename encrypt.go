@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+)
+
+// An EncryptedValue stands in a Snapshot for a data value classified
+// ClassSecret that was encrypted with Snapshot.EncryptSecrets.
+type EncryptedValue struct {
+	// Encrypted holds a random nonce followed by the AES-GCM encryption of
+	// the value's JSON encoding, authenticated with its key.
+	Encrypted []byte `json:"encrypted"`
+}
+
+// EncryptSecrets returns a copy of s in which every data value classified
+// ClassSecret (see KeyClassification), including those of nested Snapshots
+// and the global data, is replaced by an EncryptedValue, for errors
+// persisted to queues or databases whose secrets must not be stored in the
+// clear. key is an AES-128, AES-192, or AES-256 key:
+//
+//	snap, err := errors.NewSnapshot(jobErr).EncryptSecrets(key)
+//	...
+//	payload, err := json.Marshal(snap)
+//
+// The process rehydrating the error decrypts the values with DecryptSecrets
+// before calling Err.
+func (s Snapshot) EncryptSecrets(key []byte) (Snapshot, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return Snapshot{}, Wrap(err, "encrypting secrets")
+	}
+	return s.mapSecrets(func(k string, v interface{}) (interface{}, error) {
+		if KeyClassification(k) != ClassSecret {
+			return v, nil
+		}
+		plain, err := json.Marshal(v)
+		if err != nil {
+			return nil, Wrapf(err, "encrypting %q", k)
+		}
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, Wrap(err, "encrypting secrets")
+		}
+		return EncryptedValue{aead.Seal(nonce, nonce, plain, []byte(k))}, nil
+	})
+}
+
+// DecryptSecrets returns a copy of s in which every EncryptedValue set by
+// EncryptSecrets with key, including those decoded from JSON, is replaced by
+// the value it encrypts. Values are restored as encoding/json decodes them
+// into an interface{}; Err converts those of the package's standard keys
+// back to their types.
+func (s Snapshot) DecryptSecrets(key []byte) (Snapshot, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return Snapshot{}, Wrap(err, "decrypting secrets")
+	}
+	return s.mapSecrets(func(k string, v interface{}) (interface{}, error) {
+		enc, ok := encryptedValue(v)
+		if !ok {
+			return v, nil
+		}
+		n := aead.NonceSize()
+		if len(enc.Encrypted) < n {
+			return nil, Errorf("decrypting %q: ciphertext too short", k)
+		}
+		plain, err := aead.Open(nil, enc.Encrypted[:n], enc.Encrypted[n:], []byte(k))
+		if err != nil {
+			return nil, Wrapf(err, "decrypting %q", k)
+		}
+		var dec interface{}
+		if err := json.Unmarshal(plain, &dec); err != nil {
+			return nil, Wrapf(err, "decrypting %q", k)
+		}
+		return dec, nil
+	})
+}
+
+// newAEAD returns AES-GCM keyed with key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedValue returns v as an EncryptedValue, whether it is one or was
+// decoded from the JSON encoding of one, and whether it was.
+func encryptedValue(v interface{}) (EncryptedValue, bool) {
+	switch v := v.(type) {
+	case EncryptedValue:
+		return v, true
+	case map[string]interface{}:
+		if len(v) != 1 {
+			break
+		}
+		if _, ok := v["encrypted"].(string); !ok {
+			break
+		}
+		var enc EncryptedValue
+		if b, err := json.Marshal(v); err == nil && json.Unmarshal(b, &enc) == nil {
+			return enc, true
+		}
+	}
+	return EncryptedValue{}, false
+}
+
+// mapSecrets returns a copy of s with fn applied to every data value,
+// descending into nested Snapshots, including those decoded from JSON (see
+// nestedSnapshot), and into the branches of multi-errors.
+func (s Snapshot) mapSecrets(fn func(key string, v interface{}) (interface{}, error)) (Snapshot, error) {
+	var mapData func(data map[string]interface{}) (map[string]interface{}, error)
+	mapData = func(data map[string]interface{}) (map[string]interface{}, error) {
+		if data == nil {
+			return nil, nil
+		}
+		kv := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			if nested, ok := nestedSnapshot(v); ok {
+				m, err := nested.mapSecrets(fn)
+				if err != nil {
+					return nil, err
+				}
+				v = m
+			}
+			v, err := fn(k, v)
+			if err != nil {
+				return nil, err
+			}
+			kv[k] = v
+		}
+		return kv, nil
+	}
+
+	out := s
+	out.Layers = make([]SnapshotLayer, len(s.Layers))
+	for i, l := range s.Layers {
+		data, err := mapData(l.Data)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		l.Data = data
+		if l.Branches != nil {
+			branches := make([]Snapshot, len(l.Branches))
+			for j, b := range l.Branches {
+				if branches[j], err = b.mapSecrets(fn); err != nil {
+					return Snapshot{}, err
+				}
+			}
+			l.Branches = branches
+		}
+		out.Layers[i] = l
+	}
+	global, err := mapData(s.GlobalData)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	out.GlobalData = global
+	return out, nil
+}
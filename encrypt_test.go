@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncryptSecrets(t *testing.T) {
+	ClassifyKeys(ClassSecret, "encrypt_token", KeyRetryAfter)
+	defer ClassifyKeys(ClassPublic, KeyRetryAfter)
+	key := bytes.Repeat([]byte{7}, 32)
+
+	cause := WithData(New("denied"), "encrypt_token", "nested-s3cr3t")
+	err := WithRetryAfter(WrapWithData(io.EOF, "calling", "encrypt_token", "s3cr3t", "host", "db1", "cause", cause), time.Second)
+
+	enc, encErr := NewSnapshot(err).EncryptSecrets(key)
+	if encErr != nil {
+		t.Fatal(encErr)
+	}
+	buf, jerr := json.Marshal(enc)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	for _, secret := range []string{"s3cr3t", "nested-s3cr3t"} {
+		if bytes.Contains(buf, []byte(secret)) {
+			t.Errorf("serialized snapshot holds %q: %s", secret, buf)
+		}
+	}
+	if !bytes.Contains(buf, []byte("db1")) {
+		t.Errorf("serialized snapshot lost the internal data: %s", buf)
+	}
+
+	var snap Snapshot
+	if jerr := json.Unmarshal(buf, &snap); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if _, decErr := snap.DecryptSecrets(bytes.Repeat([]byte{8}, 32)); decErr == nil {
+		t.Error("DecryptSecrets with the wrong key: want an error")
+	}
+	dec, decErr := snap.DecryptSecrets(key)
+	if decErr != nil {
+		t.Fatal(decErr)
+	}
+	got := dec.Err()
+	if v, _ := GetValue(got, "encrypt_token"); v != "s3cr3t" {
+		t.Errorf("encrypt_token: got %v, want s3cr3t", v)
+	}
+	if d, ok := RetryAfter(got); !ok || d != time.Second {
+		t.Errorf("RetryAfter: got %v, %v, want 1s", d, ok)
+	}
+	nested, _ := GetValue(got, "cause")
+	if v, _ := GetValue(nested.(error), "encrypt_token"); v != "nested-s3cr3t" {
+		t.Errorf("nested encrypt_token: got %v, want nested-s3cr3t", v)
+	}
+
+	if _, encErr := NewSnapshot(err).EncryptSecrets([]byte("short")); encErr == nil {
+		t.Error("EncryptSecrets with an invalid key: want an error")
+	}
+}
+
+func TestEncryptSecretsLayersField(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	page := map[string]interface{}{"layers": []interface{}{map[string]interface{}{"name": "base"}}}
+
+	var snap Snapshot
+	b, _ := json.Marshal(NewSnapshot(WithData(New("rendering"), "page", page)))
+	if jerr := json.Unmarshal(b, &snap); jerr != nil {
+		t.Fatal(jerr)
+	}
+	enc, encErr := snap.EncryptSecrets(key)
+	if encErr != nil {
+		t.Fatal(encErr)
+	}
+	if v := enc.Layers[0].Data["page"]; !reflect.DeepEqual(v, page) {
+		t.Errorf("page: got %#v, want the map unchanged", v)
+	}
+}
@@ -0,0 +1,51 @@
+package errors
+
+import "context"
+
+// KeyStage is the data key under which SendErr records the name of the
+// pipeline stage that produced an error.
+const KeyStage = "stage"
+
+// Envelope carries either a value or an error between the stages of a
+// channel-based pipeline.
+type Envelope[T any] struct {
+	Value T
+	Err   error
+}
+
+// SendErr sends an Envelope holding err on ch, with err annotated with a
+// stack trace at the point SendErr is called and with the name of the
+// sending stage under KeyStage. It gives up and returns false if ctx is
+// done before the send completes.
+// If err is nil, SendErr sends nothing and returns true.
+func SendErr[T any](ctx context.Context, ch chan<- Envelope[T], stage string, err error) bool {
+	if err == nil {
+		return true
+	}
+	err = attachData(err, []interface{}{KeyStage, stage})
+	err = runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+	select {
+	case ch <- Envelope[T]{Err: err}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Collect receives from ch until it is closed and returns the values
+// received, in order, and the errors received, combined with Join.
+func Collect[T any](ch <-chan Envelope[T]) ([]T, error) {
+	var vals []T
+	var errs []error
+	for e := range ch {
+		if e.Err != nil {
+			errs = append(errs, e.Err)
+			continue
+		}
+		vals = append(vals, e.Value)
+	}
+	return vals, Join(errs...)
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func parseStage(ctx context.Context, in []string, out chan<- Envelope[int]) {
+	defer close(out)
+	for _, s := range in {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			SendErr(ctx, out, "parse", err)
+			continue
+		}
+		out <- Envelope[int]{Value: n}
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	ch := make(chan Envelope[int])
+	go parseStage(context.Background(), []string{"1", "x", "3"}, ch)
+
+	vals, err := Collect(ch)
+	if !reflect.DeepEqual(vals, []int{1, 3}) {
+		t.Errorf("Collect: got %v, want [1 3]", vals)
+	}
+	if v, _ := GetValue(err, KeyStage); v != "parse" {
+		t.Errorf("Collect: stage %v, want parse", v)
+	}
+	var numErr *strconv.NumError
+	if !As(err, &numErr) {
+		t.Errorf("Collect: got %v, want the *strconv.NumError kept", err)
+	}
+	var ws *withStack
+	if !As(err, &ws) || fmt.Sprintf("%n", ws.StackTrace()[0]) != "parseStage" {
+		t.Errorf("SendErr: want a stack starting in parseStage")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if SendErr(ctx, make(chan Envelope[int]), "parse", io.EOF) {
+		t.Error("SendErr: want false once ctx is done")
+	}
+	if !SendErr[int](ctx, nil, "parse", nil) {
+		t.Error("SendErr(nil): want true")
+	}
+	done := make(chan Envelope[int])
+	close(done)
+	if vals, err := Collect(done); vals != nil || err != nil {
+		t.Errorf("Collect(empty): got %v, %v", vals, err)
+	}
+}
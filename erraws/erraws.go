@@ -0,0 +1,184 @@
+// Package erraws annotates errors returned by the AWS SDKs with the service
+// error code, request ID, and HTTP status, and classifies them with an
+// errors.Kind.
+//
+// Both aws-sdk-go-v2 (smithy-go) and aws-sdk-go (awserr) errors are
+// recognized through the methods they expose, so this package does not
+// depend on either SDK.
+package erraws
+
+import (
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// Keys under which Annotate records AWS error details.
+const (
+	KeyCode      = "aws_code"
+	KeyMessage   = "aws_message"
+	KeyRequestID = "aws_request_id"
+	KeyStatus    = "aws_status"
+	KeyService   = "aws_service"
+	KeyOperation = "aws_operation"
+)
+
+// apiError is implemented by smithy-go's APIError.
+type apiError interface {
+	error
+	ErrorCode() string
+	ErrorMessage() string
+}
+
+// awsError is implemented by aws-sdk-go's awserr.Error.
+type awsError interface {
+	error
+	Code() string
+	Message() string
+}
+
+// statusError is implemented by smithy-go's http.ResponseError.
+type statusError interface {
+	error
+	HTTPStatusCode() int
+}
+
+// requestFailure is implemented by aws-sdk-go's awserr.RequestFailure.
+type requestFailure interface {
+	error
+	StatusCode() int
+	RequestID() string
+}
+
+// serviceRequestIDer is implemented by aws-sdk-go-v2's http.ResponseError.
+type serviceRequestIDer interface {
+	error
+	ServiceRequestID() string
+}
+
+// operationError is implemented by smithy-go's OperationError.
+type operationError interface {
+	error
+	Service() string
+	Operation() string
+}
+
+// Annotate annotates err with the details of any AWS SDK error in its chain
+// and with the Kind derived from the AWS error code or, failing that, the
+// HTTP status. Errors without AWS details are returned unchanged.
+// If err is nil, Annotate returns nil.
+func Annotate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var keyVals []interface{}
+	var code string
+	var status int
+
+	var api apiError
+	var legacy awsError
+	if errors.As(err, &api) {
+		code = api.ErrorCode()
+		keyVals = append(keyVals, KeyCode, code, KeyMessage, api.ErrorMessage())
+	} else if errors.As(err, &legacy) {
+		code = legacy.Code()
+		keyVals = append(keyVals, KeyCode, code, KeyMessage, legacy.Message())
+	}
+
+	var se statusError
+	var rf requestFailure
+	var rid serviceRequestIDer
+	if errors.As(err, &se) {
+		status = se.HTTPStatusCode()
+	}
+	if errors.As(err, &rf) {
+		if status == 0 {
+			status = rf.StatusCode()
+		}
+		keyVals = append(keyVals, KeyRequestID, rf.RequestID())
+	} else if errors.As(err, &rid) {
+		keyVals = append(keyVals, KeyRequestID, rid.ServiceRequestID())
+	}
+	if status != 0 {
+		keyVals = append(keyVals, KeyStatus, status)
+	}
+
+	var op operationError
+	if errors.As(err, &op) {
+		keyVals = append(keyVals, KeyService, op.Service(), KeyOperation, op.Operation())
+	}
+
+	if len(keyVals) == 0 {
+		return err
+	}
+	if k := Kind(code, status); k != errors.KindUnknown {
+		keyVals = append(keyVals, errors.KeyKind, k)
+	}
+	return errors.WithData(err, keyVals...)
+}
+
+// Kind returns the errors.Kind for an AWS error code, falling back to the
+// HTTP status when the code is not recognized.
+func Kind(code string, status int) errors.Kind {
+	if k, ok := codeKinds[code]; ok {
+		return k
+	}
+	return errors.KindFromHTTPStatus(status)
+}
+
+// codeKinds maps AWS error codes shared across services to Kinds.
+var codeKinds = map[string]errors.Kind{
+	"ValidationException":       errors.KindInvalid,
+	"ValidationError":           errors.KindInvalid,
+	"InvalidParameterException": errors.KindInvalid,
+	"InvalidParameterValue":     errors.KindInvalid,
+	"InvalidInput":              errors.KindInvalid,
+	"MalformedPolicyDocument":   errors.KindInvalid,
+
+	"ResourceNotFoundException": errors.KindNotFound,
+	"NotFound":                  errors.KindNotFound,
+	"NoSuchKey":                 errors.KindNotFound,
+	"NoSuchBucket":              errors.KindNotFound,
+	"NoSuchEntity":              errors.KindNotFound,
+
+	"ConditionalCheckFailedException": errors.KindConflict,
+	"TransactionConflictException":    errors.KindConflict,
+	"ConflictException":               errors.KindConflict,
+	"ResourceInUseException":          errors.KindConflict,
+	"ResourceConflictException":       errors.KindConflict,
+	"BucketAlreadyExists":             errors.KindConflict,
+	"EntityAlreadyExists":             errors.KindConflict,
+
+	"UnrecognizedClientException": errors.KindUnauthenticated,
+	"InvalidClientTokenId":        errors.KindUnauthenticated,
+	"InvalidSignatureException":   errors.KindUnauthenticated,
+	"SignatureDoesNotMatch":       errors.KindUnauthenticated,
+	"ExpiredToken":                errors.KindUnauthenticated,
+	"ExpiredTokenException":       errors.KindUnauthenticated,
+	"MissingAuthenticationToken":  errors.KindUnauthenticated,
+
+	"AccessDenied":          errors.KindPermission,
+	"AccessDeniedException": errors.KindPermission,
+	"UnauthorizedOperation": errors.KindPermission,
+
+	"Throttling":                             errors.KindRateLimited,
+	"ThrottlingException":                    errors.KindRateLimited,
+	"ThrottledException":                     errors.KindRateLimited,
+	"TooManyRequestsException":               errors.KindRateLimited,
+	"RequestLimitExceeded":                   errors.KindRateLimited,
+	"RequestThrottled":                       errors.KindRateLimited,
+	"RequestThrottledException":              errors.KindRateLimited,
+	"ProvisionedThroughputExceededException": errors.KindRateLimited,
+	"SlowDown":                               errors.KindRateLimited,
+
+	"RequestTimeout":          errors.KindTimeout,
+	"RequestTimeoutException": errors.KindTimeout,
+
+	"RequestCanceled": errors.KindCanceled,
+
+	"ServiceUnavailable":          errors.KindUnavailable,
+	"ServiceUnavailableException": errors.KindUnavailable,
+
+	"InternalFailure":     errors.KindInternal,
+	"InternalError":       errors.KindInternal,
+	"InternalServerError": errors.KindInternal,
+}
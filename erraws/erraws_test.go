@@ -0,0 +1,124 @@
+package erraws
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// smithyAPIError mimics smithy.GenericAPIError.
+type smithyAPIError struct{ code, msg string }
+
+func (e *smithyAPIError) Error() string        { return e.code + ": " + e.msg }
+func (e *smithyAPIError) ErrorCode() string    { return e.code }
+func (e *smithyAPIError) ErrorMessage() string { return e.msg }
+
+// smithyResponseError mimics awshttp.ResponseError.
+type smithyResponseError struct {
+	status int
+	id     string
+	err    error
+}
+
+func (e *smithyResponseError) Error() string            { return e.err.Error() }
+func (e *smithyResponseError) Unwrap() error            { return e.err }
+func (e *smithyResponseError) HTTPStatusCode() int      { return e.status }
+func (e *smithyResponseError) ServiceRequestID() string { return e.id }
+
+// smithyOperationError mimics smithy.OperationError.
+type smithyOperationError struct {
+	service, op string
+	err         error
+}
+
+func (e *smithyOperationError) Error() string     { return e.service + " " + e.op + ": " + e.err.Error() }
+func (e *smithyOperationError) Unwrap() error     { return e.err }
+func (e *smithyOperationError) Service() string   { return e.service }
+func (e *smithyOperationError) Operation() string { return e.op }
+
+// awserrRequestFailure mimics awserr.RequestFailure.
+type awserrRequestFailure struct {
+	code, msg, id string
+	status        int
+}
+
+func (e *awserrRequestFailure) Error() string     { return e.code + ": " + e.msg }
+func (e *awserrRequestFailure) Code() string      { return e.code }
+func (e *awserrRequestFailure) Message() string   { return e.msg }
+func (e *awserrRequestFailure) OrigErr() error    { return nil }
+func (e *awserrRequestFailure) StatusCode() int   { return e.status }
+func (e *awserrRequestFailure) RequestID() string { return e.id }
+
+func TestAnnotateNil(t *testing.T) {
+	if got := Annotate(nil); got != nil {
+		t.Errorf("Annotate(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	v2 := &smithyOperationError{"DynamoDB", "PutItem",
+		&smithyResponseError{400, "req-1",
+			&smithyAPIError{"ConditionalCheckFailedException", "The conditional request failed"}}}
+	v1 := &awserrRequestFailure{"SomethingNew", "boom", "req-2", 503}
+
+	tests := []struct {
+		err        error
+		wantedKind errors.Kind
+		wantedData map[string]interface{}
+	}{{
+		io.EOF,
+		errors.KindUnknown,
+		nil,
+	}, {
+		errors.Wrap(v2, "saving lock"),
+		errors.KindConflict,
+		map[string]interface{}{
+			KeyCode:        "ConditionalCheckFailedException",
+			KeyMessage:     "The conditional request failed",
+			KeyRequestID:   "req-1",
+			KeyStatus:      400,
+			KeyService:     "DynamoDB",
+			KeyOperation:   "PutItem",
+			errors.KeyKind: errors.KindConflict,
+		},
+	}, {
+		v1,
+		errors.KindUnavailable,
+		map[string]interface{}{
+			KeyCode:        "SomethingNew",
+			KeyMessage:     "boom",
+			KeyRequestID:   "req-2",
+			KeyStatus:      503,
+			errors.KeyKind: errors.KindUnavailable,
+		},
+	}}
+
+	type dataCacher interface {
+		DataCache() map[string]interface{}
+	}
+
+	for i, tt := range tests {
+		err := Annotate(tt.err)
+		if got := errors.KindOf(err); got != tt.wantedKind {
+			t.Errorf("test %d: KindOf(Annotate(%v)): got %v, want %v", i+1, tt.err, got, tt.wantedKind)
+		}
+		if err.Error() != tt.err.Error() {
+			t.Errorf("test %d: Annotate(%v).Error(): got %q, want %q", i+1, tt.err, err.Error(), tt.err.Error())
+		}
+		var d dataCacher
+		if tt.wantedData == nil {
+			if errors.As(err, &d) {
+				t.Errorf("test %d: Annotate(%v): unexpected data %v", i+1, tt.err, d.DataCache())
+			}
+			continue
+		}
+		if !errors.As(err, &d) {
+			t.Fatalf("test %d: Annotate(%v): not a dataCacher %v", i+1, tt.err, reflect.TypeOf(err))
+		}
+		if kv := d.DataCache(); !reflect.DeepEqual(kv, tt.wantedData) {
+			t.Errorf("test %d: Annotate(%v): got %v, want %v", i+1, tt.err, kv, tt.wantedData)
+		}
+	}
+}
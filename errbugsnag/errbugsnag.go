@@ -0,0 +1,153 @@
+// Package errbugsnag converts errors into Bugsnag error events.
+//
+// Each error in the chain that recorded a stack trace becomes an exception
+// of the event, the outermost first followed by its causes, carrying the
+// message of the chain at that point. Key/value pairs become the event's
+// metadata. The resulting Notice can be posted as JSON to Bugsnag's notify
+// endpoint.
+package errbugsnag
+
+import (
+	"fmt"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// MetaDataTab is the metadata tab under which key/value pairs are reported.
+var MetaDataTab = "error data"
+
+// MaxClassification is the most sensitive data sent to Bugsnag: data
+// classified above it is removed with errors.LimitData before an event is
+// built.
+var MaxClassification = errors.ClassInternal
+
+// ProjectPackages lists the package path prefixes whose frames are marked
+// as in-project. If empty, every frame outside the Go runtime and standard
+// library is in-project.
+var ProjectPackages []string
+
+// Notice is the body of a request to Bugsnag's notify endpoint.
+type Notice struct {
+	APIKey         string   `json:"apiKey"`
+	PayloadVersion string   `json:"payloadVersion"`
+	Notifier       Notifier `json:"notifier"`
+	Events         []Event  `json:"events"`
+}
+
+// Notifier identifies the library that sent a Notice.
+type Notifier struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// Event describes a single error occurrence.
+type Event struct {
+	Exceptions   []Exception                       `json:"exceptions"`
+	Severity     string                            `json:"severity"`
+	Unhandled    bool                              `json:"unhandled"`
+	Context      string                            `json:"context,omitempty"`
+	GroupingHash string                            `json:"groupingHash,omitempty"`
+	App          *App                              `json:"app,omitempty"`
+	MetaData     map[string]map[string]interface{} `json:"metaData,omitempty"`
+}
+
+// App describes the application that produced an Event.
+type App struct {
+	ReleaseStage string `json:"releaseStage,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// Exception is one error of an Event.
+type Exception struct {
+	ErrorClass string       `json:"errorClass"`
+	Message    string       `json:"message"`
+	Type       string       `json:"type"`
+	Stacktrace []StackFrame `json:"stacktrace"`
+}
+
+// StackFrame is a stack frame; Bugsnag expects the innermost frame first.
+type StackFrame struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"lineNumber"`
+	Method     string `json:"method"`
+	InProject  bool   `json:"inProject,omitempty"`
+}
+
+// NewNotice returns the Notice reporting err.
+func NewNotice(apiKey string, err error) Notice {
+	return Notice{
+		APIKey:         apiKey,
+		PayloadVersion: "5",
+		Notifier: Notifier{
+			Name:    "lib_errors",
+			Version: "1",
+			URL:     "https://github.com/noke-inc/lib_errors",
+		},
+		Events: []Event{NewEvent(err)},
+	}
+}
+
+// NewEvent returns the Bugsnag event describing err at severity "error".
+func NewEvent(err error) Event {
+	err = errors.LimitData(err, MaxClassification)
+	e := Event{
+		Severity:     "error",
+		GroupingHash: errors.Fingerprint(err),
+	}
+	if data := errors.LimitedData(err, MaxClassification); len(data) > 0 {
+		e.MetaData = map[string]map[string]interface{}{MetaDataTab: data}
+	}
+	class := fmt.Sprintf("%T", errors.Cause(err))
+	for _, l := range errors.Layers(err) {
+		if len(l.Stack) == 0 {
+			continue
+		}
+		e.Exceptions = append(e.Exceptions, Exception{
+			ErrorClass: class,
+			Message:    l.Err.Error(),
+			Type:       "go",
+			Stacktrace: stacktrace(l.Stack),
+		})
+	}
+	if len(e.Exceptions) == 0 {
+		e.Exceptions = []Exception{{
+			ErrorClass: class,
+			Message:    err.Error(),
+			Type:       "go",
+			Stacktrace: []StackFrame{},
+		}}
+	}
+	return e
+}
+
+// stacktrace converts st into Bugsnag stack frames.
+func stacktrace(st errors.StackTrace) []StackFrame {
+	fs := make([]StackFrame, len(st))
+	for i, f := range st {
+		fn, file, line := f.Location()
+		fs[i] = StackFrame{File: file, LineNumber: line, Method: fn, InProject: inProject(fn)}
+	}
+	return fs
+}
+
+// inProject reports whether the function fn belongs to the project.
+func inProject(fn string) bool {
+	if len(ProjectPackages) == 0 {
+		// standard library packages have no dot in their first path element
+		pkg := fn
+		slash := strings.LastIndex(pkg, "/")
+		if i := strings.Index(pkg[slash+1:], "."); i >= 0 {
+			pkg = pkg[:slash+1+i]
+		}
+		first := strings.SplitN(pkg, "/", 2)[0]
+		return strings.Contains(first, ".") || pkg == "main"
+	}
+	for _, p := range ProjectPackages {
+		if strings.HasPrefix(fn, p) {
+			return true
+		}
+	}
+	return false
+}
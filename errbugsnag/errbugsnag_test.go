@@ -0,0 +1,63 @@
+package errbugsnag
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestNewEvent(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassSecret, "bugsnag_pin")
+	err := errors.WrapWithData(errors.New("lock offline"), "reading state", "lock_id", 7, "bugsnag_pin", "1234")
+	e := NewEvent(err)
+
+	if len(e.Exceptions) != 2 {
+		t.Fatalf("Exceptions: got %d, want 2", len(e.Exceptions))
+	}
+	if got := e.Exceptions[0].Message; got != "reading state: lock offline" {
+		t.Errorf("Exceptions[0].Message: got %q", got)
+	}
+	if got := e.Exceptions[1].Message; got != "lock offline" {
+		t.Errorf("Exceptions[1].Message: got %q", got)
+	}
+	top := e.Exceptions[0].Stacktrace[0]
+	if !strings.HasSuffix(top.Method, "errbugsnag.TestNewEvent") || !top.InProject || !strings.HasSuffix(top.File, "errbugsnag_test.go") {
+		t.Errorf("Stacktrace[0]: got %+v", top)
+	}
+	if _, ok := e.MetaData[MetaDataTab]["bugsnag_pin"]; ok || e.MetaData[MetaDataTab]["lock_id"] != 7 {
+		t.Errorf("MetaData: got %v", e.MetaData)
+	}
+	if e.GroupingHash != errors.Fingerprint(err) {
+		t.Errorf("GroupingHash: got %q, want %q", e.GroupingHash, errors.Fingerprint(err))
+	}
+}
+
+func TestNewEventWithoutStack(t *testing.T) {
+	e := NewEvent(io.EOF)
+	if len(e.Exceptions) != 1 || e.Exceptions[0].Message != "EOF" || e.Exceptions[0].ErrorClass != "*errors.errorString" {
+		t.Errorf("Exceptions: got %+v", e.Exceptions)
+	}
+	if e.MetaData != nil {
+		t.Errorf("MetaData: got %v, want nil", e.MetaData)
+	}
+}
+
+func TestInProject(t *testing.T) {
+	tests := []struct {
+		fn   string
+		want bool
+	}{
+		{"runtime.goexit", false},
+		{"net/http.(*conn).serve", false},
+		{"main.main", true},
+		{"github.com/noke-inc/lockd/store.(*DB).Get", true},
+	}
+
+	for _, tt := range tests {
+		if got := inProject(tt.fn); got != tt.want {
+			t.Errorf("inProject(%q): got %v, want %v", tt.fn, got, tt.want)
+		}
+	}
+}
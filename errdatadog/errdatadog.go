@@ -0,0 +1,77 @@
+// Package errdatadog exposes errors as the error.kind, error.message, and
+// error.stack attributes used by Datadog APM spans and log error tracking.
+package errdatadog
+
+import (
+	"fmt"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// MaxClassification is the most sensitive data that attributes may be
+// derived from: data classified above it, such as a code classified
+// errors.ClassSecret, is removed with errors.LimitData first.
+var MaxClassification = errors.ClassInternal
+
+// The attribute names defined by Datadog's error tracking conventions.
+const (
+	KeyKind    = "error.kind"
+	KeyMessage = "error.message"
+	KeyStack   = "error.stack"
+)
+
+// Attributes holds the Datadog error attributes of an error.
+type Attributes struct {
+	// Kind is the error's code if it has one, otherwise its Kind if it was
+	// classified, otherwise the type of its root cause.
+	Kind string
+	// Message is the error's full message.
+	Message string
+	// Stack is the origin stack trace: the one recorded deepest in the
+	// chain, closest to where the failure happened.
+	Stack string
+}
+
+// NewAttributes returns the Datadog error attributes of err.
+func NewAttributes(err error) Attributes {
+	if err == nil {
+		return Attributes{}
+	}
+	err = errors.LimitData(err, MaxClassification)
+	a := Attributes{Message: err.Error()}
+	switch {
+	case errors.Code(err) != "":
+		a.Kind = errors.Code(err)
+	case errors.KindOf(err) != errors.KindUnknown:
+		a.Kind = errors.KindOf(err).String()
+	default:
+		a.Kind = fmt.Sprintf("%T", errors.Cause(err))
+	}
+
+	var origin errors.StackTrace
+	for _, l := range errors.Layers(err) {
+		if len(l.Stack) > 0 {
+			origin = l.Stack
+		}
+	}
+	var b strings.Builder
+	for _, f := range origin {
+		fn, file, line := f.Location()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", fn, file, line)
+	}
+	a.Stack = b.String()
+	return a
+}
+
+// Map returns the attributes keyed by their Datadog names, omitting empty
+// values, suitable for span tags or structured log fields.
+func (a Attributes) Map() map[string]string {
+	m := make(map[string]string, 3)
+	for k, v := range map[string]string{KeyKind: a.Kind, KeyMessage: a.Message, KeyStack: a.Stack} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+package errdatadog
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func origin() error {
+	return errors.New("lock offline")
+}
+
+func TestNewAttributes(t *testing.T) {
+	tests := []struct {
+		err       error
+		wantKind  string
+		wantMsg   string
+		wantStack string
+	}{
+		{nil, "", "", ""},
+		{io.EOF, "*errors.errorString", "EOF", ""},
+		{errors.WithKind(errors.Wrap(origin(), "reading"), errors.KindUnavailable), "unavailable", "reading: lock offline", "errdatadog.origin\n\t"},
+		{errors.WithCode(errors.WithKind(io.EOF, errors.KindUnavailable), "lock_offline"), "lock_offline", "EOF", ""},
+	}
+
+	for i, tt := range tests {
+		a := NewAttributes(tt.err)
+		if a.Kind != tt.wantKind || a.Message != tt.wantMsg {
+			t.Errorf("test %d: got kind %q message %q, want %q %q", i+1, a.Kind, a.Message, tt.wantKind, tt.wantMsg)
+		}
+		if tt.wantStack == "" && a.Stack != "" || !strings.Contains(a.Stack, tt.wantStack) {
+			t.Errorf("test %d: got stack %q, want it to contain %q", i+1, a.Stack, tt.wantStack)
+		}
+		if tt.wantStack != "" && !strings.HasPrefix(a.Stack, "github.com/noke-inc/lib_errors/errdatadog.origin\n") {
+			t.Errorf("test %d: stack does not start at the origin: %q", i+1, a.Stack)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := NewAttributes(io.EOF).Map()
+	if len(m) != 2 || m[KeyKind] != "*errors.errorString" || m[KeyMessage] != "EOF" {
+		t.Errorf("Map: got %v", m)
+	}
+}
@@ -0,0 +1,168 @@
+// Package errdebug exposes the errors a process has seen for debugging: a
+// Recorder keeps the most recent errors and per-code counters, Handler
+// serves them along with the package configuration over HTTP, and Publish
+// makes the counters available through expvar.
+//
+// A typical setup registers a Recorder and mounts its handler next to the
+// other debug endpoints:
+//
+//	rec := errdebug.NewRecorder(100)
+//	errors.RegisterReporter(rec)
+//	rec.Publish("errors")
+//	http.Handle("/debug/errors", errdebug.Handler(rec))
+package errdebug
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// MaxClassification is the most sensitive data Recorders keep: data
+// classified above it is removed with errors.LimitData before an error is
+// recorded, so that it is never served by Handler.
+var MaxClassification = errors.ClassInternal
+
+// Uncoded is the counter incremented for errors without an application code.
+const Uncoded = "uncoded"
+
+// Entry describes an error recorded by a Recorder.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Code        string    `json:"code,omitempty"`
+	Kind        string    `json:"kind"`
+	Fingerprint string    `json:"fingerprint"`
+	Message     string    `json:"message"`
+	// Detail is the error formatted with %+v.
+	Detail string `json:"detail"`
+}
+
+// A Recorder keeps the most recent errors it is given and counts errors per
+// application code. It implements errors.Reporter so that it can be
+// registered with errors.RegisterReporter.
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	recent []Entry // ring buffer, next holds the oldest entry once full
+	next   int
+	full   bool
+	counts map[string]uint64
+}
+
+// NewRecorder returns a Recorder keeping the size most recent errors.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	return &Recorder{
+		recent: make([]Entry, size),
+		counts: make(map[string]uint64),
+	}
+}
+
+// Report records err.
+// If err is nil, Report does nothing.
+func (rec *Recorder) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	err = errors.LimitData(err, MaxClassification)
+	e := Entry{
+		Time:        errors.Now(),
+		Code:        errors.Code(err),
+		Kind:        errors.KindOf(err).String(),
+		Fingerprint: errors.Fingerprint(err),
+		Message:     err.Error(),
+		Detail:      fmt.Sprintf("%+v", err),
+	}
+	code := e.Code
+	if code == "" {
+		code = Uncoded
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.recent[rec.next] = e
+	rec.next++
+	if rec.next == len(rec.recent) {
+		rec.next = 0
+		rec.full = true
+	}
+	rec.counts[code]++
+}
+
+// Recent returns the recorded errors, most recent first.
+func (rec *Recorder) Recent() []Entry {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	n := rec.next
+	if rec.full {
+		n = len(rec.recent)
+	}
+	entries := make([]Entry, 0, n)
+	for i := 1; i <= n; i++ {
+		entries = append(entries, rec.recent[(rec.next-i+len(rec.recent))%len(rec.recent)])
+	}
+	return entries
+}
+
+// Counts returns the number of errors recorded per application code. Errors
+// without a code are counted under Uncoded.
+func (rec *Recorder) Counts() map[string]uint64 {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	counts := make(map[string]uint64, len(rec.counts))
+	for code, n := range rec.counts {
+		counts[code] = n
+	}
+	return counts
+}
+
+// Publish publishes the per-code counters as the expvar variable name.
+// Like expvar.Publish, it panics if name is already in use.
+func (rec *Recorder) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} { return rec.Counts() }))
+}
+
+// Config is the package configuration reported by Handler.
+type Config struct {
+	CaptureStacks     bool `json:"capture_stacks"`
+	CaptureTimestamps bool `json:"capture_timestamps"`
+}
+
+// CurrentConfig returns the current package configuration.
+func CurrentConfig() Config {
+	return Config{
+		CaptureStacks:     errors.StackCaptureEnabled(),
+		CaptureTimestamps: errors.TimestampCaptureEnabled(),
+	}
+}
+
+// Page is the JSON document served by Handler.
+type Page struct {
+	Config Config            `json:"config"`
+	Counts map[string]uint64 `json:"counts"`
+	Recent []Entry           `json:"recent"`
+}
+
+// Handler returns an http.Handler serving rec's counters and recent errors,
+// along with the package configuration, as a JSON Page.
+func Handler(rec *Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := Page{
+			Config: CurrentConfig(),
+			Counts: rec.Counts(),
+			Recent: rec.Recent(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(page)
+	})
+}
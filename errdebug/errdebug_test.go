@@ -0,0 +1,61 @@
+package errdebug
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(2)
+	ctx := context.Background()
+	rec.Report(ctx, nil)
+	rec.Report(ctx, errors.New("first"))
+	rec.Report(ctx, errors.WithCode(errors.New("second"), "lock_jammed"))
+	rec.Report(ctx, errors.WithCode(errors.New("third"), "lock_jammed"))
+
+	var msgs []string
+	for _, e := range rec.Recent() {
+		msgs = append(msgs, e.Message)
+	}
+	if want := []string{"third", "second"}; !reflect.DeepEqual(msgs, want) {
+		t.Errorf("Recent: got %v, want %v", msgs, want)
+	}
+	wantCounts := map[string]uint64{Uncoded: 1, "lock_jammed": 2}
+	if got := rec.Counts(); !reflect.DeepEqual(got, wantCounts) {
+		t.Errorf("Counts: got %v, want %v", got, wantCounts)
+	}
+
+	rec.Publish("errdebug_test")
+	var published map[string]uint64
+	if err := json.Unmarshal([]byte(expvar.Get("errdebug_test").String()), &published); err != nil || !reflect.DeepEqual(published, wantCounts) {
+		t.Errorf("expvar: got %v, %v", published, err)
+	}
+
+	w := httptest.NewRecorder()
+	Handler(rec).ServeHTTP(w, httptest.NewRequest("GET", "/debug/errors", nil))
+	var page Page
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decoding page: %v", err)
+	}
+	if !page.Config.CaptureStacks || len(page.Recent) != 2 || page.Recent[0].Code != "lock_jammed" || page.Counts["lock_jammed"] != 2 {
+		t.Errorf("Handler: got %+v", page)
+	}
+}
+
+func TestRecorderLimitsData(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassSecret, "errdebug_token")
+	rec := NewRecorder(1)
+	rec.Report(context.Background(), errors.WithData(errors.New("denied"), "errdebug_token", "s3cr3t", "user", "ann"))
+
+	detail := rec.Recent()[0].Detail
+	if strings.Contains(detail, "s3cr3t") || !strings.Contains(detail, "user:ann") {
+		t.Errorf("Detail: got %q, want secret data removed", detail)
+	}
+}
@@ -0,0 +1,114 @@
+// Package errdevice turns the status bytes reported by lock firmware and BLE
+// peripherals into descriptive errors.
+//
+// Each firmware status is registered once with its name, description, and
+// Kind:
+//
+//	func init() {
+//	        errdevice.Register(0x12, errdevice.Status{
+//	                Name:        "motor_stall",
+//	                Description: "the bolt motor stalled",
+//	                Kind:        errors.KindUnavailable,
+//	        })
+//	}
+//
+// after which FromDeviceCode("unlock", b) returns an error reading
+// "unlock: the bolt motor stalled (0x12)" whose code is "motor_stall".
+package errdevice
+
+import (
+	"fmt"
+	"sync"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// The data keys recorded by FromDeviceCode.
+const (
+	KeyOp          = "device_op"
+	KeyStatus      = "device_status"
+	KeyDescription = "device_description"
+)
+
+// SuccessCode is the status byte that FromDeviceCode treats as success.
+var SuccessCode byte = 0x00
+
+// Status describes a status byte.
+type Status struct {
+	// Name identifies the status; it is recorded as the error's code.
+	Name string
+	// Description explains the status to people.
+	Description string
+	// Kind classifies the failure.
+	Kind errors.Kind
+}
+
+// unknownStatus describes status bytes that were never registered.
+var unknownStatus = Status{
+	Name:        "unknown_device_status",
+	Description: "unknown device status",
+	Kind:        errors.KindUnknown,
+}
+
+var registry = struct {
+	sync.RWMutex
+	statuses map[byte]Status
+}{statuses: make(map[byte]Status)}
+
+// Register records the Status reported by the status byte code, replacing
+// any previous registration. Register is meant to be called during program
+// initialization.
+func Register(code byte, s Status) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.statuses[code] = s
+}
+
+// Lookup returns the Status registered for code, and whether there was one.
+func Lookup(code byte) (Status, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	s, ok := registry.statuses[code]
+	return s, ok
+}
+
+// Error is a failure status reported by a device.
+type Error struct {
+	// Op is the operation the device was performing.
+	Op string
+	// Code is the raw status byte.
+	Code byte
+	Status
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (0x%02x)", e.Op, e.Description, e.Code)
+}
+
+// FromDeviceCode returns the error for the status byte code reported by a
+// device performing op, or nil if code is SuccessCode. The error has a stack
+// trace, the registered Status's name as its code and its Kind, and the
+// following key/value pairs:
+//
+//	device_op           op
+//	device_status       the raw status byte
+//	device_description  the Status's description
+//
+// Bytes that were never registered produce an error with the code
+// "unknown_device_status". The *Error can be retrieved with errors.As.
+func FromDeviceCode(op string, code byte) error {
+	if code == SuccessCode {
+		return nil
+	}
+	s, ok := Lookup(code)
+	if !ok {
+		s = unknownStatus
+	}
+	return errors.WithData(errors.WithStack(&Error{Op: op, Code: code, Status: s}),
+		KeyOp, op,
+		KeyStatus, code,
+		KeyDescription, s.Description,
+		errors.KeyCode, s.Name,
+		errors.KeyKind, s.Kind,
+	)
+}
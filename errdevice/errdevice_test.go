@@ -0,0 +1,41 @@
+package errdevice
+
+import (
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestFromDeviceCode(t *testing.T) {
+	Register(0x12, Status{Name: "motor_stall", Description: "the bolt motor stalled", Kind: errors.KindUnavailable})
+
+	if err := FromDeviceCode("unlock", SuccessCode); err != nil {
+		t.Errorf("FromDeviceCode(success): got %v, want nil", err)
+	}
+
+	err := FromDeviceCode("unlock", 0x12)
+	if got, want := err.Error(), "unlock: the bolt motor stalled (0x12)"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if errors.Code(err) != "motor_stall" || errors.KindOf(err) != errors.KindUnavailable {
+		t.Errorf("Code, KindOf: got %q, %v", errors.Code(err), errors.KindOf(err))
+	}
+	want := map[string]interface{}{KeyOp: "unlock", KeyStatus: byte(0x12), KeyDescription: "the bolt motor stalled"}
+	for k, v := range want {
+		if got, _ := errors.GetValue(err, k); got != v {
+			t.Errorf("GetValue(%s): got %#v, want %#v", k, got, v)
+		}
+	}
+	var de *Error
+	if !errors.As(err, &de) || de.Code != 0x12 || de.Name != "motor_stall" {
+		t.Errorf("As: got %+v", de)
+	}
+
+	err = FromDeviceCode("lock", 0xfe)
+	if got, want := err.Error(), "lock: unknown device status (0xfe)"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if errors.Code(err) != "unknown_device_status" {
+		t.Errorf("Code: got %q", errors.Code(err))
+	}
+}
@@ -0,0 +1,42 @@
+// Package errecho renders errors returned by Echo handlers the same way
+// errhttp renders errors for net/http handlers.
+//
+//	e := echo.New()
+//	e.HTTPErrorHandler = errecho.HTTPErrorHandler(nil)
+package errecho
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	errors "github.com/noke-inc/lib_errors"
+	"github.com/noke-inc/lib_errors/errhttp"
+)
+
+// HTTPErrorHandler returns an echo.HTTPErrorHandler that renders errors
+// using rd. An *echo.HTTPError in the chain supplies the HTTP status and, if
+// no user message was recorded, its message as the user message. If a
+// response has already been committed, the error is only logged with
+// rd.LogError. Errors marked with errors.MarkLogged are not logged again.
+// If rd is nil, errhttp.DefaultRenderer is used.
+func HTTPErrorHandler(rd *errhttp.Renderer) echo.HTTPErrorHandler {
+	if rd == nil {
+		rd = errhttp.DefaultRenderer
+	}
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			rd.LogError(c.Request(), err)
+			return
+		}
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			if _, ok := errors.GetValue(err, errors.KeyStatusCode); !ok {
+				err = errors.WithHTTPStatus(err, he.Code)
+			}
+			if errors.UserMessage(err) == "" && he.Message != nil {
+				err = errors.WithUserMessage(err, fmt.Sprint(he.Message))
+			}
+		}
+		rd.Render(c.Response(), c.Request(), err)
+	}
+}
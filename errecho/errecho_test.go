@@ -0,0 +1,66 @@
+package errecho
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	errors "github.com/noke-inc/lib_errors"
+	"github.com/noke-inc/lib_errors/errhttp"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(&errhttp.Renderer{
+		Log:         func(*http.Request, error) {},
+		ProblemJSON: true,
+	})
+	e.GET("/locks/:id", func(c echo.Context) error {
+		return errors.WithCode(errors.WithKind(errors.Wrap(io.EOF, "loading lock"), errors.KindNotFound), "lock_missing")
+	})
+	e.GET("/limited", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "slow down")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/locks/7", http.StatusNotFound, `{"type":"about:blank","title":"Not Found","status":404,"code":"lock_missing"}` + "\n"},
+		{"/limited", http.StatusTooManyRequests, `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"slow down"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantStatus || rec.Body.String() != tt.wantBody {
+			t.Errorf("GET %s: got %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+		}
+	}
+}
+
+func TestHTTPErrorHandlerLogged(t *testing.T) {
+	var logged []string
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler(&errhttp.Renderer{
+		Log: func(_ *http.Request, err error) { logged = append(logged, err.Error()) },
+	})
+	e.GET("/marked", func(c echo.Context) error {
+		return errors.MarkLogged(errors.Wrap(io.EOF, "loading lock"))
+	})
+	e.GET("/written", func(c echo.Context) error {
+		c.String(http.StatusOK, "partial")
+		return errors.Wrap(io.EOF, "streaming locks")
+	})
+
+	for _, path := range []string{"/marked", "/written"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if want := []string{"streaming locks: EOF"}; !reflect.DeepEqual(logged, want) {
+		t.Errorf("logged: got %q, want %q", logged, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Package errgin renders errors recorded on a Gin context the same way
+// errhttp renders errors for net/http handlers.
+//
+//	r := gin.New()
+//	r.Use(errgin.ErrorHandler(nil))
+//	r.GET("/locks/:id", func(c *gin.Context) {
+//	        lock, err := store.Get(c, c.Param("id"))
+//	        if err != nil {
+//	                c.Error(errors.Wrap(err, "loading lock"))
+//	                return
+//	        }
+//	        c.JSON(http.StatusOK, lock)
+//	})
+package errgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/noke-inc/lib_errors/errhttp"
+)
+
+// ErrorHandler returns a middleware that, once the remaining handlers have
+// run, renders the last error recorded with c.Error using rd. If a response
+// has already been written, the error is only logged with rd.LogError.
+// Errors marked with errors.MarkLogged are not logged again.
+// If rd is nil, errhttp.DefaultRenderer is used.
+func ErrorHandler(rd *errhttp.Renderer) gin.HandlerFunc {
+	if rd == nil {
+		rd = errhttp.DefaultRenderer
+	}
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil {
+			return
+		}
+		if c.Writer.Written() {
+			rd.LogError(c.Request, last.Err)
+			return
+		}
+		rd.Render(c.Writer, c.Request, last.Err)
+	}
+}
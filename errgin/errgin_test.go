@@ -0,0 +1,64 @@
+package errgin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	errors "github.com/noke-inc/lib_errors"
+	"github.com/noke-inc/lib_errors/errhttp"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler(&errhttp.Renderer{
+		Log:         func(*http.Request, error) {},
+		ProblemJSON: true,
+	}))
+	r.GET("/locks/:id", func(c *gin.Context) {
+		c.Error(errors.WithCode(errors.WithKind(errors.Wrap(io.EOF, "loading lock"), errors.KindNotFound), "lock_missing"))
+	})
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/locks/7", nil))
+	want := `{"type":"about:blank","title":"Not Found","status":404,"code":"lock_missing"}` + "\n"
+	if rec.Code != http.StatusNotFound || rec.Body.String() != want {
+		t.Errorf("ErrorHandler: got %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusNotFound, want)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("ErrorHandler: got %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusOK, "ok")
+	}
+}
+
+func TestErrorHandlerLogged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logged []string
+	r := gin.New()
+	r.Use(ErrorHandler(&errhttp.Renderer{
+		Log: func(_ *http.Request, err error) { logged = append(logged, err.Error()) },
+	}))
+	r.GET("/marked", func(c *gin.Context) {
+		c.Error(errors.MarkLogged(errors.Wrap(io.EOF, "loading lock")))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		c.Error(errors.Wrap(io.EOF, "streaming locks"))
+	})
+
+	for _, path := range []string{"/marked", "/written"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if want := []string{"streaming locks: EOF"}; !reflect.DeepEqual(logged, want) {
+		t.Errorf("logged: got %q, want %q", logged, want)
+	}
+}
@@ -0,0 +1,369 @@
+// Package errgrpc converts between lib_errors errors and gRPC statuses and
+// provides interceptors that apply the conversion on both ends of a call.
+//
+// On the server, errors returned by handlers are converted to a status whose
+// code is derived from the error's Kind and whose details carry the error's
+// key/value pairs up to MaxClassification. On the client, statuses are
+// converted back into errors with the key/value pairs and Kind restored and
+// a stack trace recorded at the call site. With EmbedSnapshot set, the
+// whole chain travels too.
+package errgrpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain used to recognize details written by
+// ToStatus.
+var Domain = "github.com/noke-inc/lib_errors"
+
+// EmbedSnapshot makes ToStatus embed an errors.Snapshot of the error (its
+// messages, data, and stack traces) in the status details, from which
+// FromStatus rebuilds the chain. Since stack traces reveal the server's
+// internals, it should only be set for services called by trusted clients.
+var EmbedSnapshot = false
+
+// MaxClassification is the most sensitive data ToStatus sends: data
+// classified above it is removed with errors.LimitData.
+var MaxClassification = errors.ClassPublic
+
+// SnapshotCodec is the Codec used to encode the snapshots embedded by
+// ToStatus and whose SigningKey signs the ErrorInfo detail written by
+// ToStatus and is required by FromStatus. If nil, errors.DefaultCodec is
+// used.
+var SnapshotCodec *errors.Codec
+
+// snapshotCodec returns SnapshotCodec, or errors.DefaultCodec if it is nil.
+func snapshotCodec() *errors.Codec {
+	if SnapshotCodec != nil {
+		return SnapshotCodec
+	}
+	return errors.DefaultCodec
+}
+
+// snapshotKey is the ErrorInfo metadata key holding the base64 encoding of
+// an errors.Snapshot embedded with SnapshotCodec.
+const snapshotKey = "lib_errors.snapshot"
+
+// signatureKey is the ErrorInfo metadata key holding the base64 encoding of
+// the signature of the detail (see infoPayload).
+const signatureKey = "lib_errors.signature"
+
+// Log, if set, is called by the server interceptors with every error
+// returned by a handler that was not already logged (see
+// errors.MarkLogged), before it is converted with ToStatus.
+var Log func(ctx context.Context, method string, err error)
+
+// logError calls Log with err unless Log is nil or err was already logged.
+func logError(ctx context.Context, method string, err error) {
+	if Log != nil && !errors.IsLogged(err) {
+		Log(ctx, method, err)
+	}
+}
+
+// KeyMethod is the data key under which the client interceptors record the
+// full gRPC method name.
+const KeyMethod = "grpc_method"
+
+// ToStatus converts err into a gRPC status. Errors that already carry a
+// status (anywhere in their chain) are returned as that status. Otherwise the
+// code is derived from the error's Kind, the message is
+// errors.UserMessage(err) or, failing that, the status text of
+// errors.HTTPStatus(err), and the error's key/value pairs up to
+// MaxClassification are attached as an ErrorInfo detail, signed with the
+// SigningKey of SnapshotCodec if it is set.
+// If err is nil, ToStatus returns nil.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var gs interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &gs) {
+		return gs.GRPCStatus()
+	}
+
+	kind := errors.KindOf(err)
+	switch {
+	case kind != errors.KindUnknown:
+	case errors.Is(err, context.Canceled):
+		kind = errors.KindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		kind = errors.KindTimeout
+	}
+
+	msg := errors.UserMessage(err)
+	if msg == "" {
+		msg = errors.HTTPStatusText(errors.HTTPStatus(err))
+	}
+	st := status.New(Code(kind), msg)
+	info := &errdetails.ErrorInfo{
+		Reason:   strings.ToUpper(kind.String()),
+		Domain:   Domain,
+		Metadata: metadata(err),
+	}
+	codec := snapshotCodec()
+	if EmbedSnapshot {
+		if buf, eerr := codec.Encode(errors.NewSnapshot(err)); eerr == nil {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string)
+			}
+			info.Metadata[snapshotKey] = base64.StdEncoding.EncodeToString(buf)
+		}
+	}
+	if sig := codec.Sign(infoPayload(st.Code(), st.Message(), info.Metadata)); sig != nil {
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]string)
+		}
+		info.Metadata[signatureKey] = base64.StdEncoding.EncodeToString(sig)
+	}
+	if detailed, derr := st.WithDetails(info); derr == nil {
+		st = detailed
+	}
+	return st
+}
+
+// FromStatus converts st into an error whose Kind is derived from the status
+// code and whose key/value pairs are restored from any ErrorInfo detail
+// written by ToStatus. If the detail embeds a snapshot (see EmbedSnapshot),
+// the error wraps the chain rebuilt from it, with the server's messages,
+// data, and stack traces. The returned error still reports st through
+// status.FromError. If the SigningKey of SnapshotCodec is set, details that
+// are unsigned or whose signature does not match are ignored, and only the
+// Kind derived from the status code is restored.
+// If st is nil or OK, FromStatus returns nil.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	err := st.Err()
+	codec := snapshotCodec()
+	var keyVals []interface{}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != Domain {
+			continue
+		}
+		sig, _ := base64.StdEncoding.DecodeString(info.Metadata[signatureKey])
+		if codec.Verify(infoPayload(st.Code(), st.Message(), info.Metadata), sig) != nil {
+			continue
+		}
+		if enc, ok := info.Metadata[snapshotKey]; ok {
+			if snap, ok := decodeSnapshot(codec, enc); ok {
+				err = &remoteError{errors.Base{Err: snap.Err()}, st}
+				continue
+			}
+		}
+		for k, v := range info.Metadata {
+			if k == errors.KeyKind || k == snapshotKey || k == signatureKey {
+				continue
+			}
+			keyVals = append(keyVals, k, v)
+		}
+	}
+	if _, ok := err.(*remoteError); ok {
+		keyVals = nil
+	}
+	keyVals = append(keyVals, errors.KeyKind, Kind(st.Code()))
+	return errors.WithData(err, keyVals...)
+}
+
+// decodeSnapshot decodes a snapshot embedded by ToStatus. Snapshots
+// embedded as plain JSON by earlier versions are accepted as long as codec
+// requires no signature.
+func decodeSnapshot(codec *errors.Codec, enc string) (errors.Snapshot, bool) {
+	buf, derr := base64.StdEncoding.DecodeString(enc)
+	if derr != nil {
+		buf = []byte(enc)
+	}
+	snap, err := codec.Decode(buf)
+	return snap, err == nil && len(snap.Layers) > 0
+}
+
+// infoPayload returns the text covered by the signature of an ErrorInfo
+// detail: the status code and message, and the metadata other than the
+// signature itself, sorted by key.
+func infoPayload(c codes.Code, msg string, md map[string]string) []byte {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		if k != signatureKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\x00%s\x00", c, msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s\x00%s\x00", k, md[k])
+	}
+	return []byte(b.String())
+}
+
+// remoteError is an error chain rebuilt from a snapshot embedded in st.
+type remoteError struct {
+	errors.Base
+	st *status.Status
+}
+
+// GRPCStatus returns the status the error was received as.
+func (e *remoteError) GRPCStatus() *status.Status { return e.st }
+
+// Code returns the gRPC code used for errors of Kind k.
+func Code(k errors.Kind) codes.Code {
+	switch k {
+	case errors.KindInvalid:
+		return codes.InvalidArgument
+	case errors.KindNotFound:
+		return codes.NotFound
+	case errors.KindConflict:
+		return codes.Aborted
+	case errors.KindUnauthenticated:
+		return codes.Unauthenticated
+	case errors.KindPermission:
+		return codes.PermissionDenied
+	case errors.KindRateLimited:
+		return codes.ResourceExhausted
+	case errors.KindTimeout:
+		return codes.DeadlineExceeded
+	case errors.KindCanceled:
+		return codes.Canceled
+	case errors.KindUnavailable:
+		return codes.Unavailable
+	case errors.KindInternal:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// Kind returns the Kind that best describes a status with code c.
+func Kind(c codes.Code) errors.Kind {
+	switch c {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return errors.KindInvalid
+	case codes.NotFound:
+		return errors.KindNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return errors.KindConflict
+	case codes.Unauthenticated:
+		return errors.KindUnauthenticated
+	case codes.PermissionDenied:
+		return errors.KindPermission
+	case codes.ResourceExhausted:
+		return errors.KindRateLimited
+	case codes.DeadlineExceeded:
+		return errors.KindTimeout
+	case codes.Canceled:
+		return errors.KindCanceled
+	case codes.Unavailable:
+		return errors.KindUnavailable
+	case codes.Internal, codes.DataLoss, codes.Unimplemented:
+		return errors.KindInternal
+	}
+	return errors.KindUnknown
+}
+
+// UnaryServerInterceptor returns an interceptor logging errors returned by
+// unary handlers with Log and converting them into statuses using ToStatus.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			logError(ctx, info.FullMethod, err)
+			return resp, ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging errors returned by
+// stream handlers with Log and converting them into statuses using ToStatus.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			logError(ss.Context(), info.FullMethod, err)
+			return ToStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor converting statuses returned
+// by unary calls back into errors using FromStatus, recording the method and
+// a stack trace at the call site.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return fromClientError(err, method)
+		}
+		return nil
+	}
+}
+
+// StreamClientInterceptor returns an interceptor converting statuses returned
+// by streaming calls back into errors using FromStatus, recording the method
+// and a stack trace at the call site.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromClientError(err, method)
+		}
+		return &clientStream{cs, method}, nil
+	}
+}
+
+// clientStream converts the errors reported by a grpc.ClientStream. io.EOF is
+// passed through unchanged since it marks the normal end of a stream.
+type clientStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return fromClientError(err, s.method)
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return fromClientError(err, s.method)
+}
+
+// fromClientError converts an error returned by a client call.
+func fromClientError(err error, method string) error {
+	if st, ok := status.FromError(err); ok {
+		err = FromStatus(st)
+	}
+	return errors.WithStack(errors.WithData(err, KeyMethod, method))
+}
+
+// metadata returns the key/value pairs of err up to MaxClassification as
+// strings.
+func metadata(err error) map[string]string {
+	kv := errors.LimitedData(err, MaxClassification)
+	if len(kv) == 0 {
+		return nil
+	}
+	md := make(map[string]string, len(kv))
+	for k, v := range kv {
+		md[k] = fmt.Sprint(v)
+	}
+	return md
+}
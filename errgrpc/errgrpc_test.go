@@ -0,0 +1,210 @@
+package errgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToStatusNil(t *testing.T) {
+	if got := ToStatus(nil); got != nil {
+		t.Errorf("ToStatus(nil): got %v, expected nil", got)
+	}
+	if got := FromStatus(nil); got != nil {
+		t.Errorf("FromStatus(nil): got %v, expected nil", got)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{io.EOF, codes.Unknown},
+		{errors.WithKind(io.EOF, errors.KindNotFound), codes.NotFound},
+		{errors.Wrap(context.Canceled, "waiting"), codes.Canceled},
+		{errors.Wrap(context.DeadlineExceeded, "waiting"), codes.DeadlineExceeded},
+		{errors.Wrap(status.Error(codes.Unavailable, "down"), "calling"), codes.Unavailable},
+	}
+
+	for i, tt := range tests {
+		if got := ToStatus(tt.err).Code(); got != tt.want {
+			t.Errorf("test %d: ToStatus(%v).Code(): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassPublic, "lock_id")
+	err := errors.WithKind(errors.WrapWithData(io.EOF, "reading lock", "lock_id", 42), errors.KindNotFound)
+
+	got := FromStatus(ToStatus(err))
+	if errors.KindOf(got) != errors.KindNotFound {
+		t.Errorf("KindOf: got %v, want %v", errors.KindOf(got), errors.KindNotFound)
+	}
+	if v, _ := errors.GetValue(got, "lock_id"); v != "42" {
+		t.Errorf("GetValue(lock_id): got %v, want %q", v, "42")
+	}
+	if st, ok := status.FromError(got); !ok || st.Code() != codes.NotFound {
+		t.Errorf("status.FromError: got (%v, %v)", st, ok)
+	}
+}
+
+func TestToStatusPublic(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassSecret, "grpc_password")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.WrapWithData(io.EOF, "reading lock", "grpc_password", "hunter2", "table", "locks"), "Internal Server Error"},
+		{errors.WithUserMessage(errors.WithKind(io.EOF, errors.KindNotFound), "no such lock"), "no such lock"},
+		{errors.WithKind(errors.Wrap(io.EOF, "reading lock"), errors.KindNotFound), "Not Found"},
+	}
+	for _, tt := range tests {
+		st := ToStatus(tt.err)
+		if st.Message() != tt.want {
+			t.Errorf("ToStatus(%v).Message(): got %q, want %q", tt.err, st.Message(), tt.want)
+		}
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		for _, k := range []string{"grpc_password", "table"} {
+			if v, ok := info.Metadata[k]; ok {
+				t.Errorf("ToStatus(%v): metadata %s = %q, want it withheld", tt.err, k, v)
+			}
+		}
+	}
+
+	MaxClassification = errors.ClassInternal
+	defer func() { MaxClassification = errors.ClassPublic }()
+	info := ToStatus(tests[0].err).Details()[0].(*errdetails.ErrorInfo)
+	if _, ok := info.Metadata["grpc_password"]; ok || info.Metadata["table"] != "locks" {
+		t.Errorf("ToStatus with MaxClassification internal: got metadata %v", info.Metadata)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassPublic, "lock_id")
+	server := UnaryServerInterceptor()
+	client := UnaryClientInterceptor()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.WithKind(errors.WrapWithData(io.EOF, "reading lock", "lock_id", 7), errors.KindPermission)
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		_, err := server(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	err := client(context.Background(), "/locks.Locks/Get", nil, nil, nil, invoker)
+	if errors.KindOf(err) != errors.KindPermission {
+		t.Errorf("KindOf: got %v, want %v", errors.KindOf(err), errors.KindPermission)
+	}
+	if v, _ := errors.GetValue(err, "lock_id"); v != "7" {
+		t.Errorf("GetValue(lock_id): got %v, want %q", v, "7")
+	}
+	if v, _ := errors.GetValue(err, KeyMethod); v != "/locks.Locks/Get" {
+		t.Errorf("GetValue(%s): got %v", KeyMethod, v)
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("status.Code: got %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	EmbedSnapshot = true
+	defer func() { EmbedSnapshot = false }()
+
+	origin := errors.New("lock offline")
+	err := errors.WithKind(errors.WrapWithData(origin, "reading lock", "lock_id", 42), errors.KindUnavailable)
+
+	got := FromStatus(ToStatus(err))
+	if got.Error() != err.Error() {
+		t.Errorf("Error: got %q, want %q", got.Error(), err.Error())
+	}
+	if v, _ := errors.GetValue(got, "lock_id"); v != float64(42) {
+		t.Errorf("GetValue(lock_id): got %#v, want 42", v)
+	}
+	if errors.KindOf(got) != errors.KindUnavailable {
+		t.Errorf("KindOf: got %v, want %v", errors.KindOf(got), errors.KindUnavailable)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", got), "TestSnapshotRoundTrip") {
+		t.Errorf("%%+v: server stack trace missing:\n%+v", got)
+	}
+	if st, ok := status.FromError(got); !ok || st.Code() != codes.Unavailable {
+		t.Errorf("status.FromError: got (%v, %v)", st, ok)
+	}
+}
+
+func TestSigning(t *testing.T) {
+	EmbedSnapshot = true
+	SnapshotCodec = &errors.Codec{SigningKey: []byte("secret")}
+	defer func() { EmbedSnapshot, SnapshotCodec = false, nil }()
+
+	err := errors.WithKind(errors.WrapWithData(errors.New("lock offline"), "reading lock", "lock_id", 42), errors.KindUnavailable)
+	st := ToStatus(err)
+
+	got := FromStatus(st)
+	if got.Error() != err.Error() {
+		t.Errorf("signed: Error: got %q, want %q", got.Error(), err.Error())
+	}
+	if v, _ := errors.GetValue(got, "lock_id"); v != float64(42) {
+		t.Errorf("signed: GetValue(lock_id): got %#v, want 42", v)
+	}
+
+	tamper := func(f func(md map[string]string)) *status.Status {
+		info := proto.Clone(st.Details()[0].(*errdetails.ErrorInfo)).(*errdetails.ErrorInfo)
+		f(info.Metadata)
+		tampered, _ := status.New(st.Code(), st.Message()).WithDetails(info)
+		return tampered
+	}
+	tests := []struct {
+		name string
+		st   *status.Status
+	}{
+		{"tampered", tamper(func(md map[string]string) { md["lock_id"] = "7"; delete(md, snapshotKey) })},
+		{"unsigned", tamper(func(md map[string]string) { delete(md, signatureKey) })},
+		{"foreign key", func() *status.Status {
+			defer func(c *errors.Codec) { SnapshotCodec = c }(SnapshotCodec)
+			SnapshotCodec = &errors.Codec{SigningKey: []byte("other")}
+			return ToStatus(err)
+		}()},
+	}
+	for _, tt := range tests {
+		got := FromStatus(tt.st)
+		if got.Error() != tt.st.Err().Error() {
+			t.Errorf("%s: Error: got %q, want %q", tt.name, got.Error(), tt.st.Err().Error())
+		}
+		if _, ok := errors.GetValue(got, "lock_id"); ok {
+			t.Errorf("%s: GetValue(lock_id): untrusted value restored", tt.name)
+		}
+		if errors.KindOf(got) != errors.KindUnavailable {
+			t.Errorf("%s: KindOf: got %v, want %v", tt.name, errors.KindOf(got), errors.KindUnavailable)
+		}
+	}
+}
+
+func TestServerInterceptorLog(t *testing.T) {
+	var logged []string
+	Log = func(ctx context.Context, method string, err error) {
+		logged = append(logged, method+": "+err.Error())
+	}
+	defer func() { Log = nil }()
+
+	server := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/locks.Locks/Get"}
+	for _, err := range []error{errors.Wrap(io.EOF, "reading lock"), errors.MarkLogged(errors.Wrap(io.EOF, "opening lock"))} {
+		server(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) { return nil, err })
+	}
+	if want := []string{"/locks.Locks/Get: reading lock: EOF"}; !reflect.DeepEqual(logged, want) {
+		t.Errorf("logged: got %q, want %q", logged, want)
+	}
+}
@@ -0,0 +1,237 @@
+// Package errhttp centralizes how HTTP services log errors and render them
+// to clients.
+//
+// Handlers written as HandlerFunc simply return their error:
+//
+//	mux.Handle("/locks", errhttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	        lock, err := store.Get(r.Context(), r.URL.Query().Get("id"))
+//	        if err != nil {
+//	                return errors.Wrap(err, "loading lock")
+//	        }
+//	        return json.NewEncoder(w).Encode(lock)
+//	}))
+//
+// The error is logged with %+v (including stack traces and data) and the
+// client receives only the status derived from errors.HTTPStatus, the
+// message recorded with errors.WithUserMessage, and the code recorded with
+// errors.WithCode. Wrapping a mux with a Renderer's Middleware makes that
+// Renderer's configuration apply to every HandlerFunc and every call to Error
+// beneath it.
+package errhttp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// A Renderer logs errors and writes them to HTTP clients.
+type Renderer struct {
+	// Log is called by Render and LogError with every error that was not
+	// already logged (see errors.MarkLogged). If nil, the request method,
+	// path, and error are written with log.Printf using %+v.
+	Log func(r *http.Request, err error)
+
+	// ProblemJSON selects application/problem+json (RFC 7807) response
+	// bodies. Otherwise bodies are written as text/plain.
+	ProblemJSON bool
+
+	// JSONAPI selects application/vnd.api+json response bodies holding the
+	// error objects returned by NewJSONAPIErrors. It takes precedence over
+	// ProblemJSON.
+	JSONAPI bool
+}
+
+// DefaultRenderer is used by Error when no Renderer was installed with
+// Middleware.
+var DefaultRenderer = &Renderer{}
+
+// Problem is the application/problem+json body written by a Renderer.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   string `json:"code,omitempty"`
+
+	// Fields lists the rules broken by each field when err contains an
+	// *errors.Validation.
+	Fields map[string][]string `json:"fields,omitempty"`
+
+	// Hints lists the suggestions for fixing err recorded with
+	// errors.WithHint.
+	Hints []string `json:"hints,omitempty"`
+}
+
+// NewProblem returns the Problem describing err to a client: its status is
+// errors.HTTPStatus(err), its title is errors.Title(err) or, failing that,
+// the status text, its detail is errors.UserMessage(err), and its code is
+// errors.Code(err), its fields come from any *errors.Validation in err's
+// chain, and its hints are errors.Hints(err). Nothing else about err is
+// exposed.
+func NewProblem(err error) Problem {
+	status := errors.HTTPStatus(err)
+	p := Problem{
+		Type:   "about:blank",
+		Title:  errors.HTTPStatusText(status),
+		Status: status,
+		Detail: errors.UserMessage(err),
+		Code:   errors.Code(err),
+		Hints:  errors.Hints(err),
+	}
+	if title := errors.Title(err); title != "" {
+		p.Title = title
+	}
+	var v *errors.Validation
+	if errors.As(err, &v) {
+		p.Fields = v.Fields()
+	}
+	return p
+}
+
+// Render logs err and writes the Problem describing it, either as
+// application/problem+json or as a text/plain body containing the detail (or
+// the status text when there is no detail) followed by one "hint: " line per
+// hint, or writes its JSON:API error objects if rd.JSONAPI is set. A hint
+// recorded with errors.WithRetryAfter is sent as the Retry-After header.
+func (rd *Renderer) Render(w http.ResponseWriter, r *http.Request, err error) {
+	rd.LogError(r, err)
+
+	p := NewProblem(err)
+	h := w.Header()
+	h.Set("X-Content-Type-Options", "nosniff")
+	if d, ok := errors.RetryAfter(err); ok {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+
+	if rd.JSONAPI {
+		h.Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(p.Status)
+		json.NewEncoder(w).Encode(struct {
+			Errors []JSONAPIError `json:"errors"`
+		}{NewJSONAPIErrors(err)})
+		return
+	}
+
+	if rd.ProblemJSON {
+		h.Set("Content-Type", "application/problem+json")
+		w.WriteHeader(p.Status)
+		json.NewEncoder(w).Encode(p)
+		return
+	}
+
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(p.Status)
+	if p.Detail == "" {
+		p.Detail = p.Title
+	}
+	io.WriteString(w, p.Detail+"\n")
+	for _, hint := range p.Hints {
+		io.WriteString(w, "hint: "+hint+"\n")
+	}
+}
+
+// Middleware returns a handler that makes rd the Renderer used by Error and
+// HandlerFunc for every request served by next.
+func (rd *Renderer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rendererKey{}, rd)))
+	})
+}
+
+// rendererKey is the context key under which Middleware stores its Renderer.
+type rendererKey struct{}
+
+// Error renders err with the Renderer installed by Middleware, or with
+// DefaultRenderer if there is none.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	rd, ok := r.Context().Value(rendererKey{}).(*Renderer)
+	if !ok {
+		rd = DefaultRenderer
+	}
+	rd.Render(w, r, err)
+}
+
+// The HandlerFunc type is an adapter allowing functions that return an error
+// to be used as HTTP handlers. A non-nil error is rendered with Error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls h(w, r) and renders any error it returns.
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		Error(w, r, err)
+	}
+}
+
+// Recover returns a handler that serves next and recovers any panic it
+// raises, except http.ErrAbortHandler. The panic is converted with
+// errors.FromPanic, annotated with the request method and path, reported
+// with errors.Report, and rendered with rd as a 500 Internal Server Error,
+// unless next had already started writing the response.
+func (rd *Renderer) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoverWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err := errors.FromPanic(v)
+			err = errors.WithData(err,
+				"http_method", r.Method,
+				"http_path", r.URL.Path,
+				errors.KeyKind, errors.KindInternal,
+				errors.KeyStatusCode, http.StatusInternalServerError,
+			)
+			errors.Report(r.Context(), err)
+			if rw.written {
+				rd.LogError(r, err)
+				return
+			}
+			rd.Render(w, r, err)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// LogError logs err as Render does, unless it was already logged (see
+// errors.MarkLogged), for errors that cannot be rendered because the
+// response has already been started.
+func (rd *Renderer) LogError(r *http.Request, err error) {
+	if errors.IsLogged(err) {
+		return
+	}
+	if rd.Log != nil {
+		rd.Log(r, err)
+	} else {
+		log.Printf("%s %s: %+v", r.Method, r.URL.Path, err)
+	}
+}
+
+// recoverWriter records whether a response has been started.
+type recoverWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *recoverWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recoverWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *recoverWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
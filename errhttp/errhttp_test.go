@@ -0,0 +1,201 @@
+package errhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	failing := func(err error) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error { return err }
+	}
+
+	tests := []struct {
+		err         error
+		problem     bool
+		wantStatus  int
+		wantType    string
+		wantBody    string
+		wantLogPart string
+	}{{
+		errors.Wrap(io.EOF, "reading lock"),
+		false,
+		http.StatusInternalServerError,
+		"text/plain; charset=utf-8",
+		"Internal Server Error\n",
+		"reading lock",
+	}, {
+		errors.WithUserMessage(errors.WithKind(errors.WrapWithData(io.EOF, "reading lock", "lock_id", 9), errors.KindNotFound), "no such lock"),
+		false,
+		http.StatusNotFound,
+		"text/plain; charset=utf-8",
+		"no such lock\n",
+		"lock_id:9",
+	}, {
+		errors.WithCode(errors.WithUserMessage(errors.WithKind(io.EOF, errors.KindNotFound), "no such lock"), "lock_missing"),
+		true,
+		http.StatusNotFound,
+		"application/problem+json",
+		`{"type":"about:blank","title":"Not Found","status":404,"detail":"no such lock","code":"lock_missing"}` + "\n",
+		"EOF",
+	}, {
+		errors.WithTitle(errors.WithUserMessage(errors.WithKind(io.EOF, errors.KindUnavailable), "the lock did not answer"), "Lock unreachable"),
+		true,
+		http.StatusServiceUnavailable,
+		"application/problem+json",
+		`{"type":"about:blank","title":"Lock unreachable","status":503,"detail":"the lock did not answer"}` + "\n",
+		"EOF",
+	}, {
+		errors.WithHint(errors.WithUserMessage(errors.WithKind(io.EOF, errors.KindUnavailable), "the lock did not answer"), "check that the lock is within BLE range"),
+		false,
+		http.StatusServiceUnavailable,
+		"text/plain; charset=utf-8",
+		"the lock did not answer\nhint: check that the lock is within BLE range\n",
+		"EOF",
+	}, {
+		errors.WithHint(errors.WithKind(io.EOF, errors.KindUnavailable), "check that the lock is within BLE range"),
+		true,
+		http.StatusServiceUnavailable,
+		"application/problem+json",
+		`{"type":"about:blank","title":"Service Unavailable","status":503,"hints":["check that the lock is within BLE range"]}` + "\n",
+		"EOF",
+	}, {
+		errors.WithKind(errors.Wrap(context.Canceled, "reading lock"), errors.KindCanceled),
+		false,
+		errors.StatusClientClosedRequest,
+		"text/plain; charset=utf-8",
+		"Client Closed Request\n",
+		"reading lock",
+	}, {
+		validationErr(),
+		true,
+		http.StatusBadRequest,
+		"application/problem+json",
+		`{"type":"about:blank","title":"Bad Request","status":400,"fields":{"name":["required"]}}` + "\n",
+		"validation failed",
+	}}
+
+	for i, tt := range tests {
+		var logged string
+		rd := &Renderer{
+			Log:         func(r *http.Request, err error) { logged = fmt.Sprintf("%+v", err) },
+			ProblemJSON: tt.problem,
+		}
+		rec := httptest.NewRecorder()
+		rd.Middleware(failing(tt.err)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/locks", nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("test %d: status: got %d, want %d", i+1, rec.Code, tt.wantStatus)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+			t.Errorf("test %d: Content-Type: got %q, want %q", i+1, got, tt.wantType)
+		}
+		if got := rec.Body.String(); got != tt.wantBody {
+			t.Errorf("test %d: body: got %q, want %q", i+1, got, tt.wantBody)
+		}
+		if !strings.Contains(logged, tt.wantLogPart) {
+			t.Errorf("test %d: log: got %q, want it to contain %q", i+1, logged, tt.wantLogPart)
+		}
+	}
+}
+
+func validationErr() error {
+	var v errors.Validation
+	v.Add("name", "required", "")
+	return v.Err()
+}
+
+func TestRenderLogged(t *testing.T) {
+	var logged int
+	rd := &Renderer{Log: func(*http.Request, error) { logged++ }}
+	err := errors.Wrap(errors.MarkLogged(io.EOF), "reading body")
+	rec := httptest.NewRecorder()
+	rd.Render(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+	if logged != 0 {
+		t.Errorf("Render: logged an error marked logged %d times", logged)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Render: got status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	err := errors.WithRetryAfter(errors.WithKind(io.EOF, errors.KindRateLimited), 1500*time.Millisecond)
+	rec := httptest.NewRecorder()
+	(&Renderer{Log: func(*http.Request, error) {}}).Render(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("Render: got %d with Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestHandlerFuncSuccess(t *testing.T) {
+	h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return json.NewEncoder(w).Encode("ok")
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "\"ok\"\n" {
+		t.Errorf("HandlerFunc: got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var reported []error
+	errors.RegisterReporter(errors.ReporterFunc(func(_ context.Context, err error) {
+		reported = append(reported, err)
+	}))
+	var logged []string
+	rd := &Renderer{Log: func(r *http.Request, err error) { logged = append(logged, fmt.Sprintf("%+v", err)) }}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("lock table corrupt")
+	})
+	mux.HandleFunc("/late", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "partial")
+		panic(io.ErrUnexpectedEOF)
+	})
+	h := rd.Recover(mux)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/panic?secret=1", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "Internal Server Error\n" {
+		t.Errorf("Recover: got %d %q, want 500 with a safe body", rec.Code, rec.Body.String())
+	}
+	if len(reported) != 1 || reported[0].Error() != "panic: lock table corrupt" {
+		t.Fatalf("Recover: reported %v", reported)
+	}
+	if v, _ := errors.GetValue(reported[0], "http_path"); v != "/panic" {
+		t.Errorf("Recover: http_path %v, want /panic", v)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "TestRecover") {
+		t.Errorf("Recover: logged %q, want the panic site in the stack", logged)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/late", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("Recover: got %d %q, want the partial response untouched", rec.Code, rec.Body.String())
+	}
+	if len(reported) != 2 || !errors.Is(reported[1], io.ErrUnexpectedEOF) || len(logged) != 2 {
+		t.Errorf("Recover: reported %v, logged %d", reported, len(logged))
+	}
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("Recover: got panic %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	rd.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
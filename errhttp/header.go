@@ -0,0 +1,179 @@
+package errhttp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// The headers written by EncodeHeader.
+const (
+	HeaderMessage     = "X-Error-Message"
+	HeaderCode        = "X-Error-Code"
+	HeaderKind        = "X-Error-Kind"
+	HeaderStatus      = "X-Error-Status"
+	HeaderUserMessage = "X-Error-User-Message"
+	HeaderFingerprint = "X-Error-Fingerprint"
+	HeaderData        = "X-Error-Data"
+	HeaderSignature   = "X-Error-Signature"
+)
+
+// signedHeaders lists the headers covered by HeaderSignature, in the order
+// they are signed.
+var signedHeaders = []string{
+	HeaderMessage, HeaderCode, HeaderKind, HeaderStatus, HeaderUserMessage, HeaderFingerprint, HeaderData,
+}
+
+// HeaderCodec is the Codec whose SigningKey signs the headers written by
+// EncodeHeader and is required by DecodeHeader, so that the codes and user
+// messages relayed by intermediate hops can be trusted. If nil,
+// errors.DefaultCodec is used.
+var HeaderCodec *errors.Codec
+
+// headerCodec returns HeaderCodec, or errors.DefaultCodec if it is nil.
+func headerCodec() *errors.Codec {
+	if HeaderCodec != nil {
+		return HeaderCodec
+	}
+	return errors.DefaultCodec
+}
+
+// headerPayload returns the text of the headers of h covered by
+// HeaderSignature.
+func headerPayload(h http.Header) []byte {
+	var b strings.Builder
+	for _, name := range signedHeaders {
+		fmt.Fprintf(&b, "%s:%s\n", name, h.Get(name))
+	}
+	return []byte(b.String())
+}
+
+// HeaderMaxClassification is the most sensitive data EncodeHeader
+// propagates: data classified above it is removed with errors.LimitData.
+// Values are sent as text formatted with %v.
+var HeaderMaxClassification = errors.ClassInternal
+
+// headerKeys lists the data keys that EncodeHeader sends in headers of
+// their own rather than in HeaderData.
+var headerKeys = map[string]bool{
+	errors.KeyCode:        true,
+	errors.KeyKind:        true,
+	errors.KeyStatusCode:  true,
+	errors.KeyUserMessage: true,
+}
+
+// maxHeaderMessage bounds the length of the message sent by EncodeHeader.
+const maxHeaderMessage = 1024
+
+// EncodeHeader returns headers describing err to another service of the
+// same system: its message, code, Kind, HTTP status, user message,
+// fingerprint, and its data up to HeaderMaxClassification. The receiving
+// service rebuilds the error with DecodeHeader, so that the edge can render
+// and log the origin of a failure rather than that of the last hop. Stack
+// traces are not propagated. If the SigningKey of HeaderCodec is set, the headers
+// are signed with it in HeaderSignature.
+//
+// To send the headers as trailers of a streamed response, declare them in
+// the Trailer header before writing the body and set them with
+// http.TrailerPrefix afterwards.
+// If err is nil, EncodeHeader returns nil.
+func EncodeHeader(err error) http.Header {
+	if err == nil {
+		return nil
+	}
+	h := make(http.Header)
+	msg := err.Error()
+	if len(msg) > maxHeaderMessage {
+		msg = msg[:maxHeaderMessage]
+	}
+	h.Set(HeaderMessage, url.QueryEscape(msg))
+	if code := errors.Code(err); code != "" {
+		h.Set(HeaderCode, url.QueryEscape(code))
+	}
+	if k := errors.KindOf(err); k != errors.KindUnknown {
+		h.Set(HeaderKind, k.String())
+	}
+	h.Set(HeaderStatus, strconv.Itoa(errors.HTTPStatus(err)))
+	if um := errors.UserMessage(err); um != "" {
+		h.Set(HeaderUserMessage, url.QueryEscape(um))
+	}
+	h.Set(HeaderFingerprint, errors.Fingerprint(err))
+
+	data := make(url.Values)
+	for k, v := range errors.LimitedData(err, HeaderMaxClassification) {
+		if !headerKeys[k] {
+			data.Set(k, fmt.Sprint(v))
+		}
+	}
+	if len(data) > 0 {
+		h.Set(HeaderData, data.Encode())
+	}
+	if sig := headerCodec().Sign(headerPayload(h)); sig != nil {
+		h.Set(HeaderSignature, base64.RawURLEncoding.EncodeToString(sig))
+	}
+	return h
+}
+
+// DecodeHeader returns the error described by headers written with
+// EncodeHeader, or nil if h describes no error. The error's message, code,
+// Kind, HTTP status, user message, fingerprint, and data are those of the
+// encoded error; its data values are strings. If the SigningKey of
+// HeaderCodec is set, headers that are unsigned or whose signature does not
+// match are not trusted: DecodeHeader returns an error wrapping
+// errors.ErrSignature instead.
+func DecodeHeader(h http.Header) error {
+	msg := h.Get(HeaderMessage)
+	if msg == "" {
+		return nil
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(h.Get(HeaderSignature))
+	if err := headerCodec().Verify(headerPayload(h), sig); err != nil {
+		return errors.Wrap(err, "decoding error headers")
+	}
+	r := &remoteError{data: make(map[string]interface{})}
+	r.msg, _ = url.QueryUnescape(msg)
+
+	if vals, err := url.ParseQuery(h.Get(HeaderData)); err == nil {
+		for k := range vals {
+			r.data[k] = vals.Get(k)
+		}
+	}
+	if code, err := url.QueryUnescape(h.Get(HeaderCode)); err == nil && code != "" {
+		r.data[errors.KeyCode] = code
+	}
+	if k, ok := errors.ParseKind(h.Get(HeaderKind)); ok && k != errors.KindUnknown {
+		r.data[errors.KeyKind] = k
+	}
+	if status, err := strconv.Atoi(h.Get(HeaderStatus)); err == nil {
+		r.data[errors.KeyStatusCode] = status
+	}
+	if um, err := url.QueryUnescape(h.Get(HeaderUserMessage)); err == nil && um != "" {
+		r.data[errors.KeyUserMessage] = um
+	}
+	if fp := h.Get(HeaderFingerprint); fp != "" {
+		r.data[errors.KeyFingerprint] = fp
+	}
+	return r
+}
+
+// remoteError is an error decoded by DecodeHeader.
+type remoteError struct {
+	msg  string
+	data map[string]interface{}
+}
+
+func (r *remoteError) Error() string { return r.msg }
+
+// DataCache returns a copy of the decoded data.
+func (r *remoteError) DataCache() map[string]interface{} {
+	kv := make(map[string]interface{}, len(r.data))
+	for k, v := range r.data {
+		kv[k] = v
+	}
+	return kv
+}
@@ -0,0 +1,91 @@
+package errhttp
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	if h := EncodeHeader(nil); h != nil {
+		t.Errorf("EncodeHeader(nil): got %v, want nil", h)
+	}
+	if err := DecodeHeader(http.Header{}); err != nil {
+		t.Errorf("DecodeHeader(empty): got %v, want nil", err)
+	}
+
+	errors.ClassifyKeys(errors.ClassSecret, "secret")
+	err := errors.WrapWithData(io.EOF, "reading lock\nstate", "request_id", "r-1", "secret", "s3cr3t")
+	err = errors.WithUserMessage(errors.WithCode(errors.WithKind(err, errors.KindUnavailable), "lock_offline"), "The lock is offline.")
+	err = errors.WithTraceparent(err, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h := EncodeHeader(err)
+	for name, vs := range h {
+		for _, v := range vs {
+			for _, c := range v {
+				if c < ' ' || c > '~' {
+					t.Errorf("EncodeHeader: %s: invalid header value %q", name, v)
+				}
+			}
+		}
+	}
+
+	got := DecodeHeader(h)
+	if got.Error() != err.Error() {
+		t.Errorf("DecodeHeader: message %q, want %q", got.Error(), err.Error())
+	}
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"Code", errors.Code(got), "lock_offline"},
+		{"KindOf", errors.KindOf(got), errors.KindUnavailable},
+		{"HTTPStatus", errors.HTTPStatus(got), http.StatusServiceUnavailable},
+		{"UserMessage", errors.UserMessage(got), "The lock is offline."},
+		{"Traceparent", traceparentOf(got), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{"Fingerprint", errors.Fingerprint(errors.Wrap(got, "calling lockd")), errors.Fingerprint(err)},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if v, _ := errors.GetValue(got, "request_id"); v != "r-1" {
+		t.Errorf("request_id: got %v, want r-1", v)
+	}
+	if _, ok := errors.GetValue(got, "secret"); ok {
+		t.Error("secret: propagated although classified above HeaderMaxClassification")
+	}
+}
+
+func traceparentOf(err error) string {
+	tp, _ := errors.Traceparent(err)
+	return tp
+}
+
+func TestHeaderSigning(t *testing.T) {
+	HeaderCodec = &errors.Codec{SigningKey: []byte("k1")}
+	defer func() { HeaderCodec = nil }()
+
+	err := errors.WithCode(errors.WithKind(io.EOF, errors.KindUnavailable), "lock_offline")
+	h := EncodeHeader(err)
+	if h.Get(HeaderSignature) == "" {
+		t.Fatal("EncodeHeader: no signature")
+	}
+	if got := DecodeHeader(h); errors.Code(got) != "lock_offline" || errors.Is(got, errors.ErrSignature) {
+		t.Errorf("DecodeHeader: got %v, want the signed error", got)
+	}
+
+	tampered := h.Clone()
+	tampered.Set(HeaderCode, "lock_ok")
+	unsigned := h.Clone()
+	unsigned.Del(HeaderSignature)
+	for name, h := range map[string]http.Header{"tampered": tampered, "unsigned": unsigned} {
+		got := DecodeHeader(h)
+		if !errors.Is(got, errors.ErrSignature) || errors.Code(got) != "" {
+			t.Errorf("DecodeHeader of %s headers: got %v, want ErrSignature", name, got)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package errhttp
+
+import (
+	"strconv"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// JSONAPIError is a JSON:API error object (https://jsonapi.org/format/#errors).
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPISource         `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPISource identifies the part of the request document that caused
+// a JSON:API error.
+type JSONAPISource struct {
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// JSONAPIMaxClassification is the most sensitive data NewJSONAPIErrors
+// exposes in the meta member of each error object: data classified above it
+// is removed with errors.LimitData.
+var JSONAPIMaxClassification = errors.ClassPublic
+
+// jsonAPIMembers lists the data keys that error objects carry in members
+// other than meta, or that their status already conveys.
+var jsonAPIMembers = map[string]bool{
+	errors.KeyCode:        true,
+	errors.KeyKind:        true,
+	errors.KeyStatusCode:  true,
+	errors.KeyUserMessage: true,
+	errors.KeyTitle:       true,
+	errors.KeyHint:        true,
+}
+
+// NewJSONAPIErrors returns the JSON:API error objects describing err to a
+// client. An *errors.Validation in err's chain yields one object per
+// FieldError, with a source pointer into the request's attributes and the
+// broken rule as detail. Otherwise a single object is returned, with the
+// same status, user message, and code as NewProblem. Every object has the
+// same title as NewProblem, and the hints returned by errors.Hints under the
+// "hints" meta member.
+func NewJSONAPIErrors(err error) []JSONAPIError {
+	status := errors.HTTPStatus(err)
+	base := JSONAPIError{
+		Status: strconv.Itoa(status),
+		Code:   errors.Code(err),
+		Title:  errors.HTTPStatusText(status),
+		Meta:   jsonAPIMeta(err),
+	}
+	if title := errors.Title(err); title != "" {
+		base.Title = title
+	}
+
+	var v *errors.Validation
+	if !errors.As(err, &v) || len(v.Errors) == 0 {
+		base.Detail = errors.UserMessage(err)
+		return []JSONAPIError{base}
+	}
+	objs := make([]JSONAPIError, len(v.Errors))
+	for i, fe := range v.Errors {
+		objs[i] = base
+		objs[i].Detail = fe.Rule
+		objs[i].Source = &JSONAPISource{Pointer: attributePointer(fe.Field)}
+	}
+	return objs
+}
+
+// jsonAPIMeta returns the data of err up to JSONAPIMaxClassification that
+// no other member carries, and its hints.
+func jsonAPIMeta(err error) map[string]interface{} {
+	var meta map[string]interface{}
+	if hints := errors.Hints(err); len(hints) > 0 {
+		meta = map[string]interface{}{"hints": hints}
+	}
+	for k, v := range errors.LimitedData(err, JSONAPIMaxClassification) {
+		if jsonAPIMembers[k] {
+			continue
+		}
+		if meta == nil {
+			meta = make(map[string]interface{})
+		}
+		meta[k] = v
+	}
+	return meta
+}
+
+// attributePointer converts a FieldError path such as "schedule.days[2]"
+// into the JSON Pointer "/data/attributes/schedule/days/2".
+func attributePointer(field string) string {
+	field = strings.NewReplacer("[", ".", "]", "").Replace(field)
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	var b strings.Builder
+	b.WriteString("/data/attributes")
+	for _, tok := range strings.Split(field, ".") {
+		if tok == "" {
+			continue
+		}
+		b.WriteString("/")
+		b.WriteString(escape.Replace(tok))
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package errhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestNewJSONAPIErrors(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassPublic, "lock_id")
+	err := errors.WrapWithData(io.EOF, "reading lock", "lock_id", 7, "table", "locks")
+	err = errors.WithUserMessage(errors.WithCode(errors.WithKind(err, errors.KindNotFound), "lock_missing"), "No such lock.")
+	want := []JSONAPIError{{
+		Status: "404",
+		Code:   "lock_missing",
+		Title:  "Not Found",
+		Detail: "No such lock.",
+		Meta:   map[string]interface{}{"lock_id": 7},
+	}}
+	if got := NewJSONAPIErrors(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewJSONAPIErrors: got %+v, want %+v", got, want)
+	}
+	JSONAPIMaxClassification = errors.ClassInternal
+	if got := NewJSONAPIErrors(err); got[0].Meta["table"] != "locks" {
+		t.Errorf("NewJSONAPIErrors with JSONAPIMaxClassification internal: got meta %v", got[0].Meta)
+	}
+	JSONAPIMaxClassification = errors.ClassPublic
+
+	want[0].Title = "Lock missing"
+	if got := NewJSONAPIErrors(errors.WithTitle(err, "Lock missing")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewJSONAPIErrors with a title: got %+v, want %+v", got, want)
+	}
+	want[0].Meta = map[string]interface{}{"lock_id": 7, "hints": []string{"check the lock ID"}}
+	if got := NewJSONAPIErrors(errors.WithHint(errors.WithTitle(err, "Lock missing"), "check the lock ID")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewJSONAPIErrors with a hint: got %+v, want %+v", got, want)
+	}
+
+	var v errors.Validation
+	v.Add("name", "required", "")
+	v.Add("schedule.days[2]", "max", 9)
+	v.Add("a/b~c", "format", "x")
+	want = []JSONAPIError{
+		{Status: "400", Title: "Bad Request", Detail: "required", Source: &JSONAPISource{"/data/attributes/name"}},
+		{Status: "400", Title: "Bad Request", Detail: "max", Source: &JSONAPISource{"/data/attributes/schedule/days/2"}},
+		{Status: "400", Title: "Bad Request", Detail: "format", Source: &JSONAPISource{"/data/attributes/a~1b~0c"}},
+	}
+	if got := NewJSONAPIErrors(errors.Wrap(v.Err(), "validating")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewJSONAPIErrors(validation): got %+v, want %+v", got, want)
+	}
+}
+
+func TestRenderJSONAPI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rd := &Renderer{Log: func(*http.Request, error) {}, JSONAPI: true, ProblemJSON: true}
+	rd.Render(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.WithKind(io.EOF, errors.KindConflict))
+
+	want := `{"errors":[{"status":"409","title":"Conflict"}]}` + "\n"
+	if rec.Code != http.StatusConflict || rec.Header().Get("Content-Type") != "application/vnd.api+json" || rec.Body.String() != want {
+		t.Errorf("Render: got %d %s %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
@@ -0,0 +1,161 @@
+//go:build linux
+// +build linux
+
+// Package errjournald writes errors to the systemd journal with structured
+// fields, using journald's native protocol.
+//
+// Every entry carries MESSAGE and PRIORITY, the error's kind, code, and
+// fingerprint as ERROR_KIND, ERROR_CODE, and ERROR_FINGERPRINT, each
+// key/value pair as ERROR_DATA_<KEY>, the origin frame (the first frame of
+// the deepest stack trace) as CODE_FILE, CODE_LINE, and CODE_FUNC, and the
+// full %+v output as ERROR_DETAIL.
+package errjournald
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// SocketPath is the journald native protocol socket.
+const SocketPath = "/run/systemd/journal/socket"
+
+// MaxClassification is the most sensitive data written to the journal:
+// data classified above it is removed with errors.LimitData, from the
+// ERROR_DATA_ fields and from ERROR_DETAIL alike.
+var MaxClassification = errors.ClassInternal
+
+// Field is a single journal field.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Fields returns the journal fields describing err, sorted by name.
+func Fields(err error) []Field {
+	err = errors.LimitData(err, MaxClassification)
+	fields := []Field{
+		{"MESSAGE", err.Error()},
+		{"PRIORITY", strconv.Itoa(priority(err))},
+		{"ERROR_KIND", errors.KindOf(err).String()},
+		{"ERROR_FINGERPRINT", errors.Fingerprint(err)},
+		{"ERROR_DETAIL", fmt.Sprintf("%+v", err)},
+	}
+	if code := errors.Code(err); code != "" {
+		fields = append(fields, Field{"ERROR_CODE", code})
+	}
+	for k, v := range errors.LimitedData(err, MaxClassification) {
+		if k == errors.KeyKind || k == errors.KeyCode {
+			continue
+		}
+		fields = append(fields, Field{"ERROR_DATA_" + fieldName(k), fmt.Sprint(v)})
+	}
+
+	var origin errors.StackTrace
+	for _, l := range errors.Layers(err) {
+		if len(l.Stack) > 0 {
+			origin = l.Stack
+		}
+	}
+	if len(origin) > 0 {
+		fn, file, line := origin[0].Location()
+		fields = append(fields,
+			Field{"CODE_FILE", file},
+			Field{"CODE_LINE", strconv.Itoa(line)},
+			Field{"CODE_FUNC", fn},
+		)
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// priority returns the syslog priority level for err.
+func priority(err error) int {
+	switch errors.SeverityOf(err) {
+	case errors.SeverityDebug:
+		return 7
+	case errors.SeverityInfo:
+		return 6
+	case errors.SeverityWarning:
+		return 4
+	case errors.SeverityCritical:
+		return 2
+	}
+	return 3
+}
+
+// fieldName converts a data key into a valid journal field name: upper case
+// letters, digits, and underscores.
+func fieldName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// Encode serializes fields in the journald native protocol. Values
+// containing newlines are written in the length-prefixed binary form.
+func Encode(fields []Field) []byte {
+	var b bytes.Buffer
+	for _, f := range fields {
+		b.WriteString(f.Name)
+		if !strings.Contains(f.Value, "\n") {
+			b.WriteByte('=')
+			b.WriteString(f.Value)
+			b.WriteByte('\n')
+			continue
+		}
+		b.WriteByte('\n')
+		binary.Write(&b, binary.LittleEndian, uint64(len(f.Value)))
+		b.WriteString(f.Value)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Reporter is an errors.Reporter sending each error to the journal.
+type Reporter struct {
+	conn *net.UnixConn
+}
+
+// New returns a Reporter connected to the local journal.
+func New() (*Reporter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: SocketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to journald")
+	}
+	return &Reporter{conn: conn}, nil
+}
+
+// Send writes err to the journal.
+// If err is nil, Send does nothing.
+func (r *Reporter) Send(err error) error {
+	if err == nil {
+		return nil
+	}
+	_, werr := r.conn.Write(Encode(Fields(err)))
+	return errors.Wrap(werr, "writing to journald")
+}
+
+// Report writes err to the journal, ignoring any failure to do so.
+func (r *Reporter) Report(ctx context.Context, err error) {
+	r.Send(err)
+}
+
+// Close closes the connection to the journal.
+func (r *Reporter) Close() error {
+	return r.conn.Close()
+}
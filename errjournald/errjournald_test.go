@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package errjournald
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func origin() error {
+	return errors.New("lock offline")
+}
+
+func TestFields(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassSecret, "journald_pin")
+	err := errors.WithCode(errors.WrapWithData(origin(), "reading", "lock-id", 7, "journald_pin", "1234"), "lock_offline")
+
+	got := make(map[string]string)
+	var names []string
+	for _, f := range Fields(err) {
+		got[f.Name] = f.Value
+		names = append(names, f.Name)
+	}
+
+	want := map[string]string{
+		"MESSAGE":            "reading: lock offline",
+		"PRIORITY":           "3",
+		"ERROR_KIND":         "unknown",
+		"ERROR_CODE":         "lock_offline",
+		"ERROR_FINGERPRINT":  errors.Fingerprint(err),
+		"ERROR_DATA_LOCK_ID": "7",
+		"CODE_FUNC":          "github.com/noke-inc/lib_errors/errjournald.origin",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+	if !strings.HasSuffix(got["CODE_FILE"], "errjournald_test.go") || got["CODE_LINE"] == "" {
+		t.Errorf("CODE_FILE/CODE_LINE: got %q:%q", got["CODE_FILE"], got["CODE_LINE"])
+	}
+	if !strings.Contains(got["ERROR_DETAIL"], "errjournald.TestFields") {
+		t.Errorf("ERROR_DETAIL: got %q", got["ERROR_DETAIL"])
+	}
+	if _, ok := got["ERROR_DATA_JOURNALD_PIN"]; ok || strings.Contains(got["ERROR_DETAIL"], "1234") {
+		t.Errorf("journald_pin: written although classified secret")
+	}
+	if _, ok := got["ERROR_DATA_CODE"]; ok {
+		t.Errorf("ERROR_DATA_CODE duplicates ERROR_CODE")
+	}
+	if !strings.Contains(strings.Join(names, " "), "CODE_FILE CODE_FUNC CODE_LINE ERROR_CODE") {
+		t.Errorf("Fields not sorted: %v", names)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	got := Encode([]Field{{"MESSAGE", "hi"}, {"ERROR_DETAIL", "a\nb"}})
+	want := []byte("MESSAGE=hi\nERROR_DETAIL\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n")
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encode: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+// Package errjsonrpc converts between errors and JSON-RPC 2.0 error objects.
+//
+// The numeric code of an error object comes from the registry of
+// application codes (see RegisterCode) if the error has a code recorded with
+// errors.WithCode, and otherwise from its Kind. Only the user message, the
+// application code, and the data up to MaxClassification are sent; the rest
+// of the error stays on the server.
+package errjsonrpc
+
+import (
+	"sync"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// The error codes reserved by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// The implementation-defined server error codes used for the Kinds that the
+// specification does not cover.
+const (
+	CodeNotFound        = -32001
+	CodeConflict        = -32002
+	CodeUnauthenticated = -32003
+	CodePermission      = -32004
+	CodeRateLimited     = -32005
+	CodeTimeout         = -32006
+	CodeCanceled        = -32007
+	CodeUnavailable     = -32008
+)
+
+var kindCodes = map[errors.Kind]int{
+	errors.KindInvalid:         CodeInvalidParams,
+	errors.KindNotFound:        CodeNotFound,
+	errors.KindConflict:        CodeConflict,
+	errors.KindUnauthenticated: CodeUnauthenticated,
+	errors.KindPermission:      CodePermission,
+	errors.KindRateLimited:     CodeRateLimited,
+	errors.KindTimeout:         CodeTimeout,
+	errors.KindCanceled:        CodeCanceled,
+	errors.KindUnavailable:     CodeUnavailable,
+	errors.KindInternal:        CodeInternalError,
+}
+
+// MaxClassification is the most sensitive data sent in the data member of
+// error objects: data classified above it is removed with errors.LimitData.
+var MaxClassification = errors.ClassPublic
+
+// memberKeys lists the data keys that error objects carry in members other
+// than data, or that their code already conveys.
+var memberKeys = map[string]bool{
+	errors.KeyKind:        true,
+	errors.KeyStatusCode:  true,
+	errors.KeyUserMessage: true,
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string { return e.Message }
+
+var registry = struct {
+	sync.RWMutex
+	byCode map[string]int
+	byRPC  map[int]string
+}{
+	byCode: make(map[string]int),
+	byRPC:  make(map[int]string),
+}
+
+// RegisterCode makes errors with the application code code (see
+// errors.WithCode) use the JSON-RPC error code rpcCode, and error objects
+// with rpcCode convert back into errors with code. RegisterCode is meant to
+// be called during program initialization.
+func RegisterCode(code string, rpcCode int) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.byCode[code] = rpcCode
+	registry.byRPC[rpcCode] = code
+}
+
+// ToJSONRPC returns the error object describing err to a client. Its
+// message is errors.UserMessage(err) or, failing that, the status text of
+// errors.HTTPStatus(err); its data holds the application code under
+// errors.KeyCode and the rest of the data up to MaxClassification.
+// If err is nil, ToJSONRPC returns nil.
+func ToJSONRPC(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	code := errors.Code(err)
+	e := &Error{Code: CodeInternalError, Message: errors.UserMessage(err)}
+
+	registry.RLock()
+	rpcCode, ok := registry.byCode[code]
+	registry.RUnlock()
+	if ok {
+		e.Code = rpcCode
+	} else if c, ok := kindCodes[errors.KindOf(err)]; ok {
+		e.Code = c
+	}
+	if e.Message == "" {
+		e.Message = errors.HTTPStatusText(errors.HTTPStatus(err))
+	}
+
+	data := make(map[string]interface{})
+	if code != "" {
+		data[errors.KeyCode] = code
+	}
+	for k, v := range errors.LimitedData(err, MaxClassification) {
+		if !memberKeys[k] {
+			data[k] = v
+		}
+	}
+	if len(data) > 0 {
+		e.Data = data
+	}
+	return e
+}
+
+// FromJSONRPC returns an error for the error object e, as received by a
+// client. Its message and user message are e's message, its Kind and code
+// are derived from e's code and data, and its data holds e's data, except
+// for the keys that ToJSONRPC never sends in it, such as errors.KeyKind,
+// which a server cannot override. The error wraps e, so the object can
+// still be retrieved with errors.As.
+// If e is nil, FromJSONRPC returns nil.
+func FromJSONRPC(e *Error) error {
+	if e == nil {
+		return nil
+	}
+	keyVals := []interface{}{
+		errors.KeyKind, Kind(e.Code),
+		errors.KeyUserMessage, e.Message,
+	}
+	for k, v := range e.Data {
+		if !memberKeys[k] {
+			keyVals = append(keyVals, k, v)
+		}
+	}
+	if _, ok := e.Data[errors.KeyCode]; !ok {
+		registry.RLock()
+		code, ok := registry.byRPC[e.Code]
+		registry.RUnlock()
+		if ok {
+			keyVals = append(keyVals, errors.KeyCode, code)
+		}
+	}
+	return errors.WithData(e, keyVals...)
+}
+
+// Kind returns the Kind that best describes an error object with code c.
+func Kind(c int) errors.Kind {
+	switch c {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		return errors.KindInvalid
+	case CodeMethodNotFound:
+		return errors.KindNotFound
+	}
+	for k, code := range kindCodes {
+		if code == c {
+			return k
+		}
+	}
+	return errors.KindUnknown
+}
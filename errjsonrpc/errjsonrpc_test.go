@@ -0,0 +1,95 @@
+package errjsonrpc
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestToJSONRPC(t *testing.T) {
+	RegisterCode("lock_jammed", 1001)
+	errors.ClassifyKeys(errors.ClassPublic, "lock_id")
+
+	tests := []struct {
+		err  error
+		want *Error
+	}{
+		{nil, nil},
+		{io.EOF, &Error{Code: CodeInternalError, Message: "Internal Server Error"}},
+		{
+			errors.WithKind(errors.WrapWithData(io.EOF, "reading", "lock_id", 7, "secret", "s"), errors.KindNotFound),
+			&Error{Code: CodeNotFound, Message: "Not Found", Data: map[string]interface{}{"lock_id": 7}},
+		},
+		{
+			errors.WithUserMessage(errors.WithCode(errors.WithKind(io.EOF, errors.KindUnavailable), "lock_jammed"), "The lock is jammed."),
+			&Error{Code: 1001, Message: "The lock is jammed.", Data: map[string]interface{}{"code": "lock_jammed"}},
+		},
+		{
+			errors.WithKind(io.EOF, errors.KindInvalid),
+			&Error{Code: CodeInvalidParams, Message: "Bad Request"},
+		},
+	}
+	for _, tt := range tests {
+		if got := ToJSONRPC(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ToJSONRPC(%v): got %+v, want %+v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFromJSONRPC(t *testing.T) {
+	RegisterCode("lock_jammed", 1001)
+	if err := FromJSONRPC(nil); err != nil {
+		t.Errorf("FromJSONRPC(nil): got %v, want nil", err)
+	}
+
+	var e Error
+	if err := json.Unmarshal([]byte(`{"code":-32008,"message":"Try again later.","data":{"lock_id":7}}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	err := FromJSONRPC(&e)
+	if err.Error() != "Try again later." || errors.UserMessage(err) != "Try again later." {
+		t.Errorf("FromJSONRPC: got %q", err.Error())
+	}
+	if errors.KindOf(err) != errors.KindUnavailable {
+		t.Errorf("KindOf: got %v, want unavailable", errors.KindOf(err))
+	}
+	if v, _ := errors.GetValue(err, "lock_id"); v != float64(7) {
+		t.Errorf("GetValue: got %v, want 7", v)
+	}
+	var got *Error
+	if !errors.As(err, &got) || got != &e {
+		t.Error("As: error object not in the chain")
+	}
+
+	err = FromJSONRPC(&Error{Code: 1001, Message: "jammed"})
+	if errors.Code(err) != "lock_jammed" {
+		t.Errorf("Code: got %q, want lock_jammed", errors.Code(err))
+	}
+	if errors.KindOf(FromJSONRPC(&Error{Code: CodeMethodNotFound})) != errors.KindNotFound {
+		t.Error("KindOf: method not found should be not_found")
+	}
+}
+
+func TestFromJSONRPCHostileData(t *testing.T) {
+	var e Error
+	body := `{"code":-32008,"message":"Try again later.","data":{"kind":"permission","user_message":"Call 555-0100.","status_code":200,"lock_id":7}}`
+	if err := json.Unmarshal([]byte(body), &e); err != nil {
+		t.Fatal(err)
+	}
+	err := FromJSONRPC(&e)
+	if errors.KindOf(err) != errors.KindUnavailable {
+		t.Errorf("KindOf: got %v, want unavailable", errors.KindOf(err))
+	}
+	if um := errors.UserMessage(err); um != "Try again later." {
+		t.Errorf("UserMessage: got %q, want the error object's message", um)
+	}
+	if status := errors.HTTPStatus(err); status != 503 {
+		t.Errorf("HTTPStatus: got %d, want 503", status)
+	}
+	if v, _ := errors.GetValue(err, "lock_id"); v != float64(7) {
+		t.Errorf("GetValue(lock_id): got %v, want 7", v)
+	}
+}
@@ -0,0 +1,13 @@
+// Command errlint runs the errlint analyzer, on its own or as a go vet tool:
+//
+//	go vet -vettool=$(which errlint) ./...
+package main
+
+import (
+	"github.com/noke-inc/lib_errors/errlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(errlint.Analyzer)
+}
@@ -0,0 +1,168 @@
+// Package errlint defines an analyzer that checks code using lib_errors for
+// errors built in ways that lose stack traces or break the error chain:
+//
+//   - errors created with the standard library's errors.New or fmt.Errorf;
+//   - errors formatted into a message instead of wrapped, such as an error
+//     passed to fmt.Errorf without %w or to errors.Errorf at all;
+//   - %w used with lib_errors functions, which do not support it;
+//   - results of wrapping functions such as errors.Wrap that are discarded.
+//
+// The analyzer can be run on its own or by go vet:
+//
+//	go install github.com/noke-inc/lib_errors/errlint/cmd/errlint@latest
+//	go vet -vettool=$(which errlint) ./...
+package errlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// libPath is the import path of lib_errors.
+const libPath = "github.com/noke-inc/lib_errors"
+
+const doc = `check that errors are built with lib_errors and keep their chain
+
+The errlint analyzer reports calls to the standard library's errors.New and
+fmt.Errorf, errors formatted into messages instead of wrapped, %w passed to
+lib_errors functions, and discarded results of lib_errors wrapping functions.`
+
+// Analyzer reports error construction that loses stack traces or breaks the
+// error chain.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errlint",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// lib_errors itself is built on the standard library.
+	if path := pass.Pkg.Path(); path == libPath || strings.HasPrefix(path, libPath+"/") {
+		return nil, nil
+	}
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.ExprStmt)(nil),
+		(*ast.AssignStmt)(nil),
+	}
+	ins.Preorder(filter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			checkCall(pass, n)
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				checkDiscarded(pass, call)
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 && allBlank(n.Lhs) {
+				if call, ok := n.Rhs[0].(*ast.CallExpr); ok {
+					checkDiscarded(pass, call)
+				}
+			}
+		}
+	})
+	return nil, nil
+}
+
+// checkCall reports calls that create errors without lib_errors or format
+// errors into messages.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil {
+		return
+	}
+	switch path := fn.Pkg().Path(); {
+	case path == "errors" && fn.Name() == "New":
+		pass.Reportf(call.Pos(), "use errors.New from lib_errors instead of the standard library's, which records no stack trace")
+	case path == "fmt" && fn.Name() == "Errorf":
+		format, ok := constString(pass, call.Args[0])
+		if ok && !strings.Contains(format, "%w") && hasErrorArg(pass, call.Args[1:]) {
+			pass.Reportf(call.Pos(), "fmt.Errorf formats an error without %%w, breaking the chain; use errors.Wrapf from lib_errors")
+			return
+		}
+		pass.Reportf(call.Pos(), "use errors.Errorf or errors.Wrapf from lib_errors instead of fmt.Errorf, which records no stack trace")
+	case path == libPath:
+		i := formatParam(fn)
+		if i < 0 || i >= len(call.Args) {
+			return
+		}
+		format, ok := constString(pass, call.Args[i])
+		switch {
+		case ok && strings.Contains(format, "%w"):
+			pass.Reportf(call.Args[i].Pos(), "errors.%s does not support %%w; pass the error to errors.Wrapf instead", fn.Name())
+		case fn.Name() == "Errorf" && hasErrorArg(pass, call.Args[i+1:]):
+			pass.Reportf(call.Pos(), "errors.Errorf formats an error into its message, breaking the chain; use errors.Wrapf")
+		}
+	}
+}
+
+// checkDiscarded reports calls to lib_errors functions that wrap an error
+// and whose result is not used.
+func checkDiscarded(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != libPath {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() == 0 || sig.Results().Len() != 1 ||
+		!isError(sig.Params().At(0).Type()) || !isError(sig.Results().At(0).Type()) {
+		return
+	}
+	pass.Reportf(call.Pos(), "result of errors.%s is discarded; the wrapped error is lost", fn.Name())
+}
+
+// formatParam returns the index of fn's "format string" parameter, or -1.
+func formatParam(fn *types.Func) int {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		p := params.At(i)
+		if p.Name() == "format" && types.Identical(p.Type(), types.Typ[types.String]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// constString returns the value of expr if it is a constant string.
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// hasErrorArg reports whether any of args is an error.
+func hasErrorArg(pass *analysis.Pass, args []ast.Expr) bool {
+	for _, arg := range args {
+		if t := pass.TypesInfo.TypeOf(arg); t != nil && isError(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func isError(t types.Type) bool {
+	return types.Implements(t, errorType)
+}
+
+func allBlank(exprs []ast.Expr) bool {
+	for _, e := range exprs {
+		if id, ok := e.(*ast.Ident); !ok || id.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package a
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func std() error {
+	_ = stderrors.New("boom")                // want `use errors.New from lib_errors`
+	_ = fmt.Errorf("lock %d", 7)             // want `instead of fmt.Errorf`
+	_ = fmt.Errorf("reading: %v", io.EOF)    // want `without %w`
+	return fmt.Errorf("reading: %w", io.EOF) // want `instead of fmt.Errorf`
+}
+
+func lib() error {
+	_ = errors.New("boom")
+	_ = errors.Errorf("lock %d", 7)
+	_ = errors.Errorf("reading: %v", io.EOF)       // want `formats an error into its message`
+	_ = errors.Wrapf(io.EOF, "reading %w", io.EOF) // want `does not support %w` `result of errors.Wrapf is discarded`
+	return errors.Wrapf(io.EOF, "lock %d", 7)
+}
+
+func discarded(err error) error {
+	errors.Wrap(err, "reading")            // want `result of errors.Wrap is discarded`
+	_ = errors.WithData(err, "lock_id", 7) // want `result of errors.WithData is discarded`
+	errors.Report(err)
+	err = errors.Wrap(err, "reading")
+	return err
+}
@@ -0,0 +1,9 @@
+// Package errors is a stub of lib_errors for the errlint tests.
+package errors
+
+func New(message string) error                                  { return nil }
+func Errorf(format string, args ...interface{}) error           { return nil }
+func Wrap(err error, message string) error                      { return err }
+func Wrapf(err error, format string, args ...interface{}) error { return err }
+func WithData(err error, keyVals ...interface{}) error          { return err }
+func Report(err error)                                          {}
@@ -192,20 +192,23 @@ func (b Base) Format(f fmt.State, verb rune) {
 // New returns an error with the supplied message.
 // New also records the stack trace at the point it was called.
 func New(message string) error {
-	return &fundamental{
+	strictMessage(message)
+	return runHooks(withOp(withTimestamp(&fundamental{
 		msg:   message,
 		stack: callers(),
-	}
+	})), HookNew)
 }
 
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	return &fundamental{
-		msg:   fmt.Sprintf(format, args...),
+	msg := fmt.Sprintf(format, args...)
+	strictMessage(msg)
+	return runHooks(withOp(withTimestamp(&fundamental{
+		msg:   msg,
 		stack: callers(),
-	}
+	})), HookNew)
 }
 
 // fundamental is an error that has a message and a stack, but no caller.
@@ -219,9 +222,17 @@ func (f *fundamental) Error() string { return f.msg }
 func (f *fundamental) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, f.GoString())
+			return
+		}
 		if s.Flag('+') {
+			if formatRegistered(s, f) {
+				return
+			}
 			io.WriteString(s, f.msg)
 			f.stack.Format(s, verb)
+			formatGlobalData(s)
 			return
 		}
 		fallthrough
@@ -232,16 +243,22 @@ func (f *fundamental) Format(s fmt.State, verb rune) {
 	}
 }
 
+// GoString returns a Go expression building an error like f.
+func (f *fundamental) GoString() string {
+	return fmt.Sprintf("errors.New(%q)", f.msg)
+}
+
 // WithStack annotates err with a stack trace at the point WithStack was called.
 // If err is nil, WithStack returns nil.
 func WithStack(err error) error {
 	if err == nil {
+		nilWrap("WithStack")
 		return nil
 	}
-	return &withStack{
+	return runHooks(&withStack{
 		err,
 		callers(),
-	}
+	}, HookWrap)
 }
 
 type withStack struct {
@@ -254,9 +271,19 @@ func (w *withStack) Unwrap() error { return w.error }
 func (w *withStack) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
 		if s.Flag('+') {
+			if formatRegistered(s, w) {
+				return
+			}
 			fmt.Fprintf(s, "%+v", w.Unwrap())
 			w.stack.Format(s, verb)
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
 			return
 		}
 		fallthrough
@@ -267,21 +294,32 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 	}
 }
 
+// GoString returns a Go expression building an error like w.
+func (w *withStack) GoString() string {
+	if m, ok := w.error.(*withMessage); ok {
+		return fmt.Sprintf("errors.Wrap(%#v, %q)", m.error, m.msg)
+	}
+	return fmt.Sprintf("errors.WithStack(%#v)", w.error)
+}
+
 // Wrap returns an error annotating err with a stack trace
 // at the point Wrap is called, and the supplied message.
 // If err is nil, Wrap returns nil.
 func Wrap(err error, message string) error {
 	if err == nil {
+		nilWrap("Wrap")
 		return nil
 	}
+	strictMessage(message)
 	err = &withMessage{
 		error: err,
-		msg:   message,
+		msg:   maybePrefix(message),
 	}
-	return &withStack{
+	err = withOp(err)
+	return runHooks(&withStack{
 		err,
 		callers(),
-	}
+	}, HookWrap)
 }
 
 // Wrapf returns an error annotating err with a stack trace
@@ -289,40 +327,50 @@ func Wrap(err error, message string) error {
 // If err is nil, Wrapf returns nil.
 func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
+		nilWrap("Wrapf")
 		return nil
 	}
+	msg := fmt.Sprintf(format, args...)
+	strictMessage(msg)
+	msg = maybePrefix(msg)
 	err = &withMessage{
 		error: err,
-		msg:   fmt.Sprintf(format, args...),
+		msg:   msg,
 	}
-	return &withStack{
+	err = withOp(err)
+	return runHooks(&withStack{
 		err,
 		callers(),
-	}
+	}, HookWrap)
 }
 
 // WithMessage annotates err with a new message.
 // If err is nil, WithMessage returns nil.
 func WithMessage(err error, message string) error {
 	if err == nil {
+		nilWrap("WithMessage")
 		return nil
 	}
-	return &withMessage{
+	strictMessage(message)
+	return runHooks(&withMessage{
 		error: err,
 		msg:   message,
-	}
+	}, HookWrap)
 }
 
 // WithMessagef annotates err with the format specifier.
 // If err is nil, WithMessagef returns nil.
 func WithMessagef(err error, format string, args ...interface{}) error {
 	if err == nil {
+		nilWrap("WithMessagef")
 		return nil
 	}
-	return &withMessage{
+	msg := fmt.Sprintf(format, args...)
+	strictMessage(msg)
+	return runHooks(&withMessage{
 		error: err,
-		msg:   fmt.Sprintf(format, args...),
-	}
+		msg:   msg,
+	}, HookWrap)
 }
 
 type withMessage struct {
@@ -337,9 +385,19 @@ func (w *withMessage) Unwrap() error { return w.error }
 func (w *withMessage) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
 		if s.Flag('+') {
-			fmt.Fprintf(s, "%+v\n", w.Unwrap())
+			if formatRegistered(s, w) {
+				return
+			}
+			fmt.Fprintf(s, "%+v%s", w.Unwrap(), formatConfig().Separator)
 			io.WriteString(s, w.msg)
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
 			return
 		}
 		fallthrough
@@ -348,6 +406,11 @@ func (w *withMessage) Format(s fmt.State, verb rune) {
 	}
 }
 
+// GoString returns a Go expression building an error like w.
+func (w *withMessage) GoString() string {
+	return fmt.Sprintf("errors.WithMessage(%#v, %q)", w.error, w.msg)
+}
+
 // WithData annotates err with a map of key/value pairs.
 // keyVals should be passed in as pairs; the first of each pair being a string (the key).
 // If an odd number of keyVals are passed in, the last one is ignored.
@@ -357,18 +420,50 @@ func WithData(err error, keyVals ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	e := &withData{
+	return runHooks(attachData(err, keyVals), HookWithData)
+}
+
+// attachData returns err annotated with keyVals as described for WithData,
+// without running any hooks.
+func attachData(err error, keyVals []interface{}) *withData {
+	data := dataMap(keyVals)
+	if dataWarningsEnabled() {
+		deeper := treeData(err)
+		for k := range data {
+			if _, ok := deeper[k]; ok {
+				warnData(DataOverridden, k, "shadows a value recorded deeper in the chain")
+			}
+		}
+	}
+	return &withData{
 		err,
-		make(map[string]interface{}),
+		data,
 	}
+}
+
+// dataMap converts keyVals, as described for WithData, into a map.
+func dataMap(keyVals []interface{}) map[string]interface{} {
+	strictData(keyVals)
+	data := make(map[string]interface{})
 	for i := 0; (i + 1) < len(keyVals); i += 2 {
-		if key, ok := keyVals[i].(string); !ok {
+		key, ok := keyVals[i].(string)
+		if !ok {
+			warnData(DataDropped, fmt.Sprint(keyVals[i]), fmt.Sprintf("key is a %T, not a string", keyVals[i]))
+			continue
+		}
+		if err := checkKey(key); err != nil {
+			warnData(DataRejected, key, err.Error())
 			continue
-		} else {
-			e.data[key] = keyVals[i+1]
 		}
+		if _, ok := data[key]; ok {
+			warnData(DataOverridden, key, "given twice in the same call")
+		}
+		data[key] = keyVals[i+1]
+	}
+	if len(keyVals)%2 != 0 {
+		warnData(DataDropped, fmt.Sprint(keyVals[len(keyVals)-1]), "key without a value")
 	}
-	return e
+	return data
 }
 
 // WrapWithData returns an error annotating err with a stack trace
@@ -377,17 +472,20 @@ func WithData(err error, keyVals ...interface{}) error {
 // If err is nil, WrapWithData returns nil.
 func WrapWithData(err error, message string, keyVals ...interface{}) error {
 	if err == nil {
+		nilWrap("WrapWithData")
 		return nil
 	}
+	strictMessage(message)
 	err = &withMessage{
 		error: err,
-		msg:   message,
+		msg:   maybePrefix(message),
 	}
-	err = WithData(err, keyVals...)
-	return &withStack{
+	err = attachData(err, keyVals)
+	err = withOp(err)
+	return runHooks(&withStack{
 		err,
 		callers(),
-	}
+	}, HookWrap)
 }
 
 type withData struct {
@@ -400,33 +498,29 @@ func (w *withData) Unwrap() error { return w.error }
 
 // DataCache returns all key/value pairs in the error (including from wrapped errors)
 func (w *withData) DataCache() map[string]interface{} {
-	type dataCacher interface {
-		DataCache() map[string]interface{}
-	}
-
-	var data dataCacher
-	var kv map[string]interface{}
-
-	if As(w.error, &data) {
-		kv = data.DataCache()
-	} else {
-		kv = make(map[string]interface{})
-	}
-	for k, v := range w.data {
-		kv[k] = v
-	}
-	return kv
+	return treeData(w)
 }
 
 func (w *withData) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
 		if s.Flag('+') {
+			if formatRegistered(s, w) {
+				return
+			}
 			if len(w.data) > 0 {
-				fmt.Fprintf(s, "%+v\nERROR DATA: %v", w.Unwrap(), w.data)
+				c := formatConfig()
+				fmt.Fprintf(s, "%+v%s%s", w.Unwrap(), c.Separator, dataSection(w.data, c))
 			} else {
 				fmt.Fprintf(s, "%+v", w.Unwrap())
 			}
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
 			return
 		}
 		fallthrough
@@ -437,6 +531,11 @@ func (w *withData) Format(s fmt.State, verb rune) {
 	}
 }
 
+// GoString returns a Go expression building an error like w.
+func (w *withData) GoString() string {
+	return fmt.Sprintf("errors.WithData(%#v%s)", w.error, goStringData(w.data))
+}
+
 // Cause returns the underlying cause of the error, if possible.
 // An error value has a cause if it implements the standard
 // errors.Wrapper interface:
@@ -0,0 +1,117 @@
+// Package errrollbar converts errors into Rollbar item payloads.
+//
+// Each error in the chain that recorded a stack trace becomes a trace in the
+// payload's trace chain, the outermost first, carrying the message of the
+// chain at that point. Key/value pairs become the item's custom data. The
+// resulting Payload can be posted as JSON to Rollbar's item endpoint.
+package errrollbar
+
+import (
+	"fmt"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// MaxClassification is the most sensitive data sent to Rollbar: data
+// classified above it is removed with errors.LimitData before an item is
+// built.
+var MaxClassification = errors.ClassInternal
+
+// Payload is the body of a request to Rollbar's item endpoint.
+type Payload struct {
+	AccessToken string `json:"access_token,omitempty"`
+	Data        Data   `json:"data"`
+}
+
+// Data describes a single Rollbar item.
+type Data struct {
+	Environment string                 `json:"environment,omitempty"`
+	Level       string                 `json:"level"`
+	Timestamp   int64                  `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Language    string                 `json:"language"`
+	Title       string                 `json:"title,omitempty"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	Body        Body                   `json:"body"`
+	Custom      map[string]interface{} `json:"custom,omitempty"`
+}
+
+// Body holds either a trace chain or, for errors without stack traces, a
+// plain message.
+type Body struct {
+	TraceChain []Trace  `json:"trace_chain,omitempty"`
+	Message    *Message `json:"message,omitempty"`
+}
+
+// Trace is one exception of a trace chain.
+type Trace struct {
+	Frames    []Frame   `json:"frames"`
+	Exception Exception `json:"exception"`
+}
+
+// Frame is a stack frame; Rollbar expects the most recent call last.
+type Frame struct {
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	Method   string `json:"method"`
+}
+
+// Exception names and describes a Trace.
+type Exception struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// Message is the body of an item without stack traces.
+type Message struct {
+	Body string `json:"body"`
+}
+
+// NewPayload returns the Payload reporting err at level "error".
+func NewPayload(accessToken, environment string, err error) Payload {
+	d := NewData(err)
+	d.Environment = environment
+	return Payload{AccessToken: accessToken, Data: d}
+}
+
+// NewData returns the Rollbar item describing err.
+func NewData(err error) Data {
+	err = errors.LimitData(err, MaxClassification)
+	d := Data{
+		Level:       "error",
+		Timestamp:   errors.Now().Unix(),
+		Platform:    "go",
+		Language:    "go",
+		Title:       err.Error(),
+		Fingerprint: errors.Fingerprint(err),
+		Custom:      errors.LimitedData(err, MaxClassification),
+	}
+	class := fmt.Sprintf("%T", errors.Cause(err))
+	for _, l := range errors.Layers(err) {
+		if len(l.Stack) == 0 {
+			continue
+		}
+		d.Body.TraceChain = append(d.Body.TraceChain, Trace{
+			Frames:    frames(l.Stack),
+			Exception: Exception{Class: class, Message: l.Err.Error()},
+		})
+	}
+	if len(d.Body.TraceChain) == 0 {
+		d.Body.Message = &Message{Body: err.Error()}
+	}
+	if len(d.Custom) == 0 {
+		d.Custom = nil
+	}
+	return d
+}
+
+// frames converts st, which lists the innermost frame first, into Rollbar
+// frames listing the most recent call last.
+func frames(st errors.StackTrace) []Frame {
+	fs := make([]Frame, len(st))
+	for i, f := range st {
+		fn, file, line := f.Location()
+		fs[len(st)-1-i] = Frame{Filename: file, Lineno: line, Method: fn}
+	}
+	return fs
+}
@@ -0,0 +1,46 @@
+package errrollbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestNewData(t *testing.T) {
+	errors.ClassifyKeys(errors.ClassSecret, "rollbar_pin")
+	err := errors.WrapWithData(errors.New("lock offline"), "reading state", "lock_id", 7, "rollbar_pin", "1234")
+	d := NewData(err)
+
+	chain := d.Body.TraceChain
+	if len(chain) != 2 || d.Body.Message != nil {
+		t.Fatalf("Body: got %+v", d.Body)
+	}
+	if got := chain[0].Exception.Message; got != "reading state: lock offline" {
+		t.Errorf("TraceChain[0].Exception.Message: got %q", got)
+	}
+	if got := chain[1].Exception.Message; got != "lock offline" {
+		t.Errorf("TraceChain[1].Exception.Message: got %q", got)
+	}
+	last := chain[0].Frames[len(chain[0].Frames)-1]
+	if !strings.HasSuffix(last.Method, "errrollbar.TestNewData") || !strings.HasSuffix(last.Filename, "errrollbar_test.go") {
+		t.Errorf("most recent frame: got %+v", last)
+	}
+	if _, ok := d.Custom["rollbar_pin"]; ok || d.Custom["lock_id"] != 7 {
+		t.Errorf("Custom: got %v", d.Custom)
+	}
+}
+
+func TestNewPayloadWithoutStack(t *testing.T) {
+	p := NewPayload("token", "production", io.EOF)
+	if p.AccessToken != "token" || p.Data.Environment != "production" {
+		t.Errorf("NewPayload: got %+v", p)
+	}
+	if p.Data.Body.Message == nil || p.Data.Body.Message.Body != "EOF" || p.Data.Body.TraceChain != nil {
+		t.Errorf("Body: got %+v", p.Data.Body)
+	}
+	if p.Data.Custom != nil {
+		t.Errorf("Custom: got %v, want nil", p.Data.Custom)
+	}
+}
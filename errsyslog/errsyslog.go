@@ -0,0 +1,126 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+// Package errsyslog writes errors to the system log, mapping their severity
+// to a syslog priority and their data to key=value fields.
+package errsyslog
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+	"sort"
+	"strconv"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// MaxClassification is the most sensitive data written to the system log:
+// data classified above it is left out of the records.
+var MaxClassification = errors.ClassInternal
+
+// Priority returns the syslog severity for err, derived from
+// errors.SeverityOf.
+func Priority(err error) syslog.Priority {
+	switch errors.SeverityOf(err) {
+	case errors.SeverityDebug:
+		return syslog.LOG_DEBUG
+	case errors.SeverityInfo:
+		return syslog.LOG_INFO
+	case errors.SeverityWarning:
+		return syslog.LOG_WARNING
+	case errors.SeverityCritical:
+		return syslog.LOG_CRIT
+	}
+	return syslog.LOG_ERR
+}
+
+// Record returns the single-line structured record written for err: the
+// message followed by the kind, code, and fingerprint of the error and its
+// data up to MaxClassification as sorted key=value fields, values quoted
+// when needed.
+func Record(err error) string {
+	var b strings.Builder
+	b.WriteString(strconv.Quote(err.Error()))
+	field(&b, "kind", errors.KindOf(err).String())
+	if code := errors.Code(err); code != "" {
+		field(&b, "code", code)
+	}
+	field(&b, "fingerprint", errors.Fingerprint(err))
+
+	data := errors.LimitedData(err, MaxClassification)
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		if k != errors.KeyKind && k != errors.KeyCode {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field(&b, k, fmt.Sprint(data[k]))
+	}
+	return b.String()
+}
+
+// field appends " key=value" to b, quoting value if needed.
+func field(b *strings.Builder, key, value string) {
+	b.WriteByte(' ')
+	b.WriteString(key)
+	b.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(value)
+}
+
+// writer is the subset of *syslog.Writer used by Reporter.
+type writer interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+	Crit(m string) error
+}
+
+// Reporter is an errors.Reporter writing each error's Record to the system
+// log at the error's Priority.
+type Reporter struct {
+	w writer
+}
+
+// New returns a Reporter writing to w.
+func New(w *syslog.Writer) *Reporter {
+	return &Reporter{w: w}
+}
+
+// Dial connects to the syslog daemon at raddr over network (both empty for
+// the local daemon) and returns a Reporter writing to it with the given tag.
+func Dial(network, raddr, tag string) (*Reporter, error) {
+	w, err := syslog.Dial(network, raddr, syslog.LOG_DAEMON|syslog.LOG_ERR, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing syslog")
+	}
+	return New(w), nil
+}
+
+// Report writes err to the system log.
+// If err is nil, Report does nothing.
+func (r *Reporter) Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	rec := Record(err)
+	switch Priority(err) {
+	case syslog.LOG_DEBUG:
+		r.w.Debug(rec)
+	case syslog.LOG_INFO:
+		r.w.Info(rec)
+	case syslog.LOG_WARNING:
+		r.w.Warning(rec)
+	case syslog.LOG_CRIT:
+		r.w.Crit(rec)
+	default:
+		r.w.Err(rec)
+	}
+}
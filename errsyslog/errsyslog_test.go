@@ -0,0 +1,62 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package errsyslog
+
+import (
+	"context"
+	"io"
+	"log/syslog"
+	"reflect"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+type fakeWriter struct{ lines []string }
+
+func (w *fakeWriter) write(level, m string) error {
+	w.lines = append(w.lines, level+" "+m)
+	return nil
+}
+
+func (w *fakeWriter) Debug(m string) error   { return w.write("debug", m) }
+func (w *fakeWriter) Info(m string) error    { return w.write("info", m) }
+func (w *fakeWriter) Warning(m string) error { return w.write("warning", m) }
+func (w *fakeWriter) Err(m string) error     { return w.write("err", m) }
+func (w *fakeWriter) Crit(m string) error    { return w.write("crit", m) }
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		err  error
+		want syslog.Priority
+	}{
+		{io.EOF, syslog.LOG_ERR},
+		{errors.WithKind(io.EOF, errors.KindNotFound), syslog.LOG_WARNING},
+		{errors.WithSeverity(io.EOF, errors.SeverityCritical), syslog.LOG_CRIT},
+		{errors.WithSeverity(io.EOF, errors.SeverityInfo), syslog.LOG_INFO},
+	}
+
+	for i, tt := range tests {
+		if got := Priority(tt.err); got != tt.want {
+			t.Errorf("test %d: Priority(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestReporter(t *testing.T) {
+	w := &fakeWriter{}
+	r := &Reporter{w: w}
+
+	errors.ClassifyKeys(errors.ClassSecret, "syslog_pin")
+	eof := errors.WithData(errors.WithKind(io.EOF, errors.KindNotFound), "lock_id", 7, "note", "two words", "syslog_pin", "1234")
+	r.Report(context.Background(), eof)
+	r.Report(context.Background(), nil)
+
+	want := []string{
+		`warning "EOF" kind=not_found fingerprint=` + errors.Fingerprint(eof) + ` lock_id=7 note="two words"`,
+	}
+	if !reflect.DeepEqual(w.lines, want) {
+		t.Errorf("Report: got %q, want %q", w.lines, want)
+	}
+}
@@ -0,0 +1,130 @@
+// Package errtest provides test assertions for errors built with lib_errors.
+//
+// Each assertion reports a failure with t.Errorf, describing what was found
+// next to what was expected, and returns whether it passed so that callers
+// can stop early:
+//
+//	if !errtest.AssertKind(t, err, errors.KindNotFound) {
+//	        return
+//	}
+package errtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// TB is the subset of testing.TB used by the assertions.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertIs asserts that errors.Is(err, target).
+func AssertIs(t TB, err, target error) bool {
+	t.Helper()
+	if errors.Is(err, target) {
+		return true
+	}
+	t.Errorf("error chain does not contain target\n  target: %v\n%s", target, describe(err))
+	return false
+}
+
+// AssertKind asserts that errors.KindOf(err) is want.
+func AssertKind(t TB, err error, want errors.Kind) bool {
+	t.Helper()
+	if got := errors.KindOf(err); got != want {
+		t.Errorf("wrong kind\n  got:  %v\n  want: %v\n%s", got, want, describe(err))
+		return false
+	}
+	return true
+}
+
+// AssertDataEqual asserts that errors.GetValue(err, key) finds a value
+// deeply equal to want.
+func AssertDataEqual(t TB, err error, key string, want interface{}) bool {
+	t.Helper()
+	got, ok := errors.GetValue(err, key)
+	if !ok {
+		t.Errorf("no value for key %q\n  want: %#v\n  data: %v", key, want, errors.GetAllData(err))
+		return false
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong value for key %q\n  got:  %#v\n  want: %#v", key, got, want)
+		return false
+	}
+	return true
+}
+
+// AssertChainMessages asserts that the messages contributed by the errors of
+// err's chain, outermost first and skipping errors that add no message, are
+// exactly want.
+func AssertChainMessages(t TB, err error, want []string) bool {
+	t.Helper()
+	got := ChainMessages(err)
+	if reflect.DeepEqual(got, want) || len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	var b strings.Builder
+	b.WriteString("wrong chain messages (- want, + got)")
+	for i := 0; i < len(got) || i < len(want); i++ {
+		switch {
+		case i >= len(got):
+			fmt.Fprintf(&b, "\n  - %q", want[i])
+		case i >= len(want):
+			fmt.Fprintf(&b, "\n  + %q", got[i])
+		case got[i] == want[i]:
+			fmt.Fprintf(&b, "\n    %q", got[i])
+		default:
+			fmt.Fprintf(&b, "\n  - %q\n  + %q", want[i], got[i])
+		}
+	}
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// ChainMessages returns the messages asserted by AssertChainMessages.
+func ChainMessages(err error) []string {
+	var msgs []string
+	for _, l := range errors.Layers(err) {
+		if l.Message != "" {
+			msgs = append(msgs, l.Message)
+		}
+	}
+	return msgs
+}
+
+// AssertStackContains asserts that a stack trace recorded in err's chain
+// contains a frame of the function fn, given either fully qualified
+// ("github.com/noke-inc/lockd/store.(*DB).Get") or with its package name
+// only ("store.(*DB).Get").
+func AssertStackContains(t TB, err error, fn string) bool {
+	t.Helper()
+	var seen []string
+	for _, l := range errors.Layers(err) {
+		for _, f := range l.Stack {
+			name, _, _ := f.Location()
+			if name == fn || strings.HasSuffix(name, "/"+fn) {
+				return true
+			}
+			seen = append(seen, name)
+		}
+	}
+	if len(seen) == 0 {
+		t.Errorf("no stack trace recorded, want a frame of %s\n%s", fn, describe(err))
+		return false
+	}
+	t.Errorf("no frame of %s in recorded stack traces:\n  %s", fn, strings.Join(seen, "\n  "))
+	return false
+}
+
+// describe returns err formatted for inclusion in a failure message.
+func describe(err error) string {
+	if err == nil {
+		return "  error: <nil>"
+	}
+	return fmt.Sprintf("  error: %v (%T)", err, err)
+}
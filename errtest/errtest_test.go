@@ -0,0 +1,64 @@
+package errtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// recorder is a TB recording failures instead of failing the test.
+type recorder struct{ failures []string }
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func origin() error {
+	return errors.New("lock offline")
+}
+
+func TestAssertions(t *testing.T) {
+	err := errors.WithKind(errors.WrapWithData(origin(), "reading state", "lock_id", 7), errors.KindUnavailable)
+
+	tests := []struct {
+		name     string
+		assert   func(TB) bool
+		wantPass bool
+		wantMsg  string
+	}{
+		{"Is", func(t TB) bool { return AssertIs(t, errors.Wrap(io.EOF, "x"), io.EOF) }, true, ""},
+		{"Is/fail", func(t TB) bool { return AssertIs(t, err, io.EOF) }, false, "target: EOF"},
+		{"Kind", func(t TB) bool { return AssertKind(t, err, errors.KindUnavailable) }, true, ""},
+		{"Kind/fail", func(t TB) bool { return AssertKind(t, err, errors.KindNotFound) }, false, "got:  unavailable\n  want: not_found"},
+		{"Data", func(t TB) bool { return AssertDataEqual(t, err, "lock_id", 7) }, true, ""},
+		{"Data/wrong", func(t TB) bool { return AssertDataEqual(t, err, "lock_id", 8) }, false, "got:  7\n  want: 8"},
+		{"Data/missing", func(t TB) bool { return AssertDataEqual(t, err, "user", "bob") }, false, `no value for key "user"`},
+		{"Messages", func(t TB) bool { return AssertChainMessages(t, err, []string{"reading state", "lock offline"}) }, true, ""},
+		{"Messages/fail", func(t TB) bool { return AssertChainMessages(t, err, []string{"reading", "lock offline", "extra"}) }, false,
+			"  - \"reading\"\n  + \"reading state\"\n    \"lock offline\"\n  - \"extra\""},
+		{"Stack", func(t TB) bool { return AssertStackContains(t, err, "errtest.origin") }, true, ""},
+		{"Stack/qualified", func(t TB) bool {
+			return AssertStackContains(t, err, "github.com/noke-inc/lib_errors/errtest.TestAssertions")
+		}, true, ""},
+		{"Stack/fail", func(t TB) bool { return AssertStackContains(t, err, "store.Get") }, false, "no frame of store.Get"},
+		{"Stack/none", func(t TB) bool { return AssertStackContains(t, io.EOF, "store.Get") }, false, "no stack trace recorded"},
+	}
+
+	for _, tt := range tests {
+		r := &recorder{}
+		if got := tt.assert(r); got != tt.wantPass {
+			t.Errorf("%s: got %v, want %v (failures: %q)", tt.name, got, tt.wantPass, r.failures)
+		}
+		if tt.wantPass != (len(r.failures) == 0) {
+			t.Errorf("%s: got failures %q", tt.name, r.failures)
+		}
+		if tt.wantMsg != "" && (len(r.failures) != 1 || !strings.Contains(r.failures[0], tt.wantMsg)) {
+			t.Errorf("%s: got failures %q, want one containing %q", tt.name, r.failures, tt.wantMsg)
+		}
+	}
+}
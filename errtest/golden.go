@@ -0,0 +1,84 @@
+package errtest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// UpdateEnv names the environment variable that makes AssertGolden rewrite
+// golden files with the current output instead of comparing against them:
+//
+//	ERRTEST_UPDATE=1 go test ./...
+const UpdateEnv = "ERRTEST_UPDATE"
+
+var (
+	// frameFile matches the file:line half of a frame printed with %+v.
+	frameFile = regexp.MustCompile(`^\t(.+):\d+$`)
+	// timestamp matches a time.Time printed with %v, including the
+	// monotonic clock reading.
+	timestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)? [+-]\d{4} \w+( m=[+-]\d+\.\d+)?`)
+)
+
+// Golden returns err formatted with %+v and normalized so that the output
+// is stable across machines, checkouts, and Go releases:
+//
+//   - stack frame files are reduced to their base name and line numbers
+//     are replaced with "<line>";
+//   - frames of the runtime and testing packages are removed;
+//   - times are replaced with "<time>".
+//
+// Function names are kept, so the output still shows which code path built
+// the error.
+func Golden(err error) string {
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	out := lines[:0]
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if i+1 < len(lines) && frameFile.MatchString(lines[i+1]) {
+			if strings.HasPrefix(line, "runtime.") || strings.HasPrefix(line, "testing.") {
+				i++
+				continue
+			}
+		}
+		if m := frameFile.FindStringSubmatch(line); m != nil {
+			line = "\t" + path.Base(filepath.ToSlash(m[1])) + ":<line>"
+		}
+		out = append(out, timestamp.ReplaceAllString(line, "<time>"))
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// AssertGolden asserts that Golden(err) matches the contents of the file
+// at path. If the UpdateEnv environment variable is set, the file is
+// written with Golden(err) instead.
+func AssertGolden(t TB, err error, path string) bool {
+	t.Helper()
+	got := Golden(err)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		return true
+	}
+	want, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Errorf("reading golden file: %v (run with %s=1 to create it)", rerr, UpdateEnv)
+		return false
+	}
+	want = bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n"))
+	if got != string(want) {
+		t.Errorf("output does not match %s (run with %s=1 to update)\n--- got:\n%s--- want:\n%s", path, UpdateEnv, got, want)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package errtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestGolden(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	err := errors.WrapWithData(origin(), "reading state", "at", at)
+
+	want := "lock offline\n" +
+		"github.com/noke-inc/lib_errors/errtest.origin\n\terrtest_test.go:<line>\n" +
+		"github.com/noke-inc/lib_errors/errtest.TestGolden\n\tgolden_test.go:<line>\n" +
+		"reading state\n" +
+		"ERROR DATA: map[at:<time>]\n" +
+		"github.com/noke-inc/lib_errors/errtest.TestGolden\n\tgolden_test.go:<line>\n"
+	if got := Golden(err); got != want {
+		t.Errorf("Golden: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "testdata", "err.golden")
+	err := errors.Wrap(origin(), "reading state")
+
+	r := &recorder{}
+	if AssertGolden(r, err, file) || !strings.Contains(r.failures[0], UpdateEnv+"=1") {
+		t.Fatalf("missing file: got %q", r.failures)
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	if !AssertGolden(t, err, file) {
+		t.Fatal("update failed")
+	}
+	t.Setenv(UpdateEnv, "")
+
+	if !AssertGolden(t, err, file) {
+		t.Fatal("match after update failed")
+	}
+	if err := os.WriteFile(file, []byte("other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r = &recorder{}
+	if AssertGolden(r, err, file) || !strings.Contains(r.failures[0], "--- want:\nother\n") {
+		t.Errorf("mismatch: got %q", r.failures)
+	}
+}
@@ -0,0 +1,251 @@
+// Package errwire defines a compact binary encoding of errors for relaying
+// them over constrained links, such as BLE or LoRa between gateways.
+//
+// An encoded error is a version byte followed by:
+//
+//	uvarint  code number (see RegisterCode; 0 if none)
+//	byte     errors.Severity
+//	byte     errors.Kind
+//	uvarint  message length, then the message, truncated to MaxMessage bytes
+//	uvarint  number of data entries, then for each:
+//	         uvarint  index of the key in DataKeys
+//	         uvarint  value length, then the value formatted with %v,
+//	                  truncated to MaxValue bytes
+//
+// Both ends of a link must register the same codes and use the same
+// DataKeys.
+package errwire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// version is the first byte of every encoded error.
+const version = 1
+
+var (
+	// MaxMessage is the maximum number of message bytes encoded.
+	MaxMessage = 64
+	// MaxValue is the maximum number of bytes encoded per data value.
+	MaxValue = 32
+	// MaxEncodedSize is the size in bytes that encoded errors must fit,
+	// such as the payload size of a link. Data entries are dropped, largest
+	// first, until the encoding fits. Zero or less means no limit.
+	MaxEncodedSize = 0
+	// MaxClassification is the most sensitive data encoded: data
+	// classified above it is removed with errors.LimitData.
+	MaxClassification = errors.ClassInternal
+	// DataKeys lists the data keys that can be encoded, since a key is
+	// identified on the wire by its index in DataKeys rather than by its
+	// name. The list may only grow at its end.
+	DataKeys []string
+)
+
+var registry = struct {
+	sync.RWMutex
+	byCode map[string]uint64
+	byNum  map[uint64]string
+}{
+	byCode: make(map[string]uint64),
+	byNum:  make(map[uint64]string),
+}
+
+// RegisterCode assigns the number n, which must not be 0, to the
+// application code code (see errors.WithCode). Codes that are not
+// registered are not encoded. RegisterCode is meant to be called during
+// program initialization.
+func RegisterCode(code string, n uint64) {
+	if n == 0 {
+		panic("errwire: code number 0 is reserved")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.byCode[code] = n
+	registry.byNum[n] = code
+}
+
+// Record is a decoded error.
+type Record struct {
+	// Code is the application code, or "" if none was encoded or its
+	// number is not registered.
+	Code     string
+	Severity errors.Severity
+	Kind     errors.Kind
+	Message  string
+	Data     map[string]string
+}
+
+// Err returns an error with r's message, and with its code, severity,
+// Kind, and data recorded as data.
+func (r Record) Err() error {
+	var keyVals []interface{}
+	if r.Severity != errors.SeverityUnset {
+		keyVals = append(keyVals, errors.KeySeverity, r.Severity)
+	}
+	if r.Code != "" {
+		keyVals = append(keyVals, errors.KeyCode, r.Code)
+	}
+	if r.Kind != errors.KindUnknown {
+		keyVals = append(keyVals, errors.KeyKind, r.Kind)
+	}
+	for k, v := range r.Data {
+		keyVals = append(keyVals, k, v)
+	}
+	return errors.WithData(errors.New(r.Message), keyVals...)
+}
+
+// Encode returns the encoding of err, with the values of DataKeys up to
+// MaxClassification, shrunk to fit MaxEncodedSize if it can be by dropping
+// data entries.
+// If err is nil, Encode returns nil.
+func Encode(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	registry.RLock()
+	code := registry.byCode[errors.Code(err)]
+	registry.RUnlock()
+
+	b := []byte{version}
+	b = appendUvarint(b, code)
+	b = append(b, byte(errors.SeverityOf(err)), byte(errors.KindOf(err)))
+	b = appendString(b, err.Error(), MaxMessage)
+
+	type entry struct {
+		index int
+		value string
+	}
+	var entries []entry
+	data := errors.LimitedData(err, MaxClassification)
+	for i, key := range DataKeys {
+		if v, ok := data[key]; ok {
+			entries = append(entries, entry{i, fmt.Sprint(v)})
+		}
+	}
+	for {
+		enc := appendUvarint(b, uint64(len(entries)))
+		for _, e := range entries {
+			enc = appendUvarint(enc, uint64(e.index))
+			enc = appendString(enc, e.value, MaxValue)
+		}
+		if MaxEncodedSize <= 0 || len(enc) <= MaxEncodedSize || len(entries) == 0 {
+			return enc
+		}
+		largest := 0
+		for i, e := range entries {
+			if len(e.value) > len(entries[largest].value) {
+				largest = i
+			}
+		}
+		entries = append(entries[:largest], entries[largest+1:]...)
+	}
+}
+
+// appendUvarint appends the varint encoding of x to b.
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+// appendString appends s, truncated to at most max bytes without splitting
+// a UTF-8 sequence, prefixed by its length.
+func appendString(b []byte, s string, max int) []byte {
+	for len(s) > max {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// Decode decodes an error encoded by Encode. Data entries whose index is
+// beyond DataKeys are kept under the key "#<index>".
+func Decode(b []byte) (Record, error) {
+	d := decoder{b: b}
+	if v := d.byte(); d.err == nil && v != version {
+		return Record{}, errors.Errorf("errwire: unsupported version %d", v)
+	}
+	var r Record
+	code := d.uvarint()
+	r.Severity = errors.Severity(d.byte())
+	r.Kind = errors.Kind(d.byte())
+	r.Message = d.string()
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.b)) {
+		d.err = errors.New("errwire: data entry count exceeds input")
+	}
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		index, value := d.uvarint(), d.string()
+		key := fmt.Sprintf("#%d", index)
+		if index < uint64(len(DataKeys)) {
+			key = DataKeys[index]
+		}
+		if r.Data == nil {
+			r.Data = make(map[string]string)
+		}
+		r.Data[key] = value
+	}
+	if d.err != nil {
+		return Record{}, d.err
+	}
+	if len(d.b) > 0 {
+		return Record{}, errors.Errorf("errwire: %d trailing bytes", len(d.b))
+	}
+	registry.RLock()
+	r.Code = registry.byNum[code]
+	registry.RUnlock()
+	return r, nil
+}
+
+// decoder reads an encoded error, recording the first failure.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+var errShort = errors.New("errwire: input too short")
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.b) == 0 {
+		d.err = errShort
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errShort
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.b)) {
+		d.err = errShort
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
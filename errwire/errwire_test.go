@@ -0,0 +1,91 @@
+package errwire
+
+import (
+	"strings"
+	"testing"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	RegisterCode("lock_jammed", 7)
+	errors.ClassifyKeys(errors.ClassSecret, "wire_pin")
+	defer func(keys []string) { DataKeys = keys }(DataKeys)
+	DataKeys = []string{"lock_id", "battery", "wire_pin"}
+
+	err := errors.WithData(errors.New(strings.Repeat("é", 40)),
+		errors.KeyCode, "lock_jammed",
+		errors.KeyKind, errors.KindUnavailable,
+		errors.KeySeverity, errors.SeverityError,
+		"lock_id", "L-0042",
+		"battery", 17,
+		"wire_pin", "1234",
+		"ignored", "x",
+	)
+	b := Encode(err)
+	r, derr := Decode(b)
+	if derr != nil {
+		t.Fatalf("Decode: %v", derr)
+	}
+	if r.Code != "lock_jammed" || r.Kind != errors.KindUnavailable || r.Severity != errors.SeverityError {
+		t.Errorf("Decode: got %+v", r)
+	}
+	if want := strings.Repeat("é", 32); r.Message != want {
+		t.Errorf("Message: got %q, want %q", r.Message, want)
+	}
+	if len(r.Data) != 2 || r.Data["lock_id"] != "L-0042" || r.Data["battery"] != "17" {
+		t.Errorf("Data: got %v", r.Data)
+	}
+
+	got := r.Err()
+	if errors.Code(got) != "lock_jammed" || errors.KindOf(got) != errors.KindUnavailable {
+		t.Errorf("Err: got code %q, kind %v", errors.Code(got), errors.KindOf(got))
+	}
+	if v, _ := errors.GetValue(got, "lock_id"); v != "L-0042" {
+		t.Errorf("Err: got lock_id %v", v)
+	}
+
+	if Encode(nil) != nil {
+		t.Error("Encode(nil): want nil")
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	b := Encode(errors.New("boom"))
+	tests := [][]byte{
+		nil,
+		{2},
+		b[:len(b)-1],
+		append(append([]byte(nil), b...), 0),
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt); err == nil {
+			t.Errorf("Decode(%v): want error", tt)
+		}
+	}
+}
+
+func TestMaxEncodedSize(t *testing.T) {
+	defer func(keys []string, max int) { DataKeys, MaxEncodedSize = keys, max }(DataKeys, MaxEncodedSize)
+	DataKeys = []string{"lock_id", "battery"}
+
+	err := errors.WithData(errors.New("jammed"), "lock_id", "L-0042-0042", "battery", 17)
+	full := Encode(err)
+	MaxEncodedSize = len(full) - 1
+	b := Encode(err)
+	if len(b) > MaxEncodedSize {
+		t.Errorf("Encode: got %d bytes, want at most %d", len(b), MaxEncodedSize)
+	}
+	r, derr := Decode(b)
+	if derr != nil {
+		t.Fatal(derr)
+	}
+	if _, ok := r.Data["lock_id"]; ok || r.Data["battery"] != "17" {
+		t.Errorf("Data: got %v, want only battery kept", r.Data)
+	}
+
+	MaxEncodedSize = 1
+	if r, derr := Decode(Encode(err)); derr != nil || r.Message != "jammed" || len(r.Data) != 0 {
+		t.Errorf("Encode over budget without data: got %+v, %v", r, derr)
+	}
+}
@@ -0,0 +1,134 @@
+// Package errws maps errors to WebSocket close codes (RFC 6455) and closes
+// connections with a reason that is safe to show to clients.
+//
+// Application codes (see errors.WithCode) can be given their own close
+// codes with RegisterCode; other errors are mapped by Kind, using the
+// private-use range 4000-4999 as 4000 plus the matching HTTP status where
+// the protocol defines no suitable code.
+package errws
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+// The close codes defined by RFC 6455 and used by this package.
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseInvalidData   = 1007
+	ClosePolicy        = 1008
+	CloseInternalError = 1011
+	CloseTryAgainLater = 1013
+)
+
+// closeMessage is the WebSocket opcode of close frames, as used by
+// WriteControl.
+const closeMessage = 8
+
+// maxReason is the number of bytes left for the reason in a close frame
+// once the code is written.
+const maxReason = 123
+
+var kindCodes = map[errors.Kind]int{
+	errors.KindInvalid:         CloseInvalidData,
+	errors.KindUnauthenticated: 4401,
+	errors.KindPermission:      4403,
+	errors.KindNotFound:        4404,
+	errors.KindTimeout:         4408,
+	errors.KindConflict:        4409,
+	errors.KindRateLimited:     4429,
+	errors.KindCanceled:        CloseGoingAway,
+	errors.KindUnavailable:     CloseTryAgainLater,
+	errors.KindInternal:        CloseInternalError,
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[string]int
+}{codes: make(map[string]int)}
+
+// RegisterCode makes errors with the application code code close
+// connections with closeCode, which should be in the private-use range
+// 4000-4999. RegisterCode is meant to be called during program
+// initialization.
+func RegisterCode(code string, closeCode int) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.codes[code] = closeCode
+}
+
+// CloseCode returns the close code for a connection ended by err: the code
+// registered for its application code, or the code matching its Kind, or
+// CloseInternalError.
+// If err is nil, CloseCode returns CloseNormal.
+func CloseCode(err error) int {
+	if err == nil {
+		return CloseNormal
+	}
+	registry.RLock()
+	c, ok := registry.codes[errors.Code(err)]
+	registry.RUnlock()
+	if ok {
+		return c
+	}
+	if c, ok := kindCodes[errors.KindOf(err)]; ok {
+		return c
+	}
+	return CloseInternalError
+}
+
+// CloseReason returns the reason sent to the client for a connection ended
+// by err: its user message (see errors.WithUserMessage) or, failing that,
+// the name of its Kind. Control characters are removed and the reason is
+// truncated to fit a close frame.
+// If err is nil, CloseReason returns "".
+func CloseReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	reason := errors.UserMessage(err)
+	if reason == "" {
+		reason = strings.ReplaceAll(errors.KindOf(err).String(), "_", " ")
+	}
+	reason = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, reason)
+	for len(reason) > maxReason {
+		_, size := utf8.DecodeLastRuneInString(reason)
+		reason = reason[:len(reason)-size]
+	}
+	return reason
+}
+
+// FormatCloseMessage returns the payload of a close frame for a connection
+// ended by err, holding CloseCode(err) and CloseReason(err).
+func FormatCloseMessage(err error) []byte {
+	reason := CloseReason(err)
+	buf := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(buf, uint16(CloseCode(err)))
+	copy(buf[2:], reason)
+	return buf
+}
+
+// ControlWriter is implemented by WebSocket connections that can send
+// control frames, such as *websocket.Conn of github.com/gorilla/websocket.
+type ControlWriter interface {
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+}
+
+// Close sends a close frame for a connection ended by err, giving up at
+// deadline. The caller remains responsible for closing the underlying
+// connection once the client has answered or the deadline has passed.
+func Close(c ControlWriter, err error, deadline time.Time) error {
+	return c.WriteControl(closeMessage, FormatCloseMessage(err), deadline)
+}
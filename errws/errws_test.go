@@ -0,0 +1,73 @@
+package errws
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	errors "github.com/noke-inc/lib_errors"
+)
+
+func TestCloseCode(t *testing.T) {
+	RegisterCode("lock_jammed", 4900)
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, CloseNormal},
+		{io.EOF, CloseInternalError},
+		{errors.WithKind(io.EOF, errors.KindPermission), 4403},
+		{errors.WithKind(io.EOF, errors.KindUnavailable), CloseTryAgainLater},
+		{errors.WithCode(errors.WithKind(io.EOF, errors.KindUnavailable), "lock_jammed"), 4900},
+	}
+	for _, tt := range tests {
+		if got := CloseCode(tt.err); got != tt.want {
+			t.Errorf("CloseCode(%v): got %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCloseReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.Wrap(io.EOF, "internal detail"), "unknown"},
+		{errors.WithKind(io.EOF, errors.KindRateLimited), "rate limited"},
+		{errors.WithUserMessage(io.EOF, "Lock\nbusy\x00"), "Lockbusy"},
+	}
+	for _, tt := range tests {
+		if got := CloseReason(tt.err); got != tt.want {
+			t.Errorf("CloseReason(%v): got %q, want %q", tt.err, got, tt.want)
+		}
+	}
+
+	long := CloseReason(errors.WithUserMessage(io.EOF, strings.Repeat("é", 100)))
+	if len(long) > maxReason || !utf8.ValidString(long) {
+		t.Errorf("CloseReason: got %d bytes, valid UTF-8 %v", len(long), utf8.ValidString(long))
+	}
+}
+
+type recorder struct {
+	messageType int
+	data        []byte
+}
+
+func (r *recorder) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	r.messageType, r.data = messageType, data
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	var r recorder
+	if err := Close(&r, errors.WithKind(io.EOF, errors.KindNotFound), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if r.messageType != 8 || binary.BigEndian.Uint16(r.data) != 4404 || string(r.data[2:]) != "not found" {
+		t.Errorf("Close: got type %d payload %q", r.messageType, r.data)
+	}
+}
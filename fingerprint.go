@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+)
+
+// KeyFingerprint is the data key under which an error received from another
+// process records the Fingerprint it had at its origin.
+const KeyFingerprint = "fingerprint"
+
+// Fingerprint returns a short hexadecimal hash identifying the kind of
+// failure err represents: its code, Kind, root cause type, and message. Two
+// errors with the same fingerprint are, for reporting purposes, the same
+// error. The message hashed is the whole of err.Error(), including the
+// messages of wrappers, so variable data such as IDs should be recorded
+// with WithData rather than formatted into messages, or every occurrence
+// gets its own fingerprint; StackFingerprint ignores messages altogether.
+// A fingerprint recorded under KeyFingerprint takes precedence, so that
+// errors propagated between processes keep the one of their origin.
+// If err is nil, Fingerprint returns "".
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	if v, ok := GetValue(err, KeyFingerprint); ok {
+		if fp, ok := v.(string); ok {
+			return fp
+		}
+	}
+	h := fnv.New64a()
+	io.WriteString(h, Code(err))
+	io.WriteString(h, "\x00")
+	io.WriteString(h, KindOf(err).String())
+	io.WriteString(h, "\x00")
+	fmt.Fprintf(h, "%T", Cause(err))
+	io.WriteString(h, "\x00")
+	io.WriteString(h, err.Error())
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// StackFingerprint returns a short hexadecimal hash of the function, file,
+// and line of every frame of err's origin stack, the stack trace recorded
+// deepest in its chain. Unlike Fingerprint it ignores messages, so log
+// pipelines can collapse identical stacks even when the messages include
+// variable data.
+// If err records no stack trace, StackFingerprint returns "".
+func StackFingerprint(err error) string {
+	st := originStack(err)
+	if len(st) == 0 {
+		return ""
+	}
+	h := fnv.New64a()
+	for _, f := range st {
+		fn, file, line := f.Location()
+		fmt.Fprintf(h, "%s\x00%s\x00%d\x00", fn, file, line)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// originStack returns the stack trace recorded deepest in err's chain, or
+// nil if there is none.
+func originStack(err error) StackTrace {
+	layers := Layers(err)
+	if i := originLayer(layers); i >= 0 {
+		return layers[i].Stack
+	}
+	return nil
+}
+
+// SameOrigin reports whether a and b were created at the same place: the
+// innermost frames of their origin stacks (see StackFingerprint) have the
+// same function, file, and line. Errors without a stack trace have the same
+// origin if their root causes are the same value, as for two errors
+// wrapping io.EOF; causes that cannot be compared, such as structs holding
+// maps, never do. It helps group occurrences of "the same bug" in tests
+// and triage tooling.
+func SameOrigin(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	sa, sb := originStack(a), originStack(b)
+	if len(sa) > 0 && len(sb) > 0 {
+		fa, filea, linea := sa[0].Location()
+		fb, fileb, lineb := sb[0].Location()
+		return fa == fb && filea == fileb && linea == lineb
+	}
+	if len(sa) > 0 || len(sb) > 0 {
+		return false
+	}
+	ca, cb := Cause(a), Cause(b)
+	ta := reflect.TypeOf(ca)
+	return ta == reflect.TypeOf(cb) && ta.Comparable() && sameValue(ca, cb)
+}
+
+// sameValue reports whether a and b are equal, or false if comparing them
+// panics, as it does for structs whose interface fields hold maps or slices.
+func sameValue(a, b error) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
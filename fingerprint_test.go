@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil): got %q, want \"\"", got)
+	}
+
+	same := []error{
+		Wrap(io.EOF, "reading"),
+		Wrap(io.EOF, "reading"),
+		WithStack(WithMessage(io.EOF, "reading")),
+	}
+	for _, err := range same[1:] {
+		if Fingerprint(err) != Fingerprint(same[0]) {
+			t.Errorf("Fingerprint(%v) differs from Fingerprint(%v)", err, same[0])
+		}
+	}
+
+	different := []error{
+		Wrap(io.EOF, "writing"),
+		WithKind(Wrap(io.EOF, "reading"), KindNotFound),
+		WithCode(Wrap(io.EOF, "reading"), "eof"),
+		Wrap(New("EOF"), "reading"),
+	}
+	for _, err := range different {
+		if Fingerprint(err) == Fingerprint(same[0]) {
+			t.Errorf("Fingerprint(%v) equals Fingerprint(%v)", err, same[0])
+		}
+	}
+
+	if got := len(Fingerprint(io.EOF)); got != 16 {
+		t.Errorf("len(Fingerprint): got %d, want 16", got)
+	}
+}
+
+func TestFingerprintRecorded(t *testing.T) {
+	err := WithData(New("remote failure"), KeyFingerprint, "00000000deadbeef")
+	if got := Fingerprint(Wrap(err, "calling lockd")); got != "00000000deadbeef" {
+		t.Errorf("Fingerprint: got %q, want the recorded fingerprint", got)
+	}
+}
+
+func TestStackFingerprint(t *testing.T) {
+	newErr := func(id int) error { return Errorf("lock %d offline", id) }
+	a, b := newErr(1), newErr(2)
+	if StackFingerprint(a) == "" || StackFingerprint(a) != StackFingerprint(Wrap(b, "unlocking")) {
+		t.Errorf("StackFingerprint: got %q and %q, want equal for the same origin", StackFingerprint(a), StackFingerprint(b))
+	}
+	if StackFingerprint(a) == StackFingerprint(Errorf("lock %d offline", 1)) {
+		t.Error("StackFingerprint: got equal for different origins")
+	}
+	if StackFingerprint(io.EOF) != "" || StackFingerprint(nil) != "" {
+		t.Error("StackFingerprint without a stack: want empty")
+	}
+}
+
+// detailError is comparable by type, but not when Detail holds a map.
+type detailError struct{ Detail interface{} }
+
+func (e detailError) Error() string { return "detail" }
+
+func TestSameOrigin(t *testing.T) {
+	newErr := func(id int) error { return Errorf("lock %d offline", id) }
+	a, b := newErr(1), Wrap(newErr(2), "unlocking")
+	tests := []struct {
+		a, b error
+		want bool
+	}{
+		{a, b, true},
+		{a, Errorf("lock %d offline", 1), false},
+		{WithMessage(io.EOF, "reading"), WithMessage(io.EOF, "scanning"), true},
+		{WithMessage(io.EOF, "reading"), io.ErrUnexpectedEOF, false},
+		{WithMessage(io.EOF, "reading"), a, false},
+		{nil, nil, true},
+		{nil, a, false},
+		{detailError{"lock"}, detailError{"lock"}, true},
+		{detailError{map[string]int{}}, detailError{map[string]int{}}, false},
+		{WithMessage(detailError{[]int{1}}, "reading"), detailError{[]int{1}}, false},
+	}
+	for i, tt := range tests {
+		if got := SameOrigin(tt.a, tt.b); got != tt.want {
+			t.Errorf("%d: SameOrigin(%v, %v): got %v, want %v", i, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package errors
+
+// A MergePolicy returns the value Flatten records under key, given the
+// values recorded under it by the errors of a chain, outermost first. It is
+// only called for keys recorded more than once.
+type MergePolicy func(key string, values []interface{}) interface{}
+
+// A FlattenOption configures a call to Flatten.
+type FlattenOption func(*flattenConfig)
+
+// flattenConfig holds the settings of a call to Flatten.
+type flattenConfig struct {
+	merge MergePolicy
+}
+
+// WithMergePolicy makes Flatten merge the values of keys recorded more than
+// once in the chain with p. A nil policy, the default, keeps the shallowest
+// value, as GetValue returns.
+func WithMergePolicy(p MergePolicy) FlattenOption {
+	return func(c *flattenConfig) { c.merge = p }
+}
+
+// DeepestValue is a MergePolicy keeping the value recorded deepest in the
+// chain, closest to the root cause.
+func DeepestValue(key string, values []interface{}) interface{} {
+	return values[len(values)-1]
+}
+
+// AllValues is a MergePolicy keeping every value, as a []interface{}
+// ordered outermost first.
+func AllValues(key string, values []interface{}) interface{} {
+	return values
+}
+
+// Flatten collapses err's chain into a single error of this package, for
+// sinks that only accept a flat record of a message, fields, and a stack
+// trace. The error has the message of err, in which the messages of the
+// chain are concatenated, the data recorded in the chain, merged as set
+// with WithMergePolicy, and the origin stack of err (see TopFrame). It does
+// not wrap the errors of the chain, so that Is and As do not match them,
+// but it keeps the marks of MarkPermanent, MarkLogged, MarkSideEffects, and
+// WithPayload. Data set with SetGlobalData is not merged, but is still
+// returned by GetAllData and printed with %+v.
+//
+//	flat := errors.Flatten(err, errors.WithMergePolicy(errors.AllValues))
+//
+// If err is nil, Flatten returns nil.
+func Flatten(err error, opts ...FlattenOption) error {
+	if err == nil {
+		return nil
+	}
+	var c flattenConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	layers := Layers(err)
+	var keys []string
+	values := make(map[string][]interface{})
+	for _, l := range layers {
+		for _, k := range sortedKeys(l.Data) {
+			if _, ok := values[k]; !ok {
+				keys = append(keys, k)
+			}
+			values[k] = append(values[k], l.Data[k])
+		}
+	}
+	data := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		switch vs := values[k]; {
+		case len(vs) == 1 || c.merge == nil:
+			data[k] = vs[0]
+		default:
+			data[k] = c.merge(k, vs)
+		}
+	}
+	var stack StackTrace
+	if i := originLayer(layers); i >= 0 {
+		stack = layers[i].Stack
+	}
+	flat := buildLayer(nil, Layer{Message: err.Error(), Data: data, Stack: stack})
+	for i := len(layers) - 1; i >= 0; i-- {
+		if emptyLayer(layers[i]) {
+			if marked, ok := rewrap(layers[i].Err, flat); ok {
+				flat = marked
+			}
+		}
+	}
+	return flat
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	err := WithData(Wrap(WithData(Wrap(io.EOF, "scanning row"), "table", "locks", "row", 3), "loading lock"), "row", 4)
+
+	flat := Flatten(err)
+	if got := len(Layers(flat)); got > 2 {
+		t.Errorf("Flatten: got %d layers, want a message and its data", got)
+	}
+	if got, want := flat.Error(), "loading lock: scanning row: EOF"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if Is(flat, io.EOF) {
+		t.Error("Is: a flattened error matched its former root cause")
+	}
+	if got, want := GetAllData(flat), map[string]interface{}{"table": "locks", "row": 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllData: got %v, want %v", got, want)
+	}
+	if got, want := originStack(flat), originStack(err); len(got) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("origin stack: got %v, want %v", got, want)
+	}
+	if got := fmt.Sprintf("%+v", flat); !strings.HasPrefix(got, "loading lock: scanning row: EOF\n") {
+		t.Errorf("%%+v: got %q", got)
+	}
+	if Flatten(nil) != nil {
+		t.Error("Flatten(nil): want nil")
+	}
+}
+
+func TestMergePolicy(t *testing.T) {
+	err := WithData(Wrap(WithData(io.EOF, "row", 3, "table", "locks"), "loading lock"), "row", 4)
+
+	tests := []struct {
+		policy MergePolicy
+		want   interface{}
+	}{
+		{nil, 4},
+		{DeepestValue, 3},
+		{AllValues, []interface{}{4, 3}},
+	}
+	for i, tt := range tests {
+		flat := Flatten(err, WithMergePolicy(tt.policy))
+		if got, _ := GetValue(flat, "row"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: row: got %v, want %v", i+1, got, tt.want)
+		}
+		if got, _ := GetValue(flat, "table"); got != "locks" {
+			t.Errorf("test %d: table: got %v, want the value recorded once", i+1, got)
+		}
+	}
+}
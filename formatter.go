@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A VerboseFormatter writes err as it should appear when formatted with %+v,
+// in place of the layout of this package. It must not format err itself
+// with %+v, which would call it again; FormatStack, FormatData, Layers, and
+// NewSnapshot give access to the pieces of err instead.
+type VerboseFormatter func(w io.Writer, err error)
+
+var formatters = struct {
+	sync.RWMutex
+	byKind map[Kind]VerboseFormatter
+	byCode map[string]VerboseFormatter
+}{
+	byKind: make(map[Kind]VerboseFormatter),
+	byCode: make(map[string]VerboseFormatter),
+}
+
+// RegisterKindFormatter makes f format errors of Kind k with %+v, so that,
+// for example, infrastructure errors print their stacks while others print
+// only their messages. RegisterKindFormatter is meant to be called during
+// program initialization.
+func RegisterKindFormatter(k Kind, f VerboseFormatter) {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.byKind[k] = f
+}
+
+// RegisterCodeFormatter makes f format errors with the code code (see
+// WithCode) with %+v. A formatter registered for an error's code takes
+// precedence over one registered for its Kind. RegisterCodeFormatter is
+// meant to be called during program initialization.
+func RegisterCodeFormatter(code string, f VerboseFormatter) {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.byCode[code] = f
+}
+
+// formatRegistered formats err with the VerboseFormatter registered for its
+// code or Kind, if any, and reports whether there was one. Each error of
+// this package calls it before writing its own %+v layout, so a formatter
+// replaces the output of the error it matches and of the errors it wraps.
+func formatRegistered(s fmt.State, err error) bool {
+	formatters.RLock()
+	empty := len(formatters.byKind) == 0 && len(formatters.byCode) == 0
+	formatters.RUnlock()
+	if empty {
+		return false
+	}
+	code, kind := Code(err), KindOf(err)
+
+	formatters.RLock()
+	f, ok := formatters.byCode[code]
+	if !ok || code == "" {
+		f, ok = formatters.byKind[kind]
+	}
+	formatters.RUnlock()
+	if !ok {
+		return false
+	}
+	f(s, err)
+	return true
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// resetFormatters removes every registered VerboseFormatter.
+func resetFormatters() {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.byKind = make(map[Kind]VerboseFormatter)
+	formatters.byCode = make(map[string]VerboseFormatter)
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	defer resetFormatters()
+	RegisterKindFormatter(KindNotFound, func(w io.Writer, err error) {
+		fmt.Fprintf(w, "not found: %v", err)
+	})
+	RegisterCodeFormatter("lock_missing", func(w io.Writer, err error) {
+		fmt.Fprintf(w, "[lock_missing] %v", err)
+	})
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(WithKind(New("boom"), KindNotFound), "loading"), "not found: loading: boom"},
+		{WithCode(WithKind(New("boom"), KindNotFound), "lock_missing"), "[lock_missing] boom"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf("%+v", tt.err); got != tt.want {
+			t.Errorf("%%+v: got %q, want %q", got, tt.want)
+		}
+	}
+	if got := fmt.Sprintf("%+v", WithKind(New("boom"), KindInternal)); !strings.HasPrefix(got, "boom\n") {
+		t.Errorf("%%+v without a formatter: got %q, want the default layout", got)
+	}
+}
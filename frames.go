@@ -0,0 +1,89 @@
+package errors
+
+import "strings"
+
+// TopFrame returns the innermost frame of err's origin stack, the stack
+// trace recorded deepest in its chain, and whether there is one.
+func TopFrame(err error) (Frame, bool) {
+	st := originStack(err)
+	if len(st) == 0 {
+		return 0, false
+	}
+	return st[0], true
+}
+
+// FramesIn returns the frames of err's origin stack, innermost first, whose
+// function belongs to the package pkgPrefix or to a package below it, so
+// that application code can tell which of its own functions an error came
+// through.
+func FramesIn(err error, pkgPrefix string) []Frame {
+	var frames []Frame
+	for _, f := range originStack(err) {
+		if inPackage(f, pkgPrefix) {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// FirstFrameOutside returns the innermost frame of err's origin stack whose
+// function does not belong to the package pkgPrefix or to a package below
+// it, and whether there is one. Passing the path of a helper package finds
+// the application code that called it.
+func FirstFrameOutside(err error, pkgPrefix string) (Frame, bool) {
+	for _, f := range originStack(err) {
+		if !inPackage(f, pkgPrefix) {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// inPackage reports whether the function of f belongs to the package
+// pkgPrefix or to a package below it.
+func inPackage(f Frame, pkgPrefix string) bool {
+	pkg := packagePath(f.name())
+	pkgPrefix = strings.TrimSuffix(pkgPrefix, "/")
+	return pkg == pkgPrefix || strings.HasPrefix(pkg, pkgPrefix+"/")
+}
+
+// packagePath returns the import path of the package of the function named
+// name, as reported by runtime.Func.Name, which escapes the dots of the
+// last path element as %2e.
+func packagePath(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		name = name[:slash+1+dot]
+	}
+	return strings.ReplaceAll(name, "%2e", ".")
+}
+
+// PackageOf returns the import path of the package of the function at the
+// top of err's origin stack (see TopFrame), such as
+// "github.com/acme/store", or "" if err records no stack trace. It suits
+// metrics labels and the routing of alerts to owning teams.
+func PackageOf(err error) string {
+	f, ok := TopFrame(err)
+	if !ok {
+		return ""
+	}
+	return packagePath(f.name())
+}
+
+// FunctionOf returns the name of the function at the top of err's origin
+// stack (see TopFrame), qualified by the last element of its package path,
+// such as "store.(*Repo).Save", or "" if err records no stack trace.
+func FunctionOf(err error) string {
+	f, ok := TopFrame(err)
+	if !ok {
+		return ""
+	}
+	return shortFuncName(f.name())
+}
+
+// shortFuncName returns the function named name, as reported by
+// runtime.Func.Name, qualified by the last element of its package path.
+func shortFuncName(name string) string {
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.ReplaceAll(name, "%2e", ".")
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestFrameQueries(t *testing.T) {
+	helper := FakeFrame("github.com/acme/dbutil.(*Tx).Exec", "/src/dbutil/tx.go", 12)
+	store := FakeFrame("github.com/acme/store.(*Repo).Save", "/src/store/repo.go", 40)
+	api := FakeFrame("github.com/acme/store/api.handle", "/src/store/api/api.go", 8)
+	front := FakeFrame("github.com/acme/storefront.main", "/src/storefront/main.go", 3)
+	prev := SetStackProvider(FixedStack(helper, store, api, front))
+	err := New("boom")
+	SetStackProvider(prev)
+	err = Wrap(err, "saving")
+
+	if f, ok := TopFrame(err); !ok || f != helper {
+		t.Errorf("TopFrame: got %v, %v, want the innermost origin frame", f, ok)
+	}
+	if got := FramesIn(err, "github.com/acme/store"); len(got) != 2 || got[0] != store || got[1] != api {
+		t.Errorf("FramesIn: got %v, want the store and store/api frames", got)
+	}
+	if f, ok := FirstFrameOutside(err, "github.com/acme/dbutil/"); !ok || f != store {
+		t.Errorf("FirstFrameOutside: got %v, %v, want the store frame", f, ok)
+	}
+
+	if got := PackageOf(err); got != "github.com/acme/dbutil" {
+		t.Errorf("PackageOf: got %q", got)
+	}
+	if got := FunctionOf(err); got != "dbutil.(*Tx).Exec" {
+		t.Errorf("FunctionOf: got %q", got)
+	}
+	if PackageOf(io.EOF) != "" || FunctionOf(io.EOF) != "" {
+		t.Error("PackageOf, FunctionOf without a stack: want empty")
+	}
+
+	if _, ok := TopFrame(io.EOF); ok {
+		t.Error("TopFrame without a stack: got true")
+	}
+	if _, ok := FirstFrameOutside(err, "github.com/acme"); ok {
+		t.Error("FirstFrameOutside with every frame inside: got true")
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	tests := map[string]string{
+		"github.com/acme/store.(*Repo).Save": "github.com/acme/store",
+		"github.com/acme/store.Save.func1":   "github.com/acme/store",
+		"main.main":                          "main",
+		"runtime.goexit":                     "runtime",
+		"gopkg.in/yaml%2ev3.Unmarshal":       "gopkg.in/yaml.v3",
+	}
+	for name, want := range tests {
+		if got := packagePath(name); got != want {
+			t.Errorf("packagePath(%q): got %q, want %q", name, got, want)
+		}
+	}
+}
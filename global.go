@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// globalData holds the map[string]interface{} set by SetGlobalData.
+var globalData atomic.Value
+
+// SetGlobalData replaces the key/value pairs that are reported as part of
+// every error, such as the service name, region, or pod. keyVals follow the
+// same rules as for WithData. The pairs are not stored in errors; they are
+// merged in when errors are inspected with GetValue or GetAllData and printed
+// with %+v, with values recorded in the error taking precedence.
+// Calling SetGlobalData with no arguments clears the global data.
+func SetGlobalData(keyVals ...interface{}) {
+	globalData.Store(dataMap(keyVals))
+}
+
+// GlobalData returns a copy of the key/value pairs set with SetGlobalData.
+func GlobalData() map[string]interface{} {
+	data, _ := globalData.Load().(map[string]interface{})
+	kv := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		kv[k] = v
+	}
+	return kv
+}
+
+// globalValue returns the value set for key with SetGlobalData.
+func globalValue(key string) (interface{}, bool) {
+	data, _ := globalData.Load().(map[string]interface{})
+	v, ok := data[key]
+	return v, ok
+}
+
+// GetAllData returns every key/value pair recorded in err's chain merged over
+// the global data set with SetGlobalData. For a duplicated key the shallowest
+// value wins, and values recorded in err win over global ones.
+// If err is nil, GetAllData returns nil.
+func GetAllData(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	kv := GlobalData()
+	for k, v := range treeData(err) {
+		kv[k] = v
+	}
+	return kv
+}
+
+// formatGlobalData writes the global data section printed with %+v by the
+// deepest error of this package in a chain.
+func formatGlobalData(s fmt.State) {
+	data, _ := globalData.Load().(map[string]interface{})
+	if len(data) > 0 {
+		c := formatConfig()
+		fmt.Fprintf(s, "%s%s%s", c.Separator, c.GlobalDataLabel, formatDataMap(data))
+	}
+}
+
+// isDeepest reports whether no error of this package occurs in the chain
+// starting at err, meaning the error wrapping err is the deepest one.
+func isDeepest(err error) bool {
+	for err != nil {
+		switch err.(type) {
+		case *fundamental, *withStack, *withMessage, *withData, *withArgs, *PanicError, *withSecondary, *withSuppressed, *translated, *sealed:
+			return false
+		}
+		err = Unwrap(err)
+	}
+	return true
+}
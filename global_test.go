@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGlobalData(t *testing.T) {
+	defer SetGlobalData()
+	SetGlobalData("service", "lockd", "region", "us-west-2", 12, "skipped")
+
+	err := WithData(Wrap(io.EOF, "reading"), "region", "eu-central-1", "lock_id", 7)
+
+	tests := []struct {
+		err    error
+		key    string
+		want   interface{}
+		wantOk bool
+	}{
+		{nil, "service", nil, false},
+		{io.EOF, "service", "lockd", true},
+		{err, "service", "lockd", true},
+		{err, "region", "eu-central-1", true},
+		{err, "lock_id", 7, true},
+		{err, "missing", nil, false},
+	}
+	for i, tt := range tests {
+		got, ok := GetValue(tt.err, tt.key)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: GetValue(%v, %q): got (%v, %v), want (%v, %v)", i+1, tt.err, tt.key, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	want := map[string]interface{}{"service": "lockd", "region": "eu-central-1", "lock_id": 7}
+	if got := GetAllData(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllData: got %v, want %v", got, want)
+	}
+	if got := GetAllData(nil); got != nil {
+		t.Errorf("GetAllData(nil): got %v, want nil", got)
+	}
+
+	// the global data is printed once, by the deepest error of this package
+	for _, e := range []error{err, New("new"), WithMessage(io.EOF, "msg"), WithData(io.EOF, "key", 1)} {
+		got := fmt.Sprintf("%+v", e)
+		if n := strings.Count(got, "GLOBAL DATA: map[region:us-west-2 service:lockd]"); n != 1 {
+			t.Errorf("%%+v of %v: global data printed %d times:\n%s", e, n, got)
+		}
+	}
+
+	SetGlobalData()
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "GLOBAL DATA") {
+		t.Errorf("%%+v after clearing global data: got %q", got)
+	}
+	if got := GlobalData(); len(got) != 0 {
+		t.Errorf("GlobalData after clearing: got %v", got)
+	}
+}
+
+func TestGlobalDataReservedKeys(t *testing.T) {
+	defer SetGlobalData()
+	SetGlobalData(KeyKind, KindNotFound, KeyCode, "E_GLOBAL", KeyStatusCode, 418)
+
+	err := Wrap(io.EOF, "reading")
+	if got := KindOf(err); got != KindUnknown {
+		t.Errorf("KindOf: got %v, want %v", got, KindUnknown)
+	}
+	if got := Code(err); got != "" {
+		t.Errorf("Code: got %q, want none", got)
+	}
+	if got := HTTPStatus(err); got != 500 {
+		t.Errorf("HTTPStatus: got %d, want 500", got)
+	}
+	if got := GetAllData(err)[KeyCode]; got != "E_GLOBAL" {
+		t.Errorf("GetAllData: got %v, want the global data listed", got)
+	}
+}
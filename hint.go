@@ -0,0 +1,33 @@
+package errors
+
+// KeyHint is the data key under which WithHint records a suggestion for
+// fixing an error.
+const KeyHint = "hint"
+
+// WithHint annotates err with a suggestion, safe to show to end users, for
+// fixing the failure, such as "check that the lock is within BLE range", so
+// that the code that best understands a failure can help those who run into
+// it. Render, and the errhttp renderers, show the hints of an error below
+// its message.
+// If err is nil, WithHint returns nil.
+func WithHint(err error, hint string) error {
+	return WithData(err, KeyHint, hint)
+}
+
+// Hints returns the hints recorded with WithHint anywhere in err's chain,
+// outermost first, following the same errors as Walk. Duplicate hints are
+// returned once.
+func Hints(err error) []string {
+	var hints []string
+	seen := make(map[string]bool)
+	Walk(err, func(e error) bool {
+		if d, ok := e.(*withData); ok {
+			if hint, ok := d.data[KeyHint].(string); ok && !seen[hint] {
+				seen[hint] = true
+				hints = append(hints, hint)
+			}
+		}
+		return true
+	})
+	return hints
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestHints(t *testing.T) {
+	ble := "check that the lock is within BLE range"
+	battery := "replace the lock's batteries"
+	tests := []struct {
+		err  error
+		want []string
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{WithHint(io.EOF, ble), []string{ble}},
+		{WithHint(Wrap(WithHint(io.EOF, ble), "unlocking"), battery), []string{battery, ble}},
+		{WithHint(WithHint(io.EOF, ble), ble), []string{ble}},
+		{Join(WithHint(io.EOF, ble), WithHint(io.ErrUnexpectedEOF, battery)), []string{ble, battery}},
+	}
+
+	for i, tt := range tests {
+		if got := Hints(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Hints(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithHint(nil, ble); got != nil {
+		t.Errorf("WithHint(nil): got %#v, expected nil", got)
+	}
+}
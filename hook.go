@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// HookOp identifies the kind of construction that triggered a Hook.
+type HookOp uint8
+
+const (
+	// HookNew is reported for errors created by New, Errorf, and
+	// Validation.Err.
+	HookNew HookOp = iota
+	// HookWrap is reported for errors created by Wrap, Wrapf, WrapWithData,
+	// WithStack, WithMessage, WithMessagef, the other Wrap helpers, and
+	// Retry.
+	HookWrap
+	// HookWithData is reported for errors created by WithData and the
+	// helpers built on it, such as WithKind and WithCode.
+	HookWithData
+)
+
+func (op HookOp) String() string {
+	switch op {
+	case HookNew:
+		return "new"
+	case HookWrap:
+		return "wrap"
+	case HookWithData:
+		return "with_data"
+	}
+	return "unknown"
+}
+
+// A Hook observes every error constructed by this package and returns the
+// error to give the caller instead: err itself, or err enriched, for
+// example with WithData. err is the newly constructed error, as returned by
+// the previous Hook; a Hook returning nil leaves it unchanged. Hooks run
+// synchronously on the constructing goroutine, so they should be cheap and
+// must not panic. Errors constructed by a Hook run the hooks too, so a Hook
+// enriching err must leave alone the errors of its own op, such as
+// HookWithData for WithData:
+//
+//	errors.RegisterHook(func(err error, op errors.HookOp) error {
+//	        if op != errors.HookNew {
+//	                return err
+//	        }
+//	        return errors.WithData(err, "host", hostname)
+//	})
+type Hook func(err error, op HookOp) error
+
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Value // []Hook
+)
+
+// RegisterHook adds h to the hooks run whenever this package constructs an
+// error, allowing applications to implement auditing, sampling, or metrics in
+// one place. Hooks run in the order they were registered. RegisterHook is
+// meant to be called during program initialization.
+func RegisterHook(h Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	old, _ := hooks.Load().([]Hook)
+	hs := make([]Hook, len(old), len(old)+1)
+	copy(hs, old)
+	hooks.Store(append(hs, h))
+}
+
+// runHooks runs the registered hooks for err and returns the error returned
+// by the last one.
+func runHooks(err error, op HookOp) error {
+	hs, _ := hooks.Load().([]Hook)
+	for _, h := range hs {
+		if hooked := h(err, op); hooked != nil {
+			err = hooked
+		}
+	}
+	return err
+}
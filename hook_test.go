@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// resetHooks removes every registered hook.
+func resetHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks.Store([]Hook(nil))
+}
+
+func TestRegisterHook(t *testing.T) {
+	defer resetHooks()
+
+	var ops []HookOp
+	var last error
+	RegisterHook(func(err error, op HookOp) error {
+		ops = append(ops, op)
+		last = err
+		return err
+	})
+	var count int
+	RegisterHook(func(error, HookOp) error { count++; return nil })
+
+	tests := []struct {
+		fn   func() error
+		want []HookOp
+	}{
+		{func() error { return New("new") }, []HookOp{HookNew}},
+		{func() error { return Errorf("new %d", 1) }, []HookOp{HookNew}},
+		{func() error { return Wrap(io.EOF, "wrap") }, []HookOp{HookWrap}},
+		{func() error { return Wrapf(io.EOF, "wrap %d", 1) }, []HookOp{HookWrap}},
+		{func() error { return WithStack(io.EOF) }, []HookOp{HookWrap}},
+		{func() error { return WithMessage(io.EOF, "msg") }, []HookOp{HookWrap}},
+		{func() error { return WithMessagef(io.EOF, "msg %d", 1) }, []HookOp{HookWrap}},
+		{func() error { return WithData(io.EOF, "key", "val") }, []HookOp{HookWithData}},
+		{func() error { return WrapWithData(io.EOF, "msg", "key", "val") }, []HookOp{HookWrap}},
+		{func() error {
+			return Retry(context.Background(), 1, nil, func(context.Context) error { return io.EOF })
+		}, []HookOp{HookWrap}},
+		{func() error { return WithKind(io.EOF, KindInternal) }, []HookOp{HookWithData}},
+		{func() error { return Wrap(nil, "nil") }, nil},
+	}
+
+	for i, tt := range tests {
+		ops, last, count = nil, nil, 0
+		err := tt.fn()
+		if !reflect.DeepEqual(ops, tt.want) {
+			t.Errorf("test %d: got ops %v, want %v", i+1, ops, tt.want)
+		}
+		if last != err {
+			t.Errorf("test %d: hook saw %v, constructor returned %v", i+1, last, err)
+		}
+		if count != len(tt.want) {
+			t.Errorf("test %d: second hook ran %d times, want %d", i+1, count, len(tt.want))
+		}
+	}
+}
+
+func TestHookEnrich(t *testing.T) {
+	defer resetHooks()
+
+	RegisterHook(func(err error, op HookOp) error {
+		if op != HookNew {
+			return err
+		}
+		return WithData(err, "hook_host", "lock-gw-1")
+	})
+	var seen error
+	RegisterHook(func(err error, op HookOp) error {
+		seen = err
+		return err
+	})
+
+	err := Wrap(New("lock offline"), "reading")
+	if v, _ := GetValue(err, "hook_host"); v != "lock-gw-1" {
+		t.Errorf("GetValue(hook_host): got %v, want the data added by the hook", v)
+	}
+	if err.Error() != "reading: lock offline" {
+		t.Errorf("Error: got %q", err.Error())
+	}
+	if seen != err {
+		t.Errorf("last hook saw %v, constructor returned %v", seen, err)
+	}
+}
+
+func TestHookOpString(t *testing.T) {
+	tests := []struct {
+		op   HookOp
+		want string
+	}{
+		{HookNew, "new"},
+		{HookWrap, "wrap"},
+		{HookWithData, "with_data"},
+		{HookOp(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("HookOp(%d).String(): got %q, want %q", tt.op, got, tt.want)
+		}
+	}
+}
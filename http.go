@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// HTTPResponseHeaders lists the response headers recorded by WrapHTTPResponse.
+// Header names are canonicalized before lookup.
+var HTTPResponseHeaders = []string{
+	"Content-Type",
+	"Retry-After",
+	"Www-Authenticate",
+	"X-Request-Id",
+	"X-Amzn-Requestid",
+	"X-Amz-Request-Id",
+}
+
+// HTTPBodySnippetLimit is the maximum number of response body bytes recorded
+// by WrapHTTPResponse.
+var HTTPBodySnippetLimit = 512
+
+// WrapHTTPResponse returns an error annotating err with a stack trace at the
+// point WrapHTTPResponse is called, a message naming the request, and the
+// following key/value pairs describing the outbound call:
+//
+//	http_method   request method
+//	http_url      request URL with query values and password redacted
+//	http_status   response status code
+//	http_headers  the HTTPResponseHeaders present in the response
+//	http_body     up to HTTPBodySnippetLimit bytes of the response body
+//
+// A valid Retry-After response header is also recorded as the RetryAfter hint.
+//
+// req may be nil, in which case resp.Request is used. resp may be nil (for
+// example when the transport failed), in which case only the request is
+// described. The body snippet is read from resp.Body, which is replaced so
+// that the caller can still read the full body afterwards.
+// If err is nil, WrapHTTPResponse returns nil.
+func WrapHTTPResponse(err error, req *http.Request, resp *http.Response) error {
+	if err == nil {
+		return nil
+	}
+	if req == nil && resp != nil {
+		req = resp.Request
+	}
+
+	msg := "http request"
+	var keyVals []interface{}
+	if req != nil {
+		method := req.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		msg = method
+		keyVals = append(keyVals, "http_method", method)
+		if req.URL != nil {
+			u := redactURL(req.URL)
+			msg += " " + u
+			keyVals = append(keyVals, "http_url", u)
+		}
+	}
+	if resp != nil {
+		keyVals = append(keyVals, "http_status", resp.StatusCode)
+		headers := make(map[string]string)
+		for _, name := range HTTPResponseHeaders {
+			if v := resp.Header.Get(name); v != "" {
+				headers[http.CanonicalHeaderKey(name)] = v
+			}
+		}
+		if len(headers) > 0 {
+			keyVals = append(keyVals, "http_headers", headers)
+		}
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now()); ok {
+			keyVals = append(keyVals, KeyRetryAfter, d)
+		}
+		if snippet := bodySnippet(resp); snippet != "" {
+			keyVals = append(keyVals, "http_body", snippet)
+		}
+	}
+
+	err = &withMessage{
+		error: err,
+		msg:   msg,
+	}
+	err = attachData(err, keyVals)
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// redactURL returns u as a string with its password and every query value
+// replaced, leaving the query keys visible.
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.RawQuery != "" {
+		q := r.Query()
+		for k, vs := range q {
+			for i := range vs {
+				vs[i] = "REDACTED"
+			}
+			q[k] = vs
+		}
+		r.RawQuery = q.Encode()
+	}
+	return r.Redacted()
+}
+
+// bodySnippet reads up to HTTPBodySnippetLimit bytes from resp.Body and
+// restores the body so that it can be read again from the beginning.
+func bodySnippet(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody || HTTPBodySnippetLimit <= 0 {
+		return ""
+	}
+	// Read one byte more than the limit to tell whether the body is truncated.
+	buf, _ := io.ReadAll(io.LimitReader(resp.Body, int64(HTTPBodySnippetLimit)+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), resp.Body), resp.Body}
+	if len(buf) > HTTPBodySnippetLimit {
+		warnData(DataTruncated, "http_body", fmt.Sprintf("truncated to %d bytes", HTTPBodySnippetLimit))
+		buf = buf[:HTTPBodySnippetLimit]
+	}
+	return string(buf)
+}
+
+// KeyStatusCode is the data key under which WithHTTPStatus records the HTTP
+// status that should be used when err is returned to an HTTP client.
+const KeyStatusCode = "status_code"
+
+// WithHTTPStatus annotates err with the HTTP status that should be used when
+// it is returned to an HTTP client.
+// If err is nil, WithHTTPStatus returns nil.
+func WithHTTPStatus(err error, status int) error {
+	return WithData(err, KeyStatusCode, status)
+}
+
+// StatusClientClosedRequest is the non-standard HTTP status, introduced by
+// nginx, that HTTPStatus returns for errors of KindCanceled: the client went
+// away before it could be answered.
+const StatusClientClosedRequest = 499
+
+// HTTPStatusText returns the text of the HTTP status code, as
+// http.StatusText does, including StatusClientClosedRequest.
+func HTTPStatusText(code int) string {
+	if code == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(code)
+}
+
+// HTTPStatus returns the HTTP status that should be used when err is returned
+// to an HTTP client: the shallowest status recorded with WithHTTPStatus or,
+// failing that, the status matching the error's Kind.
+// If err is nil, HTTPStatus returns http.StatusOK.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if v, ok := GetValue(err, KeyStatusCode); ok {
+		if status, ok := v.(int); ok {
+			return status
+		}
+	}
+	switch KindOf(err) {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindPermission:
+		return http.StatusForbidden
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		return StatusClientClosedRequest
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
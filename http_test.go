@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrapHTTPResponseNil(t *testing.T) {
+	got := WrapHTTPResponse(nil, nil, nil)
+	if got != nil {
+		t.Errorf("WrapHTTPResponse(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestWrapHTTPResponse(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/locks?token=secret&id=7", nil)
+	body := strings.Repeat("x", HTTPBodySnippetLimit+10)
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header: http.Header{
+			"Content-Type": {"text/plain"},
+			"Retry-After":  {"30"},
+			"Set-Cookie":   {"session=1"},
+		},
+		Body:    io.NopCloser(strings.NewReader(body)),
+		Request: req,
+	}
+
+	err := WrapHTTPResponse(New("unexpected status"), nil, resp)
+	want := "POST https://api.example.com/v1/locks?id=REDACTED&token=REDACTED: unexpected status"
+	if got := err.Error(); got != want {
+		t.Errorf("WrapHTTPResponse: got %q, want %q", got, want)
+	}
+
+	type dataCacher interface {
+		DataCache() map[string]interface{}
+	}
+	var d dataCacher
+	if !As(err, &d) {
+		t.Fatalf("WrapHTTPResponse: not a dataCacher %v", reflect.TypeOf(err))
+	}
+	wantedData := map[string]interface{}{
+		"http_method": "POST",
+		"http_url":    "https://api.example.com/v1/locks?id=REDACTED&token=REDACTED",
+		"http_status": http.StatusServiceUnavailable,
+		"http_headers": map[string]string{
+			"Content-Type": "text/plain",
+			"Retry-After":  "30",
+		},
+		"http_body":   body[:HTTPBodySnippetLimit],
+		KeyRetryAfter: 30 * time.Second,
+	}
+	if kv := d.DataCache(); !reflect.DeepEqual(kv, wantedData) {
+		t.Errorf("WrapHTTPResponse: got %v, want %v", kv, wantedData)
+	}
+
+	rest, _ := io.ReadAll(resp.Body)
+	if string(rest) != body {
+		t.Errorf("WrapHTTPResponse: body not restored, got %d bytes, want %d", len(rest), len(body))
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{io.EOF, http.StatusInternalServerError},
+		{WithKind(io.EOF, KindNotFound), http.StatusNotFound},
+		{WithKind(io.EOF, KindRateLimited), http.StatusTooManyRequests},
+		{WithKind(io.EOF, KindCanceled), StatusClientClosedRequest},
+		{WithHTTPStatus(WithKind(io.EOF, KindNotFound), http.StatusGone), http.StatusGone},
+		{Wrap(WithHTTPStatus(io.EOF, http.StatusTeapot), "wrapped"), http.StatusTeapot},
+	}
+
+	for i, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("test %d: HTTPStatus(%v): got %d, want %d", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := HTTPStatusText(StatusClientClosedRequest); got != "Client Closed Request" {
+		t.Errorf("HTTPStatusText(499): got %q", got)
+	}
+	if got := HTTPStatusText(http.StatusNotFound); got != "Not Found" {
+		t.Errorf("HTTPStatusText(404): got %q", got)
+	}
+}
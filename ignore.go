@@ -0,0 +1,101 @@
+package errors
+
+// Ignore returns nil if err matches any of targets according to Is, and err
+// otherwise, replacing blocks such as:
+//
+//	if errors.Is(err, io.EOF) {
+//	        err = nil
+//	}
+//
+// If err is or wraps an error wrapping several others, such as one built
+// with Join, only the branches that match are dropped; see Filter.
+func Ignore(err error, targets ...error) error {
+	return Filter(err, targets...)
+}
+
+// IgnoreFunc returns nil if err is non-nil and ignore(err) is true, and err
+// otherwise. Errors wrapping several others are handled as by FilterFunc.
+func IgnoreFunc(err error, ignore func(error) bool) error {
+	return FilterFunc(err, ignore)
+}
+
+// Filter returns err without the errors matching any of targets according
+// to Is. When err wraps several errors, directly or under wrappers (see
+// Walk), each of them is filtered, and the remaining ones are returned
+// joined with Join under the same wrappers, or nil if none remain.
+func Filter(err error, targets ...error) error {
+	return FilterFunc(err, func(err error) bool {
+		for _, target := range targets {
+			if Is(err, target) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterFunc is like Filter, except that the errors dropped are those for
+// which ignore returns true. Each error wrapped by a multi-error is passed
+// to ignore under the wrappers of the multi-error, so that, for example,
+// the Kind recorded on a Join applies to every branch.
+func FilterFunc(err error, ignore func(error) bool) error {
+	filtered, _ := filter(err, ignore)
+	return filtered
+}
+
+// filter implements FilterFunc, and reports whether anything was dropped.
+func filter(err error, ignore func(error) bool) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if errs, ok := multiErrors(err); ok {
+		kept := make([]error, 0, len(errs))
+		var changed bool
+		for _, e := range errs {
+			k, c := filter(e, ignore)
+			kept = append(kept, k)
+			changed = changed || c
+		}
+		if !changed {
+			return err, false
+		}
+		return Join(kept...), true
+	}
+	if !wrapsMulti(err) {
+		if ignore(err) {
+			return nil, true
+		}
+		return err, false
+	}
+	inner, changed := filter(Unwrap(err), func(e error) bool {
+		return ignore(replaceInner(err, e))
+	})
+	if !changed {
+		return err, false
+	}
+	if inner == nil {
+		return nil, true
+	}
+	return replaceInner(err, inner), true
+}
+
+// wrapsMulti reports whether an error wrapping several others is found by
+// unwrapping err.
+func wrapsMulti(err error) bool {
+	for err = Unwrap(err); err != nil; err = Unwrap(err) {
+		if _, ok := multiErrors(err); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceInner returns err rebuilt over inner instead of the error it
+// wraps: a copy of err if it is a wrapper of this package, and an error of
+// this package recording its Layer otherwise.
+func replaceInner(err, inner error) error {
+	if e, ok := rewrap(err, inner); ok {
+		return e
+	}
+	return buildLayer(inner, layerOf(err))
+}
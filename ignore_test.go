@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	wrapped := Wrap(io.EOF, "reading")
+	tests := []struct {
+		err     error
+		targets []error
+		want    error
+	}{
+		{nil, []error{io.EOF}, nil},
+		{wrapped, []error{io.EOF}, nil},
+		{wrapped, []error{context.Canceled, io.EOF}, nil},
+		{wrapped, []error{context.Canceled}, wrapped},
+		{wrapped, nil, wrapped},
+	}
+	for _, tt := range tests {
+		if got := Ignore(tt.err, tt.targets...); got != tt.want {
+			t.Errorf("Ignore(%v, %v): got %v, want %v", tt.err, tt.targets, got, tt.want)
+		}
+	}
+
+	notFound := WithKind(New("missing"), KindNotFound)
+	isNotFound := func(err error) bool { return KindOf(err) == KindNotFound }
+	if IgnoreFunc(notFound, isNotFound) != nil || IgnoreFunc(wrapped, isNotFound) != wrapped {
+		t.Error("IgnoreFunc: wrong result")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	other := New("other")
+	err := Join(io.EOF, Join(other, io.EOF))
+	got := Filter(err, io.EOF)
+	if got == nil || got.Error() != "other" || !Is(got, other) || Is(got, io.EOF) {
+		t.Errorf("Filter: got %v", got)
+	}
+	if got := Filter(Join(io.EOF, Wrap(io.EOF, "reading")), io.EOF); got != nil {
+		t.Errorf("Filter of only ignored errors: got %v, want nil", got)
+	}
+	if got := Filter(other, io.EOF); got != other {
+		t.Errorf("Filter of a single error: got %v, want it unchanged", got)
+	}
+}
+
+func TestIgnoreJoin(t *testing.T) {
+	other := New("other")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Join(io.EOF, other), "other"},
+		{Wrap(Join(io.EOF, other), "closing"), "closing: other"},
+		{WithKind(fmt.Errorf("closing: %w", Join(other, io.EOF)), KindInternal), "closing: other"},
+		{Wrap(Join(io.EOF, Wrap(io.EOF, "reading")), "closing"), ""},
+	}
+	for _, tt := range tests {
+		got := Ignore(tt.err, io.EOF)
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("Ignore(%q): got %q, want nil", tt.err, got)
+			}
+			continue
+		}
+		if got == nil || got.Error() != tt.want || !Is(got, other) || Is(got, io.EOF) {
+			t.Errorf("Ignore(%q): got %v, want %q", tt.err, got, tt.want)
+		}
+	}
+
+	unchanged := Wrap(Join(other, context.Canceled), "closing")
+	if got := Filter(unchanged, io.EOF); got != unchanged {
+		t.Errorf("Filter without a match: got %v, want err unchanged", got)
+	}
+
+	// The Kind recorded on the Join applies to each branch.
+	notFound := WithKind(Join(io.EOF, other), KindNotFound)
+	if got := IgnoreFunc(notFound, func(err error) bool { return KindOf(err) == KindNotFound }); got != nil {
+		t.Errorf("IgnoreFunc by Kind: got %v, want nil", got)
+	}
+	if got := KindOf(Ignore(notFound, io.EOF)); got != KindNotFound {
+		t.Errorf("KindOf after Ignore: got %v, want %v", got, KindNotFound)
+	}
+}
@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"encoding/json"
+)
+
+// jsonExcerptRadius is the number of bytes of input kept on either side of
+// the offending offset when WrapJSONDecode records an excerpt.
+const jsonExcerptRadius = 24
+
+// WrapJSONDecode returns an error annotating a JSON decoding failure with a
+// stack trace at the point WrapJSONDecode is called and with details pulled
+// from the underlying *json.SyntaxError or *json.UnmarshalTypeError:
+//
+//	json_offset    byte offset into input where decoding failed
+//	json_line      1-based line of the offset
+//	json_column    1-based column of the offset
+//	json_field     struct field path being decoded (type errors only)
+//	json_expected  Go type that was expected (type errors only)
+//	json_actual    JSON value type that was found (type errors only)
+//	json_excerpt   a short excerpt of input surrounding the offset
+//
+// input should be the document that was being decoded; it may be nil, in
+// which case no line, column, or excerpt is recorded.
+// If err is nil, WrapJSONDecode returns nil.
+func WrapJSONDecode(err error, input []byte) error {
+	if err == nil {
+		return nil
+	}
+	var keyVals []interface{}
+	offset := int64(-1)
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case As(err, &typeErr):
+		offset = typeErr.Offset
+		keyVals = append(keyVals,
+			"json_field", typeErr.Field,
+			"json_expected", typeErr.Type.String(),
+			"json_actual", typeErr.Value,
+		)
+	}
+	if offset >= 0 {
+		keyVals = append(keyVals, "json_offset", offset)
+		if input != nil {
+			line, col := jsonPosition(input, offset)
+			keyVals = append(keyVals,
+				"json_line", line,
+				"json_column", col,
+				"json_excerpt", jsonExcerpt(input, offset),
+			)
+		}
+	}
+
+	err = &withMessage{
+		error: err,
+		msg:   "json decode",
+	}
+	err = attachData(err, keyVals)
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// jsonPosition converts a byte offset reported by encoding/json into a
+// 1-based line and column within input.
+func jsonPosition(input []byte, offset int64) (line, col int) {
+	if offset > int64(len(input)) {
+		offset = int64(len(input))
+	}
+	line, col = 1, 1
+	for _, b := range input[:offset] {
+		if b == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
+// jsonExcerpt returns the bytes of input surrounding offset, bounded by
+// jsonExcerptRadius on either side.
+func jsonExcerpt(input []byte, offset int64) string {
+	start := offset - jsonExcerptRadius
+	if start < 0 {
+		start = 0
+	}
+	end := offset + jsonExcerptRadius
+	if end > int64(len(input)) {
+		end = int64(len(input))
+	}
+	if start > end {
+		start = end
+	}
+	return string(input[start:end])
+}
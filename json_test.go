@@ -2,6 +2,7 @@ package errors
 
 import (
 	"encoding/json"
+	"reflect"
 	"regexp"
 	"testing"
 )
@@ -49,3 +50,64 @@ func TestFrameMarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapJSONDecodeNil(t *testing.T) {
+	got := WrapJSONDecode(nil, []byte("{}"))
+	if got != nil {
+		t.Errorf("WrapJSONDecode(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestWrapJSONDecode(t *testing.T) {
+	type lock struct {
+		Battery int `json:"battery"`
+	}
+	syntaxInput := []byte("{\n  \"battery\": 9x\n}")
+	typeInput := []byte(`{"battery": "full"}`)
+
+	var v lock
+	tests := []struct {
+		input      []byte
+		want       string
+		wantedData map[string]interface{}
+	}{{
+		syntaxInput,
+		"json decode: invalid character 'x' after object key:value pair",
+		map[string]interface{}{
+			"json_offset":  int64(17),
+			"json_line":    2,
+			"json_column":  16,
+			"json_excerpt": "{\n  \"battery\": 9x\n}",
+		},
+	}, {
+		typeInput,
+		"json decode: json: cannot unmarshal string into Go struct field lock.battery of type int",
+		map[string]interface{}{
+			"json_offset":   int64(18),
+			"json_line":     1,
+			"json_column":   19,
+			"json_excerpt":  `{"battery": "full"}`,
+			"json_field":    "battery",
+			"json_expected": "int",
+			"json_actual":   "string",
+		},
+	}}
+
+	type dataCacher interface {
+		DataCache() map[string]interface{}
+	}
+	var d dataCacher
+
+	for i, tt := range tests {
+		err := WrapJSONDecode(json.Unmarshal(tt.input, &v), tt.input)
+		if got := err.Error(); got != tt.want {
+			t.Errorf("test %d: WrapJSONDecode: got %q, want %q", i+1, got, tt.want)
+		}
+		if !As(err, &d) {
+			t.Fatalf("test %d: WrapJSONDecode: not a dataCacher %v", i+1, reflect.TypeOf(err))
+		}
+		if kv := d.DataCache(); !reflect.DeepEqual(kv, tt.wantedData) {
+			t.Errorf("test %d: WrapJSONDecode: got %v, want %v", i+1, kv, tt.wantedData)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// MaxKeyLength is the maximum length in bytes of a data key accepted by
+// ValidateKey.
+var MaxKeyLength = 128
+
+// KeyError reports a data key rejected by ValidateKey.
+type KeyError struct {
+	Key    string
+	Reason string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("invalid data key %q: %s", e.Key, e.Reason)
+}
+
+// ValidateKey reports whether key is usable as a data key: it must be
+// non-empty, at most MaxKeyLength bytes long, and made only of ASCII
+// letters, digits, and the punctuation '_', '-', and '.', so that it
+// survives every log store and wire format downstream. The returned error,
+// if any, is a *KeyError.
+//
+// Data recorded under invalid keys is kept; ValidateKey is used by strict
+// mode (see SetStrictHook) and is available to callers that build keys
+// dynamically.
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return &KeyError{key, "empty"}
+	case len(key) > MaxKeyLength:
+		return &KeyError{key, fmt.Sprintf("longer than %d bytes", MaxKeyLength)}
+	}
+	for i, r := range key {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			return &KeyError{key, fmt.Sprintf("whitespace at offset %d", i)}
+		case r >= utf8.RuneSelf || !isKeyChar(byte(r)):
+			return &KeyError{key, fmt.Sprintf("character %q at offset %d not allowed", r, i)}
+		}
+	}
+	return nil
+}
+
+// isKeyChar reports whether c may appear in a data key.
+func isKeyChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '_' || c == '-' || c == '.'
+}
+
+// A KeyPolicy returns a non-nil error for data keys that do not follow a
+// naming convention. ValidateKey is itself a KeyPolicy.
+type KeyPolicy func(key string) error
+
+// keyPolicy holds the KeyPolicy set with SetKeyPolicy.
+var keyPolicy atomic.Value
+
+// SetKeyPolicy makes every data key recorded afterwards with WithData,
+// WrapWithData, SetGlobalData, and the helpers built on them be checked
+// with p, and returns the previous policy. Pairs whose key p rejects are
+// dropped, and reported to the strict mode hook if one is set. The keys of
+// this package, such as KeyKind and KeyCode, are not checked, so that the
+// functions reading them keep working whatever the policy. Setting a nil
+// policy, the default, accepts every string key.
+//
+// A shared policy keeps keys recorded by different teams consistent, which
+// matters when they are queried in a common log store:
+//
+//	errors.SetKeyPolicy(errors.SnakeCaseKeys)
+func SetKeyPolicy(p KeyPolicy) (previous KeyPolicy) {
+	previous, _ = keyPolicy.Load().(KeyPolicy)
+	keyPolicy.Store(p)
+	return previous
+}
+
+// checkKey returns the error of the KeyPolicy, if any, for key. The keys
+// of this package are always accepted.
+func checkKey(key string) error {
+	p, _ := keyPolicy.Load().(KeyPolicy)
+	if p == nil {
+		return nil
+	}
+	if _, ok := reservedKeys[key]; ok {
+		return nil
+	}
+	return p(key)
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// SnakeCaseKeys is a KeyPolicy accepting only snake_case keys, such as
+// "lock_id" or "http_status".
+func SnakeCaseKeys(key string) error {
+	if !snakeCase.MatchString(key) {
+		return &KeyError{key, "not snake_case"}
+	}
+	return nil
+}
+
+// KeyPattern returns a KeyPolicy accepting only the keys matched by re.
+func KeyPattern(re *regexp.Regexp) KeyPolicy {
+	return func(key string) error {
+		if !re.MatchString(key) {
+			return &KeyError{key, fmt.Sprintf("does not match %s", re)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	long := make([]byte, MaxKeyLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"lock_id", ""},
+		{"http.status-code", ""},
+		{"", `invalid data key "": empty`},
+		{"lock id", `invalid data key "lock id": whitespace at offset 4`},
+		{"lock\tid", `invalid data key "lock\tid": whitespace at offset 4`},
+		{"é", `invalid data key "é": character 'é' at offset 0 not allowed`},
+		{"a=b", `invalid data key "a=b": character '=' at offset 1 not allowed`},
+		{string(long), `invalid data key "` + string(long) + `": longer than 128 bytes`},
+	}
+	for _, tt := range tests {
+		err := ValidateKey(tt.key)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("ValidateKey(%q): got %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSetKeyPolicy(t *testing.T) {
+	prev := SetKeyPolicy(SnakeCaseKeys)
+	defer SetKeyPolicy(prev)
+
+	err := WithData(New("boom"), "lock_id", "L-1", "LockID", "L-2", "http.status", 500)
+	if got := GetAllData(err); len(got) != 1 || got["lock_id"] != "L-1" {
+		t.Errorf("GetAllData with SnakeCaseKeys: got %v", got)
+	}
+
+	SetKeyPolicy(KeyPattern(regexp.MustCompile(`^acme\.`)))
+	if err := checkKey("lock_id"); err == nil || err.Error() != `invalid data key "lock_id": does not match ^acme\.` {
+		t.Errorf("KeyPattern: got %v", err)
+	}
+	err = WithCode(WithKind(WithData(New("boom"), "acme.lock_id", 7, "lock_id", 8), KindNotFound), "lock_missing")
+	if KindOf(err) != KindNotFound || Code(err) != "lock_missing" || HTTPStatus(err) != 404 {
+		t.Errorf("KeyPattern: got kind %v, code %q, status %d, want the package's keys kept", KindOf(err), Code(err), HTTPStatus(err))
+	}
+	if got := GetAllData(err); got["acme.lock_id"] != 7 || got["lock_id"] != nil {
+		t.Errorf("GetAllData with KeyPattern: got %v", got)
+	}
+	SetKeyPolicy(nil)
+	if err := WithData(New("boom"), "LockID", 1); GetAllData(err)["LockID"] != 1 {
+		t.Errorf("nil policy: key dropped")
+	}
+}
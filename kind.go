@@ -0,0 +1,104 @@
+package errors
+
+import "net/http"
+
+// Kind classifies an error by the broad category of failure it represents,
+// independently of the message or the package that produced it.
+type Kind uint8
+
+// The Kinds understood by this package. KindUnknown is the zero value and is
+// reported for errors that were never classified.
+const (
+	KindUnknown Kind = iota
+	KindInvalid
+	KindNotFound
+	KindConflict
+	KindUnauthenticated
+	KindPermission
+	KindRateLimited
+	KindTimeout
+	KindCanceled
+	KindUnavailable
+	KindInternal
+)
+
+var kindNames = [...]string{
+	KindUnknown:         "unknown",
+	KindInvalid:         "invalid",
+	KindNotFound:        "not_found",
+	KindConflict:        "conflict",
+	KindUnauthenticated: "unauthenticated",
+	KindPermission:      "permission",
+	KindRateLimited:     "rate_limited",
+	KindTimeout:         "timeout",
+	KindCanceled:        "canceled",
+	KindUnavailable:     "unavailable",
+	KindInternal:        "internal",
+}
+
+// String returns the snake_case name of the Kind.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return kindNames[KindUnknown]
+}
+
+// ParseKind returns the Kind whose String is name, and whether there is one.
+func ParseKind(name string) (Kind, bool) {
+	for k, n := range kindNames {
+		if n == name {
+			return Kind(k), true
+		}
+	}
+	return KindUnknown, false
+}
+
+// KeyKind is the data key under which WithKind records the Kind of an error.
+const KeyKind = "kind"
+
+// WithKind annotates err with the Kind k.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, k Kind) error {
+	return WithData(err, KeyKind, k)
+}
+
+// KindOf returns the shallowest Kind recorded in err's chain, or KindUnknown
+// if none was recorded.
+func KindOf(err error) Kind {
+	if v, ok := GetValue(err, KeyKind); ok {
+		if k, ok := v.(Kind); ok {
+			return k
+		}
+	}
+	return KindUnknown
+}
+
+// KindFromHTTPStatus returns the Kind that best describes a failed request
+// answered with the given HTTP status code.
+func KindFromHTTPStatus(status int) Kind {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return KindInvalid
+	case http.StatusUnauthorized:
+		return KindUnauthenticated
+	case http.StatusForbidden:
+		return KindPermission
+	case http.StatusNotFound, http.StatusGone:
+		return KindNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return KindConflict
+	case http.StatusTooManyRequests:
+		return KindRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return KindTimeout
+	case StatusClientClosedRequest:
+		return KindCanceled
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return KindUnavailable
+	}
+	if status >= 500 {
+		return KindInternal
+	}
+	return KindUnknown
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestKindString(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		want string
+	}{
+		{KindUnknown, "unknown"},
+		{KindNotFound, "not_found"},
+		{KindRateLimited, "rate_limited"},
+		{KindInternal, "internal"},
+		{Kind(200), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("Kind(%d).String(): got %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestWithKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Kind
+	}{
+		{nil, KindUnknown},
+		{io.EOF, KindUnknown},
+		{WithKind(io.EOF, KindNotFound), KindNotFound},
+		{Wrap(WithKind(io.EOF, KindNotFound), "wrapped"), KindNotFound},
+		{WithKind(WithKind(io.EOF, KindNotFound), KindInternal), KindInternal},
+		{WithData(io.EOF, KeyKind, "not a kind"), KindUnknown},
+	}
+
+	for i, tt := range tests {
+		if got := KindOf(tt.err); got != tt.want {
+			t.Errorf("test %d: KindOf(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithKind(nil, KindInternal); got != nil {
+		t.Errorf("WithKind(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestKindFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   Kind
+	}{
+		{http.StatusOK, KindUnknown},
+		{http.StatusBadRequest, KindInvalid},
+		{http.StatusUnauthorized, KindUnauthenticated},
+		{http.StatusForbidden, KindPermission},
+		{http.StatusNotFound, KindNotFound},
+		{http.StatusConflict, KindConflict},
+		{http.StatusTooManyRequests, KindRateLimited},
+		{http.StatusGatewayTimeout, KindTimeout},
+		{StatusClientClosedRequest, KindCanceled},
+		{http.StatusServiceUnavailable, KindUnavailable},
+		{http.StatusInternalServerError, KindInternal},
+		{599, KindInternal},
+	}
+
+	for _, tt := range tests {
+		if got := KindFromHTTPStatus(tt.status); got != tt.want {
+			t.Errorf("KindFromHTTPStatus(%d): got %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for k := KindUnknown; k <= KindInternal; k++ {
+		if got, ok := ParseKind(k.String()); !ok || got != k {
+			t.Errorf("ParseKind(%q): got %v, %v, want %v", k.String(), got, ok, k)
+		}
+	}
+	if got, ok := ParseKind("gone"); ok || got != KindUnknown {
+		t.Errorf("ParseKind(gone): got %v, %v, want none", got, ok)
+	}
+}
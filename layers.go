@@ -0,0 +1,84 @@
+package errors
+
+import "strings"
+
+// Layer describes what a single error in a chain contributes to it.
+type Layer struct {
+	// Err is the error itself.
+	Err error
+	// Message is the message added by this error, or "" if it only annotates
+	// the error it wraps.
+	Message string
+	// Data holds the key/value pairs recorded by this error.
+	Data map[string]interface{}
+	// Stack is the stack trace recorded by this error, if any.
+	Stack StackTrace
+	// Branches holds the errors wrapped by a multi-error, such as one built
+	// with Join, which ends the chain.
+	Branches []error
+}
+
+// Layers returns one Layer for each error in err's chain, starting with err
+// itself and ending with the root cause. A multi-error ends the chain: its
+// Layer lists the errors it wraps as Branches, whose own chains are found
+// by calling Layers on each of them.
+//
+// For errors of other packages the message is derived from Error(): a
+// wrapper contributes the text that precedes the message of the error it
+// wraps, as with fmt.Errorf("context: %w", err).
+func Layers(err error) []Layer {
+	var layers []Layer
+	for err != nil {
+		layers = append(layers, layerOf(err))
+		err = Unwrap(err)
+	}
+	return layers
+}
+
+// layerOf returns the Layer describing what err contributes to its chain.
+func layerOf(err error) Layer {
+	type stackTracer interface {
+		StackTrace() StackTrace
+	}
+
+	l := Layer{Err: err}
+	switch e := err.(type) {
+	case *fundamental:
+		l.Message = e.msg
+		l.Stack = e.stack.StackTrace()
+	case *withStack:
+		l.Stack = e.stack.StackTrace()
+	case *withMessage:
+		l.Message = e.msg
+	case *withData:
+		l.Data = make(map[string]interface{}, len(e.data))
+		for k, v := range e.data {
+			l.Data[k] = v
+		}
+	case *withArgs:
+		l.Data = e.data()
+	case *translated:
+		l.Message = e.to.Error()
+	default:
+		if st, ok := err.(stackTracer); ok {
+			l.Stack = st.StackTrace()
+		}
+		if next := Unwrap(err); next == nil {
+			l.Message = err.Error()
+			if d, ok := err.(DataError); ok {
+				l.Data = d.DataCache()
+			}
+			l.Branches, _ = multiErrors(err)
+		} else {
+			msg := strings.TrimSuffix(err.Error(), next.Error())
+			l.Message = strings.TrimSuffix(strings.TrimSpace(msg), ":")
+		}
+	}
+	return l
+}
+
+// Location returns the function name, source file, and line of the frame.
+// For an unknown frame the function and file are "unknown" and the line is 0.
+func (f Frame) Location() (function, file string, line int) {
+	return f.name(), f.file(), f.line()
+}
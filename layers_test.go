@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestLayers(t *testing.T) {
+	if got := Layers(nil); got != nil {
+		t.Errorf("Layers(nil): got %v, want nil", got)
+	}
+
+	err := WithData(Wrap(fmt.Errorf("dialing: %w", io.EOF), "reading"), "key", "val")
+	layers := Layers(err)
+
+	type summary struct {
+		Message  string
+		Data     map[string]interface{}
+		HasStack bool
+	}
+	var got []summary
+	for _, l := range layers {
+		got = append(got, summary{l.Message, l.Data, len(l.Stack) > 0})
+	}
+	want := []summary{
+		{"", map[string]interface{}{"key": "val"}, false},
+		{"", nil, true},
+		{"reading", nil, false},
+		{"dialing", nil, false},
+		{"EOF", nil, false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Layers: got %+v, want %+v", got, want)
+	}
+	if layers[0].Err != err || layers[len(layers)-1].Err != io.EOF {
+		t.Errorf("Layers: got errors %v ... %v", layers[0].Err, layers[len(layers)-1].Err)
+	}
+}
+
+func TestFrameLocation(t *testing.T) {
+	fn, file, line := Frame(initpc).Location()
+	if fn != "github.com/noke-inc/lib_errors.init" || line != 9 || file == "unknown" {
+		t.Errorf("Location: got (%q, %q, %d)", fn, file, line)
+	}
+	fn, file, line = Frame(0).Location()
+	if fn != "unknown" || file != "unknown" || line != 0 {
+		t.Errorf("Location of unknown frame: got (%q, %q, %d)", fn, file, line)
+	}
+}
@@ -0,0 +1,99 @@
+package errors
+
+import "sync/atomic"
+
+// FormatConfig is the layout of the text written when errors of this
+// package are formatted with %+v.
+type FormatConfig struct {
+	// Separator is written between messages, data sections, and stack
+	// frames. The default is "\n".
+	Separator string
+	// FrameIndent is written between a frame's function name and its
+	// "file:line", after Separator. The default is "\t".
+	FrameIndent string
+	// DataLabel precedes the data recorded with WithData. The default is
+	// "ERROR DATA: ".
+	DataLabel string
+	// ArgsLabel precedes the arguments recorded with WithArgs. The default
+	// is "ERROR ARGS: ".
+	ArgsLabel string
+	// GlobalDataLabel precedes the data set with SetGlobalData. The
+	// default is "GLOBAL DATA: ".
+	GlobalDataLabel string
+	// NestedPrefix precedes every line of the %+v output of an error
+	// recorded as a data value, which follows the data section. The
+	// default is "> ".
+	NestedPrefix string
+	// SecondaryLabel precedes each error attached with WithSecondary,
+	// whose %+v output follows, its remaining lines prefixed with the
+	// NestedPrefix. The default is "SECONDARY ERROR: ".
+	SecondaryLabel string
+	// SuppressedLabel precedes each error recorded with Suppress, laid out
+	// like those attached with WithSecondary. The default is
+	// "SUPPRESSED ERROR: ".
+	SuppressedLabel string
+	// AttemptsLabel precedes the number of attempts made by Retry, which
+	// is followed by a table of the attempts. The default is
+	// "FAILED ATTEMPTS: ".
+	AttemptsLabel string
+}
+
+// DefaultFormatConfig is the layout used unless SetFormatConfig is called.
+var DefaultFormatConfig = FormatConfig{
+	Separator:       "\n",
+	FrameIndent:     "\t",
+	DataLabel:       "ERROR DATA: ",
+	ArgsLabel:       "ERROR ARGS: ",
+	GlobalDataLabel: "GLOBAL DATA: ",
+	NestedPrefix:    "> ",
+	SecondaryLabel:  "SECONDARY ERROR: ",
+	SuppressedLabel: "SUPPRESSED ERROR: ",
+	AttemptsLabel:   "FAILED ATTEMPTS: ",
+}
+
+// formatConfigValue holds the *FormatConfig set with SetFormatConfig.
+var formatConfigValue atomic.Value
+
+// SetFormatConfig sets the layout used by %+v, and by Parse to read it back,
+// and returns the previous layout. Empty fields of c take their value from
+// DefaultFormatConfig.
+func SetFormatConfig(c FormatConfig) (previous FormatConfig) {
+	previous = formatConfig()
+	if c.Separator == "" {
+		c.Separator = DefaultFormatConfig.Separator
+	}
+	if c.FrameIndent == "" {
+		c.FrameIndent = DefaultFormatConfig.FrameIndent
+	}
+	if c.DataLabel == "" {
+		c.DataLabel = DefaultFormatConfig.DataLabel
+	}
+	if c.ArgsLabel == "" {
+		c.ArgsLabel = DefaultFormatConfig.ArgsLabel
+	}
+	if c.GlobalDataLabel == "" {
+		c.GlobalDataLabel = DefaultFormatConfig.GlobalDataLabel
+	}
+	if c.NestedPrefix == "" {
+		c.NestedPrefix = DefaultFormatConfig.NestedPrefix
+	}
+	if c.SecondaryLabel == "" {
+		c.SecondaryLabel = DefaultFormatConfig.SecondaryLabel
+	}
+	if c.SuppressedLabel == "" {
+		c.SuppressedLabel = DefaultFormatConfig.SuppressedLabel
+	}
+	if c.AttemptsLabel == "" {
+		c.AttemptsLabel = DefaultFormatConfig.AttemptsLabel
+	}
+	formatConfigValue.Store(&c)
+	return previous
+}
+
+// formatConfig returns the layout set with SetFormatConfig.
+func formatConfig() FormatConfig {
+	if c, _ := formatConfigValue.Load().(*FormatConfig); c != nil {
+		return *c
+	}
+	return DefaultFormatConfig
+}
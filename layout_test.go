@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetFormatConfig(t *testing.T) {
+	defer SetStackProvider(SetStackProvider(FixedStack(FakeFrame("main.main", "/src/main.go", 7))))
+	prev := SetFormatConfig(FormatConfig{Separator: " | ", FrameIndent: "at ", DataLabel: "data="})
+	defer SetFormatConfig(prev)
+	if prev != DefaultFormatConfig {
+		t.Errorf("SetFormatConfig: got previous %+v, want the default", prev)
+	}
+
+	err := WithMessage(WithData(New("boom"), "lock_id", "L-1"), "unlocking")
+	want := "boom | main.main | at /src/main.go:7 | data=map[lock_id:L-1] | unlocking"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	snap, perr := Parse(want)
+	if perr != nil {
+		t.Fatalf("Parse: %v", perr)
+	}
+	if len(snap.Layers) != 3 || snap.Layers[1].Data["lock_id"] != "L-1" || snap.Layers[2].Stack[0].Line != 7 || snap.Layers[0].Message != "unlocking" {
+		t.Errorf("Parse: got %+v", snap)
+	}
+}
@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// The JSON fields, in order of preference, that DecodeLenient reads each
+// part of a foreign error from.
+var (
+	lenientMessageFields = []string{"message", "msg", "error", "detail", "title", "description"}
+	lenientDataFields    = []string{"data", "details", "metadata", "context", "extra"}
+	lenientStackFields   = []string{"stack", "stacktrace", "stack_trace", "frames", "backtrace"}
+	lenientCauseFields   = []string{"cause", "inner", "wrapped", "error"}
+)
+
+// DecodeLenient decodes a JSON error document that only approximates the
+// encoding of a Snapshot, such as those sent by services written in other
+// languages, into a Snapshot whose Err is usable. It accepts:
+//
+//   - the encoding of a Snapshot, whatever its fields of the wrong type;
+//   - a single object with a message under "message", "msg", "error",
+//     "detail", "title", or "description", data under "data", "details",
+//     "metadata", "context", or "extra", and a stack under "stack",
+//     "stacktrace", "stack_trace", "frames", or "backtrace", wrapping the
+//     object or message under "cause", "inner", "wrapped", or "error";
+//   - a bare string, taken as the message.
+//
+// "code", "kind", "severity", and "status" fields are recorded under
+// KeyCode, KeyKind, KeySeverity, and KeyStatusCode. A stack may be a list of
+// frame objects, a list of strings such as "main.main /src/main.go:7" or
+// "at main.main (/src/main.go:7)", or a single string of such lines. Other
+// fields, and frames that cannot be read, are ignored.
+//
+// DecodeLenient returns an error if b is not JSON, or holds no message.
+func DecodeLenient(b []byte) (Snapshot, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return Snapshot{}, Wrap(err, "decoding error document")
+	}
+	var s Snapshot
+	if m, ok := v.(map[string]interface{}); ok {
+		if layers, ok := m["layers"].([]interface{}); ok {
+			for _, l := range layers {
+				if lm, ok := l.(map[string]interface{}); ok {
+					s.Layers = append(s.Layers, lenientLayer(lm))
+				}
+			}
+			s.GlobalData, _ = m["global_data"].(map[string]interface{})
+			if n, ok := m["version"].(float64); ok {
+				s.Version = int(n)
+			}
+		} else {
+			s.Layers = lenientChain(m)
+		}
+	} else if msg, ok := v.(string); ok {
+		s.Layers = []SnapshotLayer{{Message: msg}}
+	}
+	for _, l := range s.Layers {
+		if l.Message != "" {
+			return s, nil
+		}
+	}
+	return Snapshot{}, New("decoding error document: no error message found")
+}
+
+// lenientChain returns the layers of the error described by m and of the
+// causes it wraps, outermost first.
+func lenientChain(m map[string]interface{}) []SnapshotLayer {
+	var layers []SnapshotLayer
+	for m != nil {
+		l := lenientLayer(m)
+		var next map[string]interface{}
+		for _, f := range lenientCauseFields {
+			switch c := m[f].(type) {
+			case map[string]interface{}:
+				next = c
+			case string:
+				if f != "error" || l.Message != c {
+					next = map[string]interface{}{"message": c}
+				}
+			default:
+				continue
+			}
+			break
+		}
+		layers = append(layers, l)
+		m = next
+	}
+	return layers
+}
+
+// lenientLayer returns the layer described by the fields of m.
+func lenientLayer(m map[string]interface{}) SnapshotLayer {
+	var l SnapshotLayer
+	for _, f := range lenientMessageFields {
+		if msg, ok := m[f].(string); ok {
+			l.Message = msg
+			break
+		}
+	}
+	data := make(map[string]interface{})
+	for _, f := range lenientDataFields {
+		if d, ok := m[f].(map[string]interface{}); ok {
+			for k, v := range d {
+				data[k] = v
+			}
+			break
+		}
+	}
+	if code, ok := m["code"].(string); ok {
+		data[KeyCode] = code
+	}
+	if name, ok := m["kind"].(string); ok {
+		if k, ok := ParseKind(name); ok {
+			data[KeyKind] = k
+		}
+	}
+	if name, ok := m["severity"].(string); ok {
+		if sev, ok := ParseSeverity(name); ok {
+			data[KeySeverity] = sev
+		}
+	}
+	if status, ok := m["status"].(float64); ok {
+		data[KeyStatusCode] = int(status)
+	}
+	if len(data) > 0 {
+		l.Data = data
+	}
+	for _, f := range lenientStackFields {
+		if v, ok := m[f]; ok {
+			l.Stack = lenientStack(v)
+			break
+		}
+	}
+	return l
+}
+
+// lenientStack returns the frames of v that can be read.
+func lenientStack(v interface{}) []SnapshotFrame {
+	var items []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		items = v
+	case string:
+		for _, line := range strings.Split(v, "\n") {
+			items = append(items, line)
+		}
+	}
+	var frames []SnapshotFrame
+	for _, item := range items {
+		var f SnapshotFrame
+		switch item := item.(type) {
+		case map[string]interface{}:
+			f.Function = firstString(item, "function", "func", "method", "name")
+			f.File = firstString(item, "file", "filename", "path")
+			for _, k := range []string{"line", "lineno", "line_number"} {
+				switch n := item[k].(type) {
+				case float64:
+					f.Line = int(n)
+				case string:
+					f.Line, _ = strconv.Atoi(n)
+				}
+			}
+		case string:
+			if f = lenientFrame(item); f.File == "" {
+				continue
+			}
+		}
+		if f.Function != "" || f.File != "" {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// lenientFrame parses a frame written as "function file:line" or as
+// "at function (file:line)". A string without a location yields no file.
+func lenientFrame(s string) SnapshotFrame {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "at ")
+	var f SnapshotFrame
+	var location string
+	if i := strings.LastIndexByte(s, '('); i >= 0 && strings.HasSuffix(s, ")") {
+		f.Function, location = strings.TrimSpace(s[:i]), s[i+1:len(s)-1]
+	} else if i := strings.LastIndexByte(s, ' '); i >= 0 {
+		f.Function, location = strings.TrimSpace(s[:i]), s[i+1:]
+	}
+	f.File = location
+	if i := strings.LastIndexByte(location, ':'); i >= 0 {
+		if n, err := strconv.Atoi(location[i+1:]); err == nil {
+			f.File, f.Line = location[:i], n
+		}
+	}
+	return f
+}
+
+// firstString returns the first string value of m among keys, or "".
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeLenient(t *testing.T) {
+	doc := `{
+		"message": "charge failed",
+		"code": "card_declined",
+		"kind": "conflict",
+		"severity": "warning",
+		"status": 409,
+		"details": {"order": "o-1"},
+		"stacktrace": ["at Billing.charge (billing.js:12)", "main.main /src/main.go:7", 42],
+		"cause": {"msg": "gateway said no", "backtrace": "app.rb:3:in pay\nnot a frame", "inner": "timeout"},
+		"retryable": true
+	}`
+	s, err := DecodeLenient([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := s.Err()
+	if want := "charge failed: gateway said no: timeout"; got.Error() != want {
+		t.Errorf("Error: got %q, want %q", got.Error(), want)
+	}
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"Code", Code(got), "card_declined"},
+		{"KindOf", KindOf(got), KindConflict},
+		{"SeverityOf", SeverityOf(got), SeverityWarning},
+		{"HTTPStatus", HTTPStatus(got), 409},
+		{"order", GetAllData(got)["order"], "o-1"},
+		{"stack", s.Layers[0].Stack, []SnapshotFrame{
+			{Function: "Billing.charge", File: "billing.js", Line: 12},
+			{Function: "main.main", File: "/src/main.go", Line: 7},
+		}},
+		{"cause stack", s.Layers[1].Stack, []SnapshotFrame{
+			{Function: "app.rb:3:in", File: "pay"},
+			{Function: "not a", File: "frame"},
+		}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	own := NewSnapshot(WrapWithData(New("EOF"), "reading", "lock_id", "7"))
+	s, err = DecodeLenient([]byte(mustMarshal(t, own)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, w := fmt.Sprintf("%+v", s.Err()), fmt.Sprintf("%+v", own.Err()); g != w {
+		t.Errorf("Snapshot encoding:\n got %q\nwant %q", g, w)
+	}
+
+	tests := []struct {
+		doc     string
+		want    string
+		wantErr bool
+	}{
+		{`"disk full"`, "disk full", false},
+		{`{"error": "disk full"}`, "disk full", false},
+		{`{"error": {"message": "disk full"}}`, "disk full", false},
+		{`{"layers": [{"message": "EOF", "stack": "none"}, 7]}`, "EOF", false},
+		{`{"status": 500}`, "", true},
+		{`[1, 2]`, "", true},
+		{`{`, "", true},
+	}
+	for _, tt := range tests {
+		s, err := DecodeLenient([]byte(tt.doc))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodeLenient(%s): got error %v, want error %v", tt.doc, err, tt.wantErr)
+			continue
+		}
+		if err == nil && s.Err().Error() != tt.want {
+			t.Errorf("DecodeLenient(%s): got %q, want %q", tt.doc, s.Err().Error(), tt.want)
+		}
+	}
+}
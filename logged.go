@@ -0,0 +1,39 @@
+package errors
+
+// logged marks the error it wraps as already logged.
+type logged struct{ Base }
+
+func (logged) Logged() bool { return true }
+
+// MarkLogged annotates err as having been logged, so that the layers it is
+// returned through, such as the middleware of a request, do not log it
+// again:
+//
+//	if err != nil {
+//	        log.Printf("acquiring lock: %+v", err)
+//	        return errors.MarkLogged(err)
+//	}
+//
+// The mark survives further wrapping. errhttp Renderers do not log marked
+// errors.
+// If err is nil, MarkLogged returns nil. If err is already marked, it is
+// returned unchanged.
+func MarkLogged(err error) error {
+	if err == nil || IsLogged(err) {
+		return err
+	}
+	return logged{Base{err}}
+}
+
+// IsLogged reports whether err was marked with MarkLogged. Errors of other
+// packages can take part by implementing
+//
+//	type logger interface {
+//	        Logged() bool
+//	}
+//
+// in which case the shallowest such error in the chain decides.
+func IsLogged(err error) bool {
+	var l interface{ Logged() bool }
+	return As(err, &l) && l.Logged()
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+type unloggedError struct{ error }
+
+func (unloggedError) Logged() bool { return false }
+
+func TestIsLogged(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{MarkLogged(io.EOF), true},
+		{Wrap(WithData(MarkLogged(io.EOF), "k", 1), "wrapped"), true},
+		{unloggedError{MarkLogged(io.EOF)}, false},
+	}
+	for i, tt := range tests {
+		if got := IsLogged(tt.err); got != tt.want {
+			t.Errorf("test %d: IsLogged(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if MarkLogged(nil) != nil {
+		t.Error("MarkLogged(nil): want nil")
+	}
+	err := Wrap(MarkLogged(io.EOF), "reading")
+	if MarkLogged(err) != err {
+		t.Error("MarkLogged of a marked error: want it unchanged")
+	}
+	if !Is(err, io.EOF) || err.Error() != "reading: EOF" {
+		t.Errorf("MarkLogged: got %v, want to wrap io.EOF", err)
+	}
+}
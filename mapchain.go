@@ -0,0 +1,218 @@
+package errors
+
+import "reflect"
+
+// MapChain rebuilds err's chain with fn applied to the Layer of each error
+// in it, for boundary layers that must sanitize errors before returning
+// them, for example by stripping data or rewriting messages:
+//
+//	clean := errors.MapChain(err, func(l errors.Layer) errors.Layer {
+//	        delete(l.Data, "sql")
+//	        return l
+//	})
+//
+// fn is called from the root cause outwards, and Layer.Err is the original
+// error. Each rebuilt error records the Message, Data, and Stack of the Layer
+// returned by fn, so stack traces are preserved unless fn clears them. The
+// errors whose Layers fn returns unchanged, and which wrap an unchanged
+// chain, are kept as they are, so that sentinels still match with Is.
+// Unchanged wrappers of this package are copied over the rebuilt chain;
+// other errors are replaced by errors of this package.
+// If err is nil, MapChain returns nil.
+func MapChain(err error, fn func(Layer) Layer) error {
+	layers := Layers(err)
+	var cur error
+	same := true
+	for i := len(layers) - 1; i >= 0; i-- {
+		orig := layers[i]
+		l := fn(copyLayer(orig))
+		if sameLayer(orig, l) {
+			if same {
+				cur = orig.Err
+				continue
+			}
+			if err, ok := rewrap(orig.Err, cur); ok {
+				cur = err
+				continue
+			}
+		}
+		same = false
+		cur = buildLayer(cur, l)
+	}
+	return cur
+}
+
+// copyLayer returns l with a copy of its Data, so that fn may modify it.
+func copyLayer(l Layer) Layer {
+	if l.Data != nil {
+		data := make(map[string]interface{}, len(l.Data))
+		for k, v := range l.Data {
+			data[k] = v
+		}
+		l.Data = data
+	}
+	return l
+}
+
+// sameLayer reports whether a and b record the same message, data, and
+// stack.
+func sameLayer(a, b Layer) bool {
+	if a.Message != b.Message || len(a.Data) != len(b.Data) || len(a.Stack) != len(b.Stack) {
+		return false
+	}
+	for i := range a.Stack {
+		if a.Stack[i] != b.Stack[i] {
+			return false
+		}
+	}
+	return len(a.Data) == 0 || reflect.DeepEqual(a.Data, b.Data)
+}
+
+// rewrap returns a copy of the wrapper err of this package wrapping inner
+// instead, and whether err is such a wrapper. Generic wrappers, which a
+// type switch cannot name, implement rewrapper.
+func rewrap(err, inner error) (error, bool) {
+	switch e := err.(type) {
+	case *withStack:
+		return &withStack{inner, e.stack}, true
+	case *withMessage:
+		return &withMessage{error: inner, msg: e.msg}, true
+	case *withData:
+		return &withData{inner, e.data}, true
+	case *withArgs:
+		return &withArgs{inner, e.args}, true
+	case *translated:
+		return &translated{e.to, inner}, true
+	case *withSecondary:
+		return &withSecondary{inner, e.related}, true
+	case *withSuppressed:
+		return &withSuppressed{inner, e.suppressed}, true
+	case permanent:
+		return permanent{Base{inner}}, true
+	case sideEffects:
+		return sideEffects{Base{inner}}, true
+	case logged:
+		return logged{Base{inner}}, true
+	case *PanicError:
+		return &PanicError{inner, e.stack}, true
+	case checkPanic:
+		return checkPanic{inner}, true
+	case rewrapper:
+		return e.rewrap(inner), true
+	}
+	return nil, false
+}
+
+// A rewrapper is a generic wrapper of this package; see rewrap.
+type rewrapper interface {
+	rewrap(inner error) error
+}
+
+// buildLayer returns an error of this package recording l over inner, or
+// the root of a chain if inner is nil: a multi-error wrapping the Branches
+// of l, if it has any.
+func buildLayer(inner error, l Layer) error {
+	var s *stack
+	if len(l.Stack) > 0 {
+		st := make(stack, len(l.Stack))
+		for i, f := range l.Stack {
+			st[i] = uintptr(f)
+		}
+		s = &st
+	}
+	var err error
+	if inner == nil && len(l.Branches) > 0 {
+		err = &multiError{append([]error(nil), l.Branches...), l.Message}
+	} else if inner == nil {
+		if s == nil {
+			s = &stack{}
+		}
+		err = &fundamental{msg: l.Message, stack: s}
+		s = nil
+	} else if l.Message != "" {
+		err = &withMessage{error: inner, msg: l.Message}
+	} else {
+		err = inner
+	}
+	if len(l.Data) > 0 {
+		err = &withData{err, l.Data}
+	}
+	if s != nil {
+		err = &withStack{err, s}
+	}
+	return err
+}
+
+// mapTree is MapChain applied to every error in err's tree rather than to
+// its chain only: fn is also applied to the errors wrapped by multi-errors
+// and sealed errors, to those attached with WithSecondary and
+// WithSuppressed, and to errors recorded as data values. It returns the
+// rebuilt error, and whether anything changed.
+func mapTree(err error, fn func(Layer) Layer) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	switch e := err.(type) {
+	case *sealed:
+		if inner, changed := mapTree(e.err, fn); changed {
+			return &sealed{inner, e.key}, true
+		}
+		return err, false
+	case *withSecondary:
+		inner, innerChanged := mapTree(e.error, fn)
+		related, changed := mapTrees(e.related, fn)
+		if !innerChanged && !changed {
+			return err, false
+		}
+		return &withSecondary{inner, related}, true
+	case *withSuppressed:
+		inner, innerChanged := mapTree(e.error, fn)
+		suppressed, changed := mapTrees(e.suppressed, fn)
+		if !innerChanged && !changed {
+			return err, false
+		}
+		return &withSuppressed{inner, suppressed}, true
+	}
+	if errs, ok := multiErrors(err); ok {
+		if mapped, changed := mapTrees(errs, fn); changed {
+			return Join(mapped...), true
+		}
+		return err, false
+	}
+
+	orig := layerOf(err)
+	l := fn(copyLayer(orig))
+	same := sameLayer(orig, l)
+	for k, v := range l.Data {
+		if e, ok := v.(error); ok {
+			if mapped, changed := mapTree(e, fn); changed {
+				l.Data[k] = mapped
+				same = false
+			}
+		}
+	}
+	next := Unwrap(err)
+	inner, innerChanged := mapTree(next, fn)
+	if same {
+		if !innerChanged {
+			return err, false
+		}
+		if err, ok := rewrap(err, inner); ok {
+			return err, true
+		}
+	}
+	return buildLayer(inner, l), true
+}
+
+// mapTrees applies mapTree to each of errs, and reports whether any
+// changed.
+func mapTrees(errs []error, fn func(Layer) Layer) ([]error, bool) {
+	mapped := make([]error, len(errs))
+	var changed bool
+	for i, e := range errs {
+		var c bool
+		mapped[i], c = mapTree(e, fn)
+		changed = changed || c
+	}
+	return mapped, changed
+}
@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMapChain(t *testing.T) {
+	err := Wrap(WithData(Wrap(io.EOF, "scanning row"), "sql", "SELECT *", "table", "locks"), "loading lock")
+
+	same := MapChain(err, func(l Layer) Layer { return l })
+	if same != err {
+		t.Errorf("MapChain with the identity: got a rebuilt chain")
+	}
+
+	clean := MapChain(err, func(l Layer) Layer {
+		delete(l.Data, "sql")
+		l.Message = strings.ReplaceAll(l.Message, "row", "record")
+		return l
+	})
+	if got, want := clean.Error(), "loading lock: scanning record: EOF"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if !Is(clean, io.EOF) {
+		t.Error("Is: the unchanged root cause was not kept")
+	}
+	if _, ok := GetValue(clean, "sql"); ok {
+		t.Error("GetValue: stripped data still present")
+	}
+	if v, _ := GetValue(clean, "table"); v != "locks" {
+		t.Errorf("GetValue: got %v, want the data that was kept", v)
+	}
+	if v, _ := GetValue(err, "sql"); v != "SELECT *" {
+		t.Error("MapChain modified the original chain")
+	}
+	origStacks, cleanStacks := FormatStack(err), FormatStack(clean)
+	if origStacks == "" || origStacks != cleanStacks {
+		t.Errorf("FormatStack: got %q, want the original stacks %q", cleanStacks, origStacks)
+	}
+
+	root := MapChain(New("secret"), func(l Layer) Layer {
+		l.Message = "redacted"
+		return l
+	})
+	if got := fmt.Sprintf("%+v", root); !strings.HasPrefix(got, "redacted\ngithub.com/noke-inc/lib_errors.TestMapChain") {
+		t.Errorf("%%+v of a rebuilt root: got %q", got)
+	}
+	errNotFound := New("lock not found")
+	translatedErr := Translator{{From: io.EOF, To: errNotFound}}.Translate(WithData(io.EOF, "sql", "SELECT *"))
+	clean = MapChain(translatedErr, func(l Layer) Layer {
+		delete(l.Data, "sql")
+		return l
+	})
+	if !Is(clean, errNotFound) || clean.Error() != "lock not found" {
+		t.Errorf("MapChain: got %v, want the unchanged translation kept over the rebuilt cause", clean)
+	}
+
+	if MapChain(nil, func(l Layer) Layer { return l }) != nil {
+		t.Error("MapChain(nil): want nil")
+	}
+}
+
+func TestRewrapMarkers(t *testing.T) {
+	ClassifyKeys(ClassSecret, "rewrap_pin")
+	type payload struct{ Bolt string }
+	markers := []struct {
+		name   string
+		mark   func(error) error
+		marked func(error) bool
+	}{
+		{"MarkPermanent", MarkPermanent, IsPermanent},
+		{"MarkLogged", MarkLogged, IsLogged},
+		{"MarkSideEffects", MarkSideEffects, HasSideEffects},
+		{"WithPayload", func(err error) error { return WithPayload(err, payload{"jammed"}) }, func(err error) bool {
+			p, ok := PayloadAs[payload](err)
+			return ok && p.Bolt == "jammed"
+		}},
+	}
+	for _, m := range markers {
+		inner := WrapWithData(io.EOF, "reading", "rewrap_pin", "1234")
+		for i := 0; i < 5; i++ {
+			inner = WithMessage(inner, fmt.Sprintf("layer %d", i))
+		}
+		err := Wrap(m.mark(inner), "unlocking")
+
+		for _, tt := range []struct {
+			name string
+			got  error
+		}{
+			{"LimitData", LimitData(err, ClassInternal)},
+			{"Trim", Trim(err, 2)},
+			{"Flatten", Flatten(err)},
+			{"MapChain", MapChain(err, func(l Layer) Layer {
+				delete(l.Data, "rewrap_pin")
+				return l
+			})},
+		} {
+			if tt.got == err {
+				t.Errorf("%s: %s: got the chain unchanged, want it rebuilt", m.name, tt.name)
+			}
+			if !m.marked(tt.got) {
+				t.Errorf("%s: %s: the mark was lost:\n%+v", m.name, tt.name, tt.got)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Join returns an error wrapping each of the non-nil errs, or nil if there
+// are none. Its message joins theirs with "; ", and formatting it with %+v
+// prints each of them with %+v.
+//
+// The returned error implements Unwrap() []error, so Is, As, Walk, and
+// GetValue search every wrapped error, and Errors() []error, so that
+// go.uber.org/multierr sees the wrapped errors themselves:
+//
+//	errs := multierr.Errors(errors.Join(a, b)) // []error{a, b}
+func Join(errs ...error) error {
+	var m multiError
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return &m
+}
+
+// FlattenMulti returns err with nested multi-errors, including those of
+// github.com/hashicorp/go-multierror and go.uber.org/multierr, replaced by
+// a single error built with Join that wraps their leaves in order. The
+// wrapped errors themselves are kept, with their data and stack traces.
+// If err does not wrap several errors, FlattenMulti returns it unchanged.
+func FlattenMulti(err error) error {
+	if _, ok := multiErrors(err); !ok {
+		return err
+	}
+	var m multiError
+	var flatten func(error)
+	flatten = func(err error) {
+		errs, ok := multiErrors(err)
+		if !ok {
+			m.errs = append(m.errs, err)
+			return
+		}
+		for _, e := range errs {
+			if e != nil {
+				flatten(e)
+			}
+		}
+	}
+	flatten(err)
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return &m
+}
+
+type multiError struct {
+	errs []error
+	msg  string // overrides the message of errs, if set
+}
+
+func (m *multiError) Error() string {
+	if m.msg != "" {
+		return m.msg
+	}
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the wrapped errors.
+func (m *multiError) Unwrap() []error { return m.errs }
+
+// Errors returns a copy of the wrapped errors.
+func (m *multiError) Errors() []error {
+	return append([]error(nil), m.errs...)
+}
+
+// Is reports whether any wrapped error matches target, for Go releases
+// whose errors.Is does not follow Unwrap() []error.
+func (m *multiError) Is(target error) bool {
+	for _, err := range m.errs {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first wrapped error that matches target, for Go releases
+// whose errors.As does not follow Unwrap() []error.
+func (m *multiError) As(target interface{}) bool {
+	for _, err := range m.errs {
+		if As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, m.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, m) {
+				return
+			}
+			fmt.Fprintf(s, "%d errors occurred:", len(m.errs))
+			for i, err := range m.errs {
+				fmt.Fprintf(s, "%s[%d] %+v", formatConfig().Separator, i+1, err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, m.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", m.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like m.
+func (m *multiError) GoString() string {
+	var b strings.Builder
+	b.WriteString("errors.Join(")
+	for i, err := range m.errs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#v", err)
+	}
+	b.WriteString(")")
+	return b.String()
+}
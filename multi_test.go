@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("Join(nil, nil): got %v, want nil", err)
+	}
+
+	a := WithKind(New("a"), KindNotFound)
+	err := Join(a, nil, io.EOF)
+	if got, want := err.Error(), "a; EOF"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) || !err.(*multiError).Is(io.EOF) {
+		t.Error("Is: want EOF to be found")
+	}
+	var f *fundamental
+	if !err.(*multiError).As(&f) || f.msg != "a" {
+		t.Errorf("As: got %v, want a", f)
+	}
+	if KindOf(err) != KindNotFound {
+		t.Errorf("KindOf: got %v, want not_found", KindOf(err))
+	}
+	errs := err.(interface{ Errors() []error }).Errors()
+	if !reflect.DeepEqual(errs, []error{a, io.EOF}) {
+		t.Errorf("Errors: got %v", errs)
+	}
+	errs[0] = nil
+	if err.(*multiError).errs[0] == nil {
+		t.Error("Errors: returned slice aliases the wrapped errors")
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(verbose, "2 errors occurred:\n[1] a\n") || !strings.HasSuffix(verbose, "\n[2] EOF") {
+		t.Errorf("%%+v: got %q", verbose)
+	}
+}
+
+func TestFlattenMulti(t *testing.T) {
+	a := New("a")
+	b := WithData(io.EOF, "b", 2)
+	nested := &hashiError{[]error{a, &hashiError{[]error{b, nil}}, Join(io.ErrClosedPipe)}}
+
+	err := FlattenMulti(nested)
+	m, ok := err.(*multiError)
+	if !ok {
+		t.Fatalf("FlattenMulti: got %T, want *multiError", err)
+	}
+	if want := []error{a, b, io.ErrClosedPipe}; !reflect.DeepEqual(m.errs, want) {
+		t.Errorf("FlattenMulti: got %v, want %v", m.errs, want)
+	}
+	if v, _ := GetValue(err, "b"); v != 2 {
+		t.Errorf("GetValue: got %v, want 2", v)
+	}
+
+	if got := FlattenMulti(a); got != a {
+		t.Errorf("FlattenMulti: got %v, want a unchanged", got)
+	}
+	if got := FlattenMulti(&hashiError{}); got != nil {
+		t.Errorf("FlattenMulti(empty): got %v, want nil", got)
+	}
+}
+
+// uberError mimics the multi-error of go.uber.org/multierr, which exposes
+// its errors through Errors() and, in recent releases, Unwrap() []error.
+type uberError struct{ errs []error }
+
+func (e *uberError) Error() string   { return "uber" }
+func (e *uberError) Errors() []error { return e.errs }
+
+func TestUberMultiErr(t *testing.T) {
+	a := WithData(New("a"), "lock_id", 7)
+	err := Wrap(&uberError{[]error{a, &hashiError{[]error{io.EOF}}}}, "outer")
+
+	if got := Leaves(err); !reflect.DeepEqual(got, []error{Cause(a), io.EOF}) {
+		t.Errorf("Leaves: got %v", got)
+	}
+	if v, _ := GetValue(err, "lock_id"); v != 7 {
+		t.Errorf("GetValue: got %v, want 7", v)
+	}
+
+	m := FlattenMulti(&uberError{[]error{a, &hashiError{[]error{io.EOF}}}})
+	if got := m.(interface{ Errors() []error }).Errors(); !reflect.DeepEqual(got, []error{a, io.EOF}) {
+		t.Errorf("FlattenMulti: got %v", got)
+	}
+	if got := fmt.Sprintf("%+v", m); !strings.Contains(got, "ERROR DATA: map[lock_id:7]") {
+		t.Errorf("FlattenMulti: data lost in %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package errors
+
+// Must returns v if err is nil, and otherwise panics with err annotated
+// with a stack trace at the point Must is called. It is meant for
+// initialization code where a failure is a bug and returning errors is
+// impractical:
+//
+//	var tmpl = errors.Must(template.ParseFS(files, "*.tmpl"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(runHooks(&withStack{
+			err,
+			callers(),
+		}, HookWrap))
+	}
+	return v
+}
+
+// Must0 panics if err is not nil, with err annotated with a stack trace at
+// the point Must0 is called and, if given, the supplied message, as by
+// Wrap.
+func Must0(err error, message ...string) {
+	if err == nil {
+		return
+	}
+	if len(message) > 0 {
+		err = &withMessage{
+			error: err,
+			msg:   message[0],
+		}
+	}
+	panic(runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap))
+}
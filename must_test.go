@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// recovered returns the value f panics with, or nil.
+func recovered(f func()) (v interface{}) {
+	defer func() { v = recover() }()
+	f()
+	return nil
+}
+
+func TestMust(t *testing.T) {
+	if n := Must(strconv.Atoi("42")); n != 42 {
+		t.Errorf("Must: got %d, want 42", n)
+	}
+	if v := recovered(func() { Must0(nil, "unused") }); v != nil {
+		t.Errorf("Must0(nil): panicked with %v", v)
+	}
+
+	tests := []struct {
+		f    func()
+		want string
+	}{
+		{func() { Must(strconv.Atoi("x")) }, `strconv.Atoi: parsing "x": invalid syntax`},
+		{func() { Must0(io.EOF) }, "EOF"},
+		{func() { Must0(io.EOF, "reading config") }, "reading config: EOF"},
+	}
+	for _, tt := range tests {
+		err, ok := recovered(tt.f).(error)
+		if !ok || err.Error() != tt.want {
+			t.Errorf("panic: got %v, want %q", err, tt.want)
+			continue
+		}
+		st := err.(*withStack).StackTrace()
+		if got := fmt.Sprintf("%n", st[0]); !strings.HasPrefix(got, "TestMust.func") {
+			t.Errorf("panic: stack starts in %s, want the caller of Must", got)
+		}
+	}
+}
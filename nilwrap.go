@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync/atomic"
+)
+
+// A NilWrapHook is told when one of the wrapping functions (Wrap, Wrapf,
+// WithMessage, WithMessagef, WithStack, or WrapWithData) is called with a nil
+// error. fn is the name of the function and caller is the frame that called
+// it.
+type NilWrapHook func(fn string, caller Frame)
+
+// nilWrapHook holds the NilWrapHook set with SetNilWrapHook.
+var nilWrapHook atomic.Value
+
+// SetNilWrapHook installs h to be called whenever a wrapping function is
+// given a nil error, and returns the previous hook. Setting a nil hook turns
+// the diagnostics off, which is the default.
+//
+// Wrapping a nil error is legal and returns nil, but it is often a sign that
+// an annotation is silently lost because an error variable was unexpectedly
+// nil. SetNilWrapHook is meant for debugging such code paths:
+//
+//	errors.SetNilWrapHook(errors.LogNilWraps(nil))
+func SetNilWrapHook(h NilWrapHook) (previous NilWrapHook) {
+	previous, _ = nilWrapHook.Load().(NilWrapHook)
+	nilWrapHook.Store(h)
+	return previous
+}
+
+// LogNilWraps returns a NilWrapHook writing the call site to l.
+// If l is nil, the standard logger is used.
+func LogNilWraps(l *log.Logger) NilWrapHook {
+	return func(fn string, caller Frame) {
+		msg := fmt.Sprintf("errors: %s called with a nil error at %+s:%d", fn, caller, caller)
+		if l == nil {
+			log.Print(msg)
+			return
+		}
+		l.Print(msg)
+	}
+}
+
+// nilWrap calls the NilWrapHook, if any, with the caller of the exported
+// function fn.
+func nilWrap(fn string) {
+	h, _ := nilWrapHook.Load().(NilWrapHook)
+	if h == nil {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	h(fn, Frame(pcs[0]))
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestSetNilWrapHook(t *testing.T) {
+	var got []string
+	prev := SetNilWrapHook(func(fn string, caller Frame) {
+		got = append(got, fmt.Sprintf("%s %n", fn, caller))
+	})
+	defer SetNilWrapHook(prev)
+
+	Wrap(nil, "reading")
+	WithMessagef(nil, "reading %d", 1)
+	Wrap(New("boom"), "reading")
+
+	want := []string{"Wrap TestSetNilWrapHook", "WithMessagef TestSetNilWrapHook"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("NilWrapHook: got %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	SetNilWrapHook(LogNilWraps(log.New(&buf, "", 0)))
+	WithStack(nil)
+	if out := buf.String(); !strings.HasPrefix(out, "errors: WithStack called with a nil error at github.com/noke-inc/lib_errors.TestSetNilWrapHook\n\t") || !strings.Contains(out, "nilwrap_test.go:") {
+		t.Errorf("LogNilWraps: got %q", out)
+	}
+}
@@ -0,0 +1,59 @@
+package errors
+
+import "sync/atomic"
+
+// KeyOp is the data key under which New, Errorf, Wrap, Wrapf, and
+// WrapWithData record the name of their caller when RecordOps is enabled.
+const KeyOp = "op"
+
+// recordOps is non-zero when New, Errorf, Wrap, Wrapf, and WrapWithData
+// record the name of their caller.
+var recordOps int32
+
+// RecordOps sets whether New, Errorf, Wrap, Wrapf, and WrapWithData record
+// the short name of the calling function under KeyOp, so that Ops can report
+// the logical path an error took without each caller naming itself. It is off
+// by default.
+func RecordOps(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&recordOps, v)
+}
+
+// withOp returns err annotated with the short name of the caller of the
+// exported function calling withOp if RecordOps is enabled, and err unchanged
+// otherwise.
+func withOp(err error) error {
+	if atomic.LoadInt32(&recordOps) == 0 {
+		return err
+	}
+	name := callerName(3)
+	if name == "" {
+		return err
+	}
+	// Each layer records its own op, so the value is set directly rather than
+	// through attachData, which would report every one as shadowing the next.
+	return &withData{err, map[string]interface{}{KeyOp: name}}
+}
+
+// Ops returns the operations recorded under KeyOp in err's chain, outermost
+// first, such as
+//
+//	[]string{"api.(*Server).handleLock", "lock.(*Service).Acquire", "store.(*Repo).Save"}
+//
+// or nil if none were recorded.
+func Ops(err error) []string {
+	var ops []string
+	for ; err != nil; err = Unwrap(err) {
+		w, ok := err.(*withData)
+		if !ok {
+			continue
+		}
+		if op, ok := w.data[KeyOp].(string); ok {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+type opsRepo struct{}
+
+func (opsRepo) save() error { return New("disk full") }
+
+type opsService struct{ repo opsRepo }
+
+func (s opsService) acquire() error { return Wrap(s.repo.save(), "saving") }
+
+func TestOps(t *testing.T) {
+	if ops := Ops(opsService{}.acquire()); ops != nil {
+		t.Errorf("Ops with RecordOps disabled: got %q, want nil", ops)
+	}
+
+	RecordOps(true)
+	defer RecordOps(false)
+	err := Wrapf(opsService{}.acquire(), "handling %d", 1)
+	want := []string{"lib_errors.TestOps", "lib_errors.opsService.acquire", "lib_errors.opsRepo.save"}
+	if got := Ops(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ops: got %q, want %q", got, want)
+	}
+	if got, want := err.Error(), "handling 1: saving: disk full"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if got := Ops(WithMessage(io.EOF, "reading")); got != nil {
+		t.Errorf("Ops(WithMessage): got %q, want nil", got)
+	}
+}
@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FromPanic converts a value returned by recover into a *PanicError whose
+// stack trace starts at the point of the panic rather than at the deferred
+// function that recovered it. FromPanic must be called by the deferred
+// function itself:
+//
+//	defer func() {
+//	        if err := errors.FromPanic(recover()); err != nil {
+//	                errors.Report(ctx, err)
+//	        }
+//	}()
+//
+// If v is nil, FromPanic returns nil.
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	return runHooks(&PanicError{
+		value: v,
+		stack: panicStack(callers()),
+	}, HookNew)
+}
+
+// PanicError is an error converted from a recovered panic by FromPanic. It
+// lets upper layers tell programming faults apart from ordinary failures;
+// see IsPanic.
+type PanicError struct {
+	value interface{}
+	*stack
+}
+
+// Value returns the value the panic was called with.
+func (p *PanicError) Value() interface{} { return p.value }
+
+// Error returns "panic: " followed by the panic value.
+func (p *PanicError) Error() string { return fmt.Sprintf("panic: %v", p.value) }
+
+// Unwrap returns the panic value if it is an error, keeping it in the chain,
+// and nil otherwise.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+func (p *PanicError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, p.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, p) {
+				return
+			}
+			if err := p.Unwrap(); err != nil {
+				fmt.Fprintf(s, "%+v%spanic", err, formatConfig().Separator)
+			} else {
+				io.WriteString(s, p.Error())
+			}
+			p.stack.Format(s, verb)
+			if isDeepest(p.Unwrap()) {
+				formatGlobalData(s)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, p.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", p.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like p.
+func (p *PanicError) GoString() string {
+	return fmt.Sprintf("errors.FromPanic(%#v)", p.value)
+}
+
+// IsPanic reports whether err's chain contains a *PanicError.
+func IsPanic(err error) bool {
+	var p *PanicError
+	return As(err, &p)
+}
+
+// panicStack returns s without the frames above the function that
+// panicked: those of the recovering function and of the runtime's panic
+// machinery. s is returned unchanged if it does not pass through a panic.
+func panicStack(s *stack) *stack {
+	for i, pc := range *s {
+		if Frame(pc).name() != "runtime.gopanic" {
+			continue
+		}
+		rest := (*s)[i+1:]
+		for len(rest) > 1 && strings.HasPrefix(Frame(rest[0]).name(), "runtime.") {
+			rest = rest[1:]
+		}
+		return &rest
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func panicking(v interface{}) (err error) {
+	defer func() { err = FromPanic(recover()) }()
+	panic(v)
+}
+
+func nilDeref() (err error) {
+	defer func() { err = FromPanic(recover()) }()
+	var p *struct{ n int }
+	return fmt.Errorf("%d", p.n)
+}
+
+func TestFromPanic(t *testing.T) {
+	if err := FromPanic(nil); err != nil {
+		t.Errorf("FromPanic(nil): got %v, want nil", err)
+	}
+
+	tests := []struct {
+		err       error
+		want      string
+		wantFrame string
+	}{
+		{panicking("boom"), "panic: boom", "panicking"},
+		{panicking(io.EOF), "panic: EOF", "panicking"},
+		{nilDeref(), "panic: runtime error: invalid memory address or nil pointer dereference", "nilDeref"},
+	}
+	for _, tt := range tests {
+		if tt.err.Error() != tt.want {
+			t.Errorf("FromPanic: got %q, want %q", tt.err.Error(), tt.want)
+		}
+		st := tt.err.(interface{ StackTrace() StackTrace }).StackTrace()
+		if got := fmt.Sprintf("%n", st[0]); got != tt.wantFrame {
+			t.Errorf("FromPanic(%q): stack starts in %s, want %s", tt.want, got, tt.wantFrame)
+		}
+	}
+	if !Is(panicking(io.EOF), io.EOF) {
+		t.Error("FromPanic: error value not kept in the chain")
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	err := Wrap(panicking(42), "handling request")
+	if !IsPanic(err) {
+		t.Fatal("IsPanic: got false for a recovered panic")
+	}
+	var p *PanicError
+	As(err, &p)
+	if p.Value() != 42 {
+		t.Errorf("Value: got %v, want 42", p.Value())
+	}
+	if IsPanic(New("boom")) || IsPanic(nil) {
+		t.Error("IsPanic: got true for an ordinary error")
+	}
+	if got, want := fmt.Sprintf("%#v", p), "errors.FromPanic(42)"; got != want {
+		t.Errorf("%%#v: got %q, want %q", got, want)
+	}
+}
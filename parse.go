@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parse parses the output of formatting an error of this package with %+v,
+// in the layout set with SetFormatConfig, back into a Snapshot, for tooling
+// that only has the text of an error, such as one copied from a log.
+//
+// Since %+v is meant for people, the result is a best effort:
+//
+//   - data values are recovered as strings, and a value containing a space
+//     followed by text that looks like the next key is split there;
+//   - each line that is neither data nor part of a stack frame is taken as
+//     the message of a new layer, so multi-line messages span layers;
+//   - frame file lines may be indented with spaces instead of a tab, as
+//     happens when text is copied from a terminal;
+//   - the expanded errors recorded as data values, those attached with
+//     WithSecondary or Suppress, the arguments recorded with WithArgs, and
+//     the table of attempts of an error returned by Retry are skipped.
+//
+// Parse returns an error if s contains no message, or a file line that does
+// not follow a function name.
+func Parse(s string) (Snapshot, error) {
+	var b snapshotBuilder
+	var global map[string]interface{}
+	c := formatConfig()
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if c.Separator != "\n" {
+		s = strings.ReplaceAll(s, c.Separator, "\n")
+	}
+	lines := strings.Split(s, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "", strings.HasPrefix(line, c.NestedPrefix),
+			strings.HasPrefix(line, c.SecondaryLabel), strings.HasPrefix(line, c.SuppressedLabel),
+			strings.HasPrefix(line, c.ArgsLabel):
+			continue
+		case strings.HasPrefix(line, c.AttemptsLabel):
+			if n, err := strconv.Atoi(strings.TrimPrefix(line, c.AttemptsLabel)); err == nil && n > 0 {
+				i += n + 1 // the header and one row per attempt
+			}
+			continue
+		case isDataLine(line, c.DataLabel):
+			b.layer(snapshotData).Data = parseDataMap(strings.TrimPrefix(line, c.DataLabel))
+			continue
+		case isDataLine(line, c.GlobalDataLabel):
+			global = parseDataMap(strings.TrimPrefix(line, c.GlobalDataLabel))
+			continue
+		case isIndented(line, c.FrameIndent):
+			return Snapshot{}, Errorf("line %d: file %q does not follow a function name", i+1, strings.TrimSpace(line))
+		}
+		if i+1 < len(lines) && isIndented(lines[i+1], c.FrameIndent) {
+			var frames []SnapshotFrame
+			for ; i+1 < len(lines) && isIndented(lines[i+1], c.FrameIndent); i += 2 {
+				f, ok := parseFrame(lines[i], strings.TrimPrefix(lines[i+1], c.FrameIndent))
+				if !ok {
+					return Snapshot{}, Errorf("line %d: malformed stack frame %q", i+2, strings.TrimSpace(lines[i+1]))
+				}
+				frames = append(frames, f)
+			}
+			i--
+			b.layer(snapshotStack).Stack = frames
+			continue
+		}
+		b.layer(snapshotMessage).Message = line
+	}
+
+	snap := b.snapshot()
+	hasMessage := false
+	for _, l := range snap.Layers {
+		hasMessage = hasMessage || l.Message != ""
+	}
+	if !hasMessage {
+		return Snapshot{}, New("no error message found")
+	}
+	snap.GlobalData = global
+	return snap, nil
+}
+
+// isDataLine reports whether line is a map printed after the given label.
+func isDataLine(line, label string) bool {
+	return strings.HasPrefix(line, label+"map[") && strings.HasSuffix(strings.TrimSpace(line), "]")
+}
+
+// isIndented reports whether line is the file half of a stack frame,
+// indented with indent or with whitespace.
+func isIndented(line, indent string) bool {
+	if indent != "\t" && strings.HasPrefix(line, indent) {
+		return true
+	}
+	return len(line) > 1 && (line[0] == '\t' || line[0] == ' ') && strings.TrimSpace(line) != ""
+}
+
+// parseFrame parses a stack frame printed with %+v as a function line
+// followed by an indented "file:line" line.
+func parseFrame(function, location string) (SnapshotFrame, bool) {
+	location = strings.TrimSpace(location)
+	i := strings.LastIndexByte(location, ':')
+	if i < 0 {
+		return SnapshotFrame{}, false
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return SnapshotFrame{}, false
+	}
+	return SnapshotFrame{
+		Function: strings.TrimSpace(function),
+		File:     location[:i],
+		Line:     line,
+	}, true
+}
+
+// parseDataMap parses a map[string]interface{} printed with %v. fmt prints
+// maps with their keys sorted, so a candidate key that does not sort after
+// the previous one is taken as part of the previous value.
+func parseDataMap(s string) map[string]interface{} {
+	s = strings.TrimSpace(s)
+	s = s[len("map[") : len(s)-1]
+
+	data := make(map[string]interface{})
+	key, start, depth := "", -1, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+			continue
+		case ']':
+			depth--
+			continue
+		}
+		if depth != 0 || i > 0 && s[i-1] != ' ' {
+			continue
+		}
+		k, ok := dataKeyAt(s[i:])
+		if !ok || start >= 0 && k <= key {
+			continue
+		}
+		if start >= 0 {
+			data[key] = s[start : i-1]
+		}
+		key, start = k, i+len(k)+1
+	}
+	if start >= 0 {
+		data[key] = s[start:]
+	}
+	return data
+}
+
+// dataKeyAt returns the key at the start of s if s begins with "key:", where
+// key contains no spaces, colons, or brackets.
+func dataKeyAt(s string) (string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ':':
+			return s[:i], i > 0
+		case ' ', '[', ']':
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseDataMap(t *testing.T) {
+	tests := []struct {
+		in   string
+		want map[string]interface{}
+	}{
+		{"map[]", map[string]interface{}{}},
+		{"map[lock_id:7]", map[string]interface{}{"lock_id": "7"}},
+		{"map[a:1 b:two words c:3]", map[string]interface{}{"a": "1", "b": "two words", "c": "3"}},
+		{"map[url:https://x/y?q=1 z:note: see log]", map[string]interface{}{"url": "https://x/y?q=1", "z": "note: see log"}},
+		{"map[b:see a:1]", map[string]interface{}{"b": "see a:1"}},
+		{"map[h:map[Content-Type:text/plain X-Id:1] s:500]", map[string]interface{}{"h": "map[Content-Type:text/plain X-Id:1]", "s": "500"}},
+	}
+	for _, tt := range tests {
+		if got := parseDataMap(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseDataMap(%q): got %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	get := FakeFrame("github.com/acme/store.Get", "/src/store/store.go", 42)
+	handle := FakeFrame("github.com/acme/api.handle", "/src/api/api.go", 17)
+
+	defer SetStackProvider(SetStackProvider(FixedStack(get, handle)))
+	err := New("lock offline")
+	err = WrapWithData(err, "reading state", "lock_id", "7", "region", "eu west")
+	err = WithData(Wrap(err, "handling request"), "user", "bob")
+	SetGlobalData("service", "lockd")
+	defer SetGlobalData()
+
+	got, perr := Parse(fmt.Sprintf("%+v", err))
+	if perr != nil {
+		t.Fatalf("Parse: %v", perr)
+	}
+	if want := NewSnapshot(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse: got\n%#v\nwant\n%#v", got, want)
+	}
+	if n := len(got.Layers); n != 4 {
+		t.Fatalf("Parse: got %d layers, want 4", n)
+	}
+	if l := got.Layers[1]; l.Message != "handling request" || len(l.Stack) != 2 || l.Data != nil {
+		t.Errorf("Parse: layer 1: got %#v", l)
+	}
+
+	// Tabs become spaces when copied from a terminal.
+	got, perr = Parse("boom\nmain.main\n        /src/main.go:7\n")
+	want := Snapshot{Layers: []SnapshotLayer{{
+		Message: "boom",
+		Stack:   []SnapshotFrame{{"main.main", "/src/main.go", 7}},
+	}}}
+	if perr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse(spaces): got %#v, %v, want %#v", got, perr, want)
+	}
+
+	for _, in := range []string{"", "\n", "\t/src/main.go:7", "boom\nmain.main\n\t/src/main.go:x"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q): want error", in)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package errors
+
+import "context"
+
+// A Stage is one step of a Pipeline. It returns err transformed, or nil to
+// drop it and end the Pipeline.
+type Stage func(ctx context.Context, err error) error
+
+// A Pipeline declares once how errors are processed at a service boundary,
+// instead of scattering the policy across handlers:
+//
+//	var boundary = errors.Pipeline{
+//	        errors.IgnoreStage(context.Canceled),
+//	        errors.TranslateStage(translate),
+//	        errors.ClassifyStage(classify),
+//	        errors.ReportStage(errors.SampledReporter(tracker, sampler)),
+//	        errors.RedactStage("sql", "password"),
+//	}
+//
+//	func (s *Server) Get(ctx context.Context, req *Request) (*Lock, error) {
+//	        lock, err := s.get(ctx, req)
+//	        return lock, boundary.Process(ctx, err)
+//	}
+type Pipeline []Stage
+
+// Process passes err through the stages of p in order and returns the
+// result.
+// If err is nil, Process returns nil without running any stage.
+func (p Pipeline) Process(ctx context.Context, err error) error {
+	for _, stage := range p {
+		if err == nil {
+			return nil
+		}
+		err = stage(ctx, err)
+	}
+	return err
+}
+
+// IgnoreStage returns a Stage dropping the errors that match any of targets
+// according to Is; see Ignore.
+func IgnoreStage(targets ...error) Stage {
+	return func(ctx context.Context, err error) error {
+		return Ignore(err, targets...)
+	}
+}
+
+// TranslateStage returns a Stage translating errors with tr.
+func TranslateStage(tr Translator) Stage {
+	return func(ctx context.Context, err error) error {
+		return tr.Translate(err)
+	}
+}
+
+// ClassifyStage returns a Stage annotating errors that have no Kind yet
+// with the Kind returned by classify, unless it is KindUnknown.
+func ClassifyStage(classify func(error) Kind) Stage {
+	return func(ctx context.Context, err error) error {
+		if KindOf(err) != KindUnknown {
+			return err
+		}
+		if k := classify(err); k != KindUnknown {
+			return WithKind(err, k)
+		}
+		return err
+	}
+}
+
+// ReportStage returns a Stage delivering errors to r and passing them on
+// unchanged. If r is nil, errors are delivered with Report. Sampling is
+// obtained by passing a SampledReporter.
+func ReportStage(r Reporter) Stage {
+	return func(ctx context.Context, err error) error {
+		if r == nil {
+			Report(ctx, err)
+		} else {
+			r.Report(ctx, err)
+		}
+		return err
+	}
+}
+
+// LimitStage returns a Stage removing the data classified above max
+// anywhere in the chain, using LimitData, for pipelines feeding a sink that
+// may only emit data up to max.
+func LimitStage(max Classification) Stage {
+	return func(ctx context.Context, err error) error {
+		return LimitData(err, max)
+	}
+}
+
+// RedactStage returns a Stage removing the data recorded under keys
+// anywhere in the chain, including the branches of Join and the errors
+// recorded as data, as LimitStage does.
+func RedactStage(keys ...string) Stage {
+	return func(ctx context.Context, err error) error {
+		redacted, _ := mapTree(err, func(l Layer) Layer {
+			for _, k := range keys {
+				delete(l.Data, k)
+			}
+			return l
+		})
+		return redacted
+	}
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	errNotFound := New("lock not found")
+	var reported []error
+	p := Pipeline{
+		IgnoreStage(context.Canceled),
+		TranslateStage(Translator{{From: io.EOF, To: errNotFound}}),
+		ClassifyStage(func(err error) Kind {
+			if Is(err, errNotFound) {
+				return KindNotFound
+			}
+			return KindUnknown
+		}),
+		ReportStage(ReporterFunc(func(ctx context.Context, err error) { reported = append(reported, err) })),
+		RedactStage("sql"),
+	}
+	ctx := context.Background()
+
+	err := p.Process(ctx, WithData(io.EOF, "sql", "SELECT *", "table", "locks"))
+	if !Is(err, errNotFound) || KindOf(err) != KindNotFound {
+		t.Errorf("Process: got %v (%v), want the classified domain error", err, KindOf(err))
+	}
+	if _, ok := GetValue(err, "sql"); ok {
+		t.Error("Process: redacted data still present")
+	}
+	if len(reported) != 1 {
+		t.Fatalf("ReportStage: got %d reports, want 1", len(reported))
+	}
+	if v, _ := GetValue(reported[0], "sql"); v != "SELECT *" {
+		t.Error("ReportStage: the report did not see the data redacted after it")
+	}
+
+	if err := p.Process(ctx, Wrap(context.Canceled, "waiting")); err != nil || len(reported) != 1 {
+		t.Errorf("Process of an ignored error: got %v with %d reports", err, len(reported))
+	}
+	if p.Process(ctx, nil) != nil {
+		t.Error("Process(nil): want nil")
+	}
+}
+
+func TestRedactStageJoin(t *testing.T) {
+	err := Wrap(Join(WithData(New("a"), "password", "hunter2"), New("b")), "batch")
+	redacted := RedactStage("password")(context.Background(), err)
+	if got := fmt.Sprintf("%+v", redacted); strings.Contains(got, "hunter2") {
+		t.Errorf("RedactStage: got %q, want the password removed from the branch", got)
+	}
+	if got, want := redacted.Error(), err.Error(); got != want {
+		t.Errorf("RedactStage: got %q, want %q", got, want)
+	}
+}
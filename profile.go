@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// ProfileLabel is the pprof label set by DoLabeled and LabeledReporter.
+const ProfileLabel = "error_fingerprint"
+
+// DoLabeled calls f with a context carrying the pprof label ProfileLabel set
+// to the Fingerprint of err, and sets the same label on the calling goroutine
+// while f runs, so that CPU profiles taken during an error storm attribute
+// the time spent handling each error to its code path. The goroutine's labels
+// are restored to those of ctx when f returns.
+// If err is nil, DoLabeled calls f(ctx) unchanged.
+func DoLabeled(ctx context.Context, err error, f func(context.Context)) {
+	if err == nil {
+		f(ctx)
+		return
+	}
+	pprof.Do(ctx, pprof.Labels(ProfileLabel, Fingerprint(err)), f)
+}
+
+// LabeledReporter returns a Reporter forwarding each error to r with
+// DoLabeled, so that the time r spends on it is labeled with its fingerprint.
+func LabeledReporter(r Reporter) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		DoLabeled(ctx, err, func(ctx context.Context) {
+			r.Report(ctx, err)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestLabeledReporter(t *testing.T) {
+	var got string
+	var ok bool
+	r := LabeledReporter(ReporterFunc(func(ctx context.Context, err error) {
+		got, ok = pprof.Label(ctx, ProfileLabel)
+	}))
+	err := Wrap(io.EOF, "reading")
+	r.Report(context.Background(), err)
+	if !ok || got != Fingerprint(err) {
+		t.Errorf("label: got %q, %v, want %q", got, ok, Fingerprint(err))
+	}
+
+	DoLabeled(context.Background(), nil, func(ctx context.Context) {
+		if _, ok := pprof.Label(ctx, ProfileLabel); ok {
+			t.Errorf("DoLabeled(nil): got a label")
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PublicDataKeys lists the data keys classified ClassPublic unless set
+// otherwise with ClassifyKeys. Keys that callers outside the service may
+// rely on can be appended to it during program initialization.
+var PublicDataKeys = []string{KeyCode, KeyKind, KeyStatusCode, KeyUserMessage, KeyTitle, KeyHint, KeyRetryAfter}
+
+// Public returns a new error that is safe to return to external API
+// clients while err itself is logged internally. Its message is err's
+// UserMessage or, failing that, the lower-case text of err's HTTPStatus,
+// such as "not found", and its data holds the values found in err's chain
+// of the keys classified ClassPublic (see KeyClassification). It records
+// no stack trace and does not wrap err.
+// If err is nil, Public returns nil.
+func Public(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := UserMessage(err)
+	if msg == "" {
+		msg = strings.ToLower(HTTPStatusText(HTTPStatus(err)))
+	}
+	return &publicError{msg, LimitedData(err, ClassPublic)}
+}
+
+type publicError struct {
+	msg  string
+	data map[string]interface{}
+}
+
+func (p *publicError) Error() string { return p.msg }
+
+// DataCache returns a copy of the data copied by Public.
+func (p *publicError) DataCache() map[string]interface{} {
+	kv := make(map[string]interface{}, len(p.data))
+	for k, v := range p.data {
+		kv[k] = v
+	}
+	return kv
+}
+
+func (p *publicError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			fmt.Fprintf(s, "errors.Public(errors.WithData(errors.New(%q)%s))", p.msg, goStringData(p.data))
+			return
+		}
+		if s.Flag('+') && len(p.data) > 0 {
+			c := formatConfig()
+			fmt.Fprintf(s, "%s%s%s%s", p.msg, c.Separator, c.DataLabel, formatDataMap(p.data))
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, p.msg)
+	case 'q':
+		fmt.Fprintf(s, "%q", p.msg)
+	}
+}
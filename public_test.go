@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPublic(t *testing.T) {
+	internal := WithUserMessage(WithCode(WrapWithData(New("SELECT failed on db-3"), "loading lock", "sql", "SELECT *"), "lock_missing"), "That lock does not exist.")
+	internal = WithKind(internal, KindNotFound)
+
+	pub := Public(internal)
+	if pub.Error() != "That lock does not exist." {
+		t.Errorf("Error: got %q", pub.Error())
+	}
+	if Code(pub) != "lock_missing" || KindOf(pub) != KindNotFound || HTTPStatus(pub) != 404 {
+		t.Errorf("public data: got code %q, kind %v", Code(pub), KindOf(pub))
+	}
+	if _, ok := GetValue(pub, "sql"); ok {
+		t.Error("GetValue: internal data copied")
+	}
+	if Is(pub, internal) || Unwrap(pub) != nil {
+		t.Error("Public: wraps the internal error")
+	}
+	want := "That lock does not exist.\nERROR DATA: map[code:lock_missing kind:not_found user_message:That lock does not exist.]"
+	if got := fmt.Sprintf("%+v", pub); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	if got := Public(New("disk on fire")).Error(); got != "internal server error" {
+		t.Errorf("Public without a user message: got %q", got)
+	}
+	if Public(nil) != nil {
+		t.Error("Public(nil): want nil")
+	}
+}
@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// dataSection returns the data section printed with %+v for data: the label
+// and the map, followed by the %+v output of each error value that has more
+// to show than its message, every line prefixed with the NestedPrefix.
+func dataSection(data map[string]interface{}, c FormatConfig) string {
+	var b strings.Builder
+	b.WriteString(c.DataLabel)
+	b.WriteString(formatDataMap(data))
+	for _, k := range sortedKeys(data) {
+		err, ok := data[k].(error)
+		if !ok {
+			continue
+		}
+		full := fmt.Sprintf("%+v", err)
+		if full == err.Error() {
+			continue
+		}
+		for i, line := range strings.Split(full, c.Separator) {
+			b.WriteString(c.Separator)
+			b.WriteString(c.NestedPrefix)
+			if i == 0 {
+				b.WriteString(k)
+				b.WriteString(": ")
+			}
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of data in increasing order.
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDataMap returns data as fmt prints a map with %v, keys sorted, but
+// with each value rendered by formatValue.
+func formatDataMap(data map[string]interface{}) string {
+	var b strings.Builder
+	b.WriteString("map[")
+	for i, k := range sortedKeys(data) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(formatValue(data[k]))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// formatValue renders a data value with its intended representation: the
+// message of an error, the String of a fmt.Stringer, or the text of an
+// encoding.TextMarshaler, rather than a dump of its fields. Other values,
+// and values whose methods panic, are formatted with %v.
+func formatValue(v interface{}) (s string) {
+	if v == nil {
+		return "<nil>"
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return fmt.Sprintf("%v", v)
+	}
+	defer func() {
+		if recover() != nil {
+			s = fmt.Sprintf("%v", v)
+		}
+	}()
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
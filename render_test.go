@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type lockID struct{ site, n int }
+
+func (id lockID) String() string { return fmt.Sprintf("L-%d-%d", id.site, id.n) }
+
+type serial struct{ hi, lo uint16 }
+
+func (s serial) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%04x%04x", s.hi, s.lo)), nil
+}
+
+type panicky struct{ n int }
+
+func (p *panicky) String() string { panic("boom") }
+
+func TestFormatDataMap(t *testing.T) {
+	var nilLock *lockID
+	data := map[string]interface{}{
+		"lock":    lockID{1, 2},
+		"serial":  serial{0xbeef, 1},
+		"cause":   New("disk full"),
+		"kind":    KindNotFound,
+		"elapsed": 1500 * time.Millisecond,
+		"count":   3,
+		"nil":     nil,
+		"nilptr":  nilLock,
+	}
+	want := "map[cause:disk full count:3 elapsed:1.5s kind:not_found lock:L-1-2 nil:<nil> nilptr:<nil> serial:beef0001]"
+	if got := formatDataMap(data); got != want {
+		t.Errorf("formatDataMap:\n got %q\nwant %q", got, want)
+	}
+	if got, want := formatValue(&panicky{1}), fmt.Sprintf("%v", &panicky{1}); got != want {
+		t.Errorf("formatValue with a panicking String: got %q, want %q", got, want)
+	}
+}
+
+func TestNestedErrorData(t *testing.T) {
+	defer SetStackProvider(SetStackProvider(FixedStack(FakeFrame("main.main", "/src/main.go", 7))))
+	rollback := WithData(New("rollback failed"), "tx", 9)
+	err := WithData(New("commit failed"), "rollback", rollback, "plain", io.EOF)
+
+	want := "commit failed\n" +
+		"main.main\n\t/src/main.go:7\n" +
+		"ERROR DATA: map[plain:EOF rollback:rollback failed]\n" +
+		"> rollback: rollback failed\n" +
+		"> main.main\n" +
+		"> \t/src/main.go:7\n" +
+		"> ERROR DATA: map[tx:9]"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	if got := FormatData(err); got != strings.SplitN(want, "\n", 4)[3] {
+		t.Errorf("FormatData: got %q", got)
+	}
+	snap, perr := Parse(want)
+	if perr != nil || len(snap.Layers) != 2 || snap.Layers[1].Message != "commit failed" {
+		t.Errorf("Parse: got %+v, %v", snap, perr)
+	}
+
+	b, jerr := json.Marshal(NewSnapshot(err))
+	if jerr != nil {
+		t.Fatalf("Marshal: %v", jerr)
+	}
+	var decoded Snapshot
+	if jerr := json.Unmarshal(b, &decoded); jerr != nil {
+		t.Fatalf("Unmarshal: %v", jerr)
+	}
+	v, _ := GetValue(decoded.Err(), "rollback")
+	nested, ok := v.(error)
+	if !ok || nested.Error() != "rollback failed" {
+		t.Fatalf("rollback after a JSON round trip: got %#v", v)
+	}
+	if tx, _ := GetValue(nested, "tx"); tx != float64(9) {
+		t.Errorf("nested data after a JSON round trip: got %v", tx)
+	}
+}
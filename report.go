@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// A Reporter delivers errors to an error tracker, log, or other sink.
+// Adapters for specific services implement Reporter and are registered once
+// with RegisterReporter, after which Report is used everywhere.
+type Reporter interface {
+	Report(ctx context.Context, err error)
+}
+
+// The ReporterFunc type is an adapter allowing ordinary functions to be used
+// as Reporters.
+type ReporterFunc func(ctx context.Context, err error)
+
+// Report calls f(ctx, err).
+func (f ReporterFunc) Report(ctx context.Context, err error) { f(ctx, err) }
+
+// MultiReporter is a Reporter delivering every error to each of its Reporters
+// in order.
+type MultiReporter []Reporter
+
+// Report calls Report on each Reporter of m.
+func (m MultiReporter) Report(ctx context.Context, err error) {
+	for _, r := range m {
+		r.Report(ctx, err)
+	}
+}
+
+// SampledReporter returns a Reporter forwarding to r only the errors that s
+// decides to sample.
+func SampledReporter(r Reporter, s *Sampler) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		if s.Sample(err) {
+			r.Report(ctx, err)
+		}
+	})
+}
+
+// LogReporter returns a Reporter writing each error to l formatted with %+v.
+// If l is nil, the standard logger is used.
+func LogReporter(l *log.Logger) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error) {
+		if l == nil {
+			log.Printf("%+v", err)
+			return
+		}
+		l.Printf("%+v", err)
+	})
+}
+
+var (
+	reportersMu sync.Mutex
+	reporters   atomic.Value // MultiReporter
+)
+
+// RegisterReporter adds r to the Reporters used by Report. RegisterReporter is
+// meant to be called during program initialization.
+func RegisterReporter(r Reporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	old, _ := reporters.Load().(MultiReporter)
+	rs := make(MultiReporter, len(old), len(old)+1)
+	copy(rs, old)
+	reporters.Store(append(rs, r))
+}
+
+// Report delivers err to every Reporter registered with RegisterReporter.
+// If err is nil, Report does nothing.
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	rs, _ := reporters.Load().(MultiReporter)
+	rs.Report(ctx, err)
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// resetReporters removes every registered Reporter.
+func resetReporters() {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters.Store(MultiReporter(nil))
+}
+
+func TestReport(t *testing.T) {
+	defer resetReporters()
+
+	// reporting without registered reporters is a no-op
+	Report(context.Background(), io.EOF)
+
+	var got []string
+	record := func(name string) Reporter {
+		return ReporterFunc(func(ctx context.Context, err error) {
+			got = append(got, name+":"+err.Error())
+		})
+	}
+	RegisterReporter(record("a"))
+	RegisterReporter(MultiReporter{record("b"), SampledReporter(record("c"), NewSampler(0, 1))})
+
+	Report(context.Background(), io.EOF)
+	Report(context.Background(), nil)
+	Report(context.Background(), io.EOF)
+
+	want := []string{"a:EOF", "b:EOF", "c:EOF", "a:EOF", "b:EOF"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Report: got %v, want %v", got, want)
+	}
+}
+
+func TestLogReporter(t *testing.T) {
+	var buf bytes.Buffer
+	LogReporter(log.New(&buf, "", 0)).Report(context.Background(), WrapWithData(io.EOF, "reading", "key", "val"))
+	for _, want := range []string{"EOF", "reading", "ERROR DATA: map[key:val]", "lib_errors.TestLogReporter"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("LogReporter: got %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// KeyRequestID is the data key under which WithRequestID records the ID of
+// the request, or the correlation ID of the work, during which an error
+// occurred.
+const KeyRequestID = "request_id"
+
+// WithRequestID annotates err with the ID of the request during which it
+// occurred, so that it can be correlated with the logs of other services.
+// If err is nil, WithRequestID returns nil.
+func WithRequestID(err error, id string) error {
+	return WithData(err, KeyRequestID, id)
+}
+
+// WithContextRequestID annotates err with the request ID found in ctx by
+// RequestIDFromContext, as WithRequestID does. If ctx has no request ID,
+// err is returned unchanged.
+// If err is nil, WithContextRequestID returns nil.
+func WithContextRequestID(ctx context.Context, err error) error {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return WithRequestID(err, id)
+	}
+	return err
+}
+
+// RequestID returns the shallowest request ID recorded with WithRequestID in
+// err's chain, and whether there was one.
+func RequestID(err error) (string, bool) {
+	if v, ok := GetValue(err, KeyRequestID); ok {
+		if id, ok := v.(string); ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// requestIDKey is the context key of the request ID set with
+// ContextWithRequestID.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// commonRequestIDKeys are the plain string context keys under which
+// middleware commonly stores request IDs.
+var commonRequestIDKeys = []string{"request_id", "requestID", "requestId", "X-Request-Id", "x-request-id"}
+
+var requestIDKeys struct {
+	sync.RWMutex
+	keys []interface{}
+}
+
+// RegisterRequestIDKey makes RequestIDFromContext look for request IDs under
+// the context key key, such as the one used by the request ID middleware of
+// a router. RegisterRequestIDKey is meant to be called during program
+// initialization.
+func RegisterRequestIDKey(key interface{}) {
+	requestIDKeys.Lock()
+	defer requestIDKeys.Unlock()
+	requestIDKeys.keys = append(requestIDKeys.keys, key)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, and whether
+// there is one. It is looked for under the key of ContextWithRequestID, then
+// under the keys registered with RegisterRequestIDKey in order, then under
+// the string keys "request_id", "requestID", "requestId", "X-Request-Id",
+// and "x-request-id". The value found may be a string or a fmt.Stringer.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if id, ok := requestIDValue(ctx, requestIDKey{}); ok {
+		return id, true
+	}
+	requestIDKeys.RLock()
+	keys := requestIDKeys.keys
+	requestIDKeys.RUnlock()
+	for _, k := range keys {
+		if id, ok := requestIDValue(ctx, k); ok {
+			return id, true
+		}
+	}
+	for _, k := range commonRequestIDKeys {
+		if id, ok := requestIDValue(ctx, k); ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// requestIDValue returns the non-empty request ID stored in ctx under key,
+// and whether there is one.
+func requestIDValue(ctx context.Context, key interface{}) (string, bool) {
+	var id string
+	switch v := ctx.Value(key).(type) {
+	case string:
+		id = v
+	case fmt.Stringer:
+		id = v.String()
+	}
+	return id, id != ""
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+type routerKey int
+
+type traceID string
+
+func (id traceID) String() string { return string(id) }
+
+func TestRequestID(t *testing.T) {
+	RegisterRequestIDKey(routerKey(0))
+	bg := context.Background()
+
+	tests := []struct {
+		ctx    context.Context
+		want   string
+		wantOk bool
+	}{
+		{bg, "", false},
+		{ContextWithRequestID(bg, "r-1"), "r-1", true},
+		{context.WithValue(bg, routerKey(0), "r-2"), "r-2", true},
+		{context.WithValue(bg, routerKey(0), traceID("r-3")), "r-3", true},
+		{context.WithValue(bg, "X-Request-Id", "r-4"), "r-4", true},
+		{context.WithValue(ContextWithRequestID(bg, "r-1"), routerKey(0), "r-2"), "r-1", true},
+		{context.WithValue(bg, routerKey(0), 5), "", false},
+		{ContextWithRequestID(bg, ""), "", false},
+	}
+	for i, tt := range tests {
+		got, ok := RequestIDFromContext(tt.ctx)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: RequestIDFromContext: got (%q, %v), want (%q, %v)", i+1, got, ok, tt.want, tt.wantOk)
+		}
+		got, ok = RequestID(Wrap(WithContextRequestID(tt.ctx, io.EOF), "wrapped"))
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: RequestID: got (%q, %v), want (%q, %v)", i+1, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	if id, ok := RequestID(WithRequestID(io.EOF, "r-9")); id != "r-9" || !ok {
+		t.Errorf("RequestID(WithRequestID): got (%q, %v)", id, ok)
+	}
+	if WithContextRequestID(bg, nil) != nil {
+		t.Error("WithContextRequestID(nil): want nil")
+	}
+}
@@ -0,0 +1,68 @@
+package errors
+
+// Result holds either a value of type T or the error that prevented it,
+// for pipeline-style code that passes outcomes between stages and unpacks
+// them at the end.
+type Result[T any] struct {
+	val T
+	err error
+}
+
+// Ok returns a successful Result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{val: v}
+}
+
+// Err returns a failed Result holding err. A nil err yields a successful
+// Result holding the zero value of T.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf returns a Result from the values of a call returning (T, error),
+// holding v if err is nil and err otherwise:
+//
+//	r := errors.ResultOf(store.Get(ctx, id))
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// Then returns the Result of calling f with the value of r if r succeeded,
+// and a failed Result holding r's error otherwise.
+func Then[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return ResultOf(f(r.val))
+}
+
+// IsOk reports whether r succeeded.
+func (r Result[T]) IsOk() bool { return r.err == nil }
+
+// Err returns the error held by r, or nil if r succeeded.
+func (r Result[T]) Err() error { return r.err }
+
+// Value returns the value held by r, which is the zero value of T if r
+// failed.
+func (r Result[T]) Value() T { return r.val }
+
+// Unwrap returns the value and error held by r. If r failed, the error is
+// annotated as by Wrap with a stack trace at the point Unwrap is called and
+// the supplied message, so that each stage unpacking a Result records where
+// the failure surfaced.
+func (r Result[T]) Unwrap(message string) (T, error) {
+	if r.err == nil {
+		return r.val, nil
+	}
+	err := error(&withMessage{
+		error: r.err,
+		msg:   message,
+	})
+	return r.val, runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
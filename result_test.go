@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	r := Then(Ok("42"), strconv.Atoi)
+	if v, err := r.Unwrap("parsing"); err != nil || v != 42 || !r.IsOk() {
+		t.Errorf("Unwrap: got %v, %v, want 42", v, err)
+	}
+
+	r = Then(ResultOf("x", nil), strconv.Atoi)
+	if r.IsOk() || r.Err() == nil || r.Value() != 0 {
+		t.Fatalf("Then: got %v, %v, want failure", r.Value(), r.Err())
+	}
+	doubled := Then(r, func(n int) (int, error) {
+		t.Error("Then: f called for failed Result")
+		return n * 2, nil
+	})
+	_, err := doubled.Unwrap("parsing count")
+	if got, want := err.Error(), `parsing count: strconv.Atoi: parsing "x": invalid syntax`; got != want {
+		t.Errorf("Unwrap: got %q, want %q", got, want)
+	}
+	st := err.(*withStack).StackTrace()
+	if got := fmt.Sprintf("%n", st[0]); got != "TestResult" {
+		t.Errorf("Unwrap: stack starts in %s, want TestResult", got)
+	}
+
+	if _, err := Err[int](io.EOF).Unwrap("reading"); !Is(err, io.EOF) {
+		t.Errorf("Err: got %v, want EOF", err)
+	}
+	if !Err[int](nil).IsOk() {
+		t.Error("Err(nil): want success")
+	}
+}
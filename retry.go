@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyRetryAfter is the data key under which WithRetryAfter records how long a
+// caller should wait before retrying the failed operation.
+const KeyRetryAfter = "retry_after"
+
+// WithRetryAfter annotates err with a hint that the failed operation should
+// not be retried for at least d. HTTP handlers, client backoff, and queue
+// redelivery all read the hint with RetryAfter.
+// If err is nil, WithRetryAfter returns nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	return WithData(err, KeyRetryAfter, d)
+}
+
+// RetryAfter returns the shallowest hint recorded with WithRetryAfter in err's
+// chain and whether there was one.
+func RetryAfter(err error) (time.Duration, bool) {
+	if v, ok := GetValue(err, KeyRetryAfter); ok {
+		if d, ok := v.(time.Duration); ok {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses the value of a Retry-After HTTP header, which is
+// either a number of seconds or an HTTP date, relative to now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// permanent marks the error it wraps as a permanent failure.
+type permanent struct{ Base }
+
+func (permanent) Permanent() bool { return true }
+
+// MarkPermanent annotates err as a permanent failure: repeating the operation
+// cannot succeed, so circuit breakers should trip and job queues should drop
+// the work rather than redeliver it. Permanence is independent of any
+// RetryAfter hint.
+// If err is nil, MarkPermanent returns nil.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanent{Base{err}}
+}
+
+// IsPermanent reports whether err was marked with MarkPermanent. Errors of
+// other packages can take part by implementing
+//
+//	type permanenter interface {
+//	        Permanent() bool
+//	}
+//
+// in which case the shallowest such error in the chain decides.
+func IsPermanent(err error) bool {
+	var p interface{ Permanent() bool }
+	return As(err, &p) && p.Permanent()
+}
+
+// sideEffects marks the error it wraps as having left side effects behind.
+type sideEffects struct{ Base }
+
+func (sideEffects) SideEffects() bool { return true }
+
+// MarkSideEffects annotates err as a failure that may have left some of its
+// work done, such as a lock state that was partly written, so that the
+// operation is not safe to blindly retry. Only the failing code knows this;
+// callers check it with HasSideEffects.
+// If err is nil, MarkSideEffects returns nil.
+func MarkSideEffects(err error) error {
+	if err == nil {
+		return nil
+	}
+	return sideEffects{Base{err}}
+}
+
+// HasSideEffects reports whether err was marked with MarkSideEffects. Errors
+// of other packages can take part by implementing
+//
+//	type sideEffecter interface {
+//	        SideEffects() bool
+//	}
+//
+// in which case the shallowest such error in the chain decides.
+func HasSideEffects(err error) bool {
+	var s interface{ SideEffects() bool }
+	return As(err, &s) && s.SideEffects()
+}
+
+// RetryBaseDelay and RetryMaxDelay bound the delays returned by NextDelay.
+var (
+	RetryBaseDelay = 100 * time.Millisecond
+	RetryMaxDelay  = 30 * time.Second
+)
+
+// NextDelay returns how long to wait before retrying an operation after
+// attempt number attempt (counting from 1) failed with err, and whether it
+// should be retried at all, so that retry loops apply the same policy
+// everywhere:
+//
+//   - errors marked with MarkPermanent or MarkSideEffects, and those of
+//     KindInvalid, KindNotFound, KindUnauthenticated, KindPermission, and
+//     KindCanceled, are not retried;
+//   - a RetryAfter hint is honored as is;
+//   - otherwise the delay starts at RetryBaseDelay, or ten times that for
+//     KindRateLimited, and doubles with each attempt up to RetryMaxDelay.
+//
+// NextDelay is a Backoff, for use with Retry.
+// If err is nil, NextDelay returns 0 and false.
+func NextDelay(err error, attempt int) (time.Duration, bool) {
+	if err == nil || IsPermanent(err) || HasSideEffects(err) {
+		return 0, false
+	}
+	kind := KindOf(err)
+	switch kind {
+	case KindInvalid, KindNotFound, KindUnauthenticated, KindPermission, KindCanceled:
+		return 0, false
+	}
+	if d, ok := RetryAfter(err); ok {
+		return d, true
+	}
+	d := RetryBaseDelay
+	if kind == KindRateLimited {
+		d *= 10
+	}
+	for i := 1; i < attempt && d < RetryMaxDelay; i++ {
+		d *= 2
+	}
+	if d > RetryMaxDelay {
+		d = RetryMaxDelay
+	}
+	return d, true
+}
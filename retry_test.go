@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		err    error
+		want   time.Duration
+		wantOk bool
+	}{
+		{nil, 0, false},
+		{io.EOF, 0, false},
+		{WithRetryAfter(io.EOF, time.Second), time.Second, true},
+		{Wrap(WithRetryAfter(io.EOF, time.Second), "wrapped"), time.Second, true},
+		{WithRetryAfter(WithRetryAfter(io.EOF, time.Second), time.Minute), time.Minute, true},
+		{WithData(io.EOF, KeyRetryAfter, "soon"), 0, false},
+	}
+
+	for i, tt := range tests {
+		got, ok := RetryAfter(tt.err)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: RetryAfter(%v): got (%v, %v), want (%v, %v)", i+1, tt.err, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	if got := WithRetryAfter(nil, time.Second); got != nil {
+		t.Errorf("WithRetryAfter(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2020 00:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 31 Dec 2019 23:59:00 GMT", 0, true},
+		{"later", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("parseRetryAfter(%q): got (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+type transientError struct{ error }
+
+func (transientError) Permanent() bool { return false }
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{MarkPermanent(io.EOF), true},
+		{Wrap(MarkPermanent(io.EOF), "wrapped"), true},
+		{WithRetryAfter(MarkPermanent(io.EOF), time.Second), true},
+		{transientError{MarkPermanent(io.EOF)}, false},
+	}
+
+	for i, tt := range tests {
+		if got := IsPermanent(tt.err); got != tt.want {
+			t.Errorf("test %d: IsPermanent(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := MarkPermanent(nil); got != nil {
+		t.Errorf("MarkPermanent(nil): got %#v, expected nil", got)
+	}
+	if err := MarkPermanent(io.EOF); !Is(err, io.EOF) || err.Error() != "EOF" {
+		t.Errorf("MarkPermanent(io.EOF): got %v, want to wrap io.EOF", err)
+	}
+}
+
+type cleanError struct{ error }
+
+func (cleanError) SideEffects() bool { return false }
+
+func TestHasSideEffects(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{MarkSideEffects(io.EOF), true},
+		{Wrap(MarkSideEffects(io.EOF), "wrapped"), true},
+		{MarkPermanent(MarkSideEffects(io.EOF)), true},
+		{cleanError{MarkSideEffects(io.EOF)}, false},
+	}
+
+	for i, tt := range tests {
+		if got := HasSideEffects(tt.err); got != tt.want {
+			t.Errorf("test %d: HasSideEffects(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := MarkSideEffects(nil); got != nil {
+		t.Errorf("MarkSideEffects(nil): got %#v, expected nil", got)
+	}
+	if _, ok := NextDelay(MarkSideEffects(io.EOF), 1); ok {
+		t.Error("NextDelay: want no retry after side effects")
+	}
+}
+
+func TestNextDelay(t *testing.T) {
+	tests := []struct {
+		err     error
+		attempt int
+		want    time.Duration
+		wantOk  bool
+	}{
+		{nil, 1, 0, false},
+		{io.EOF, 0, 100 * time.Millisecond, true},
+		{io.EOF, 1, 100 * time.Millisecond, true},
+		{io.EOF, 3, 400 * time.Millisecond, true},
+		{io.EOF, 100, 30 * time.Second, true},
+		{WithKind(io.EOF, KindRateLimited), 2, 2 * time.Second, true},
+		{WithKind(io.EOF, KindUnavailable), 2, 200 * time.Millisecond, true},
+		{WithRetryAfter(WithKind(io.EOF, KindRateLimited), time.Minute), 5, time.Minute, true},
+		{MarkPermanent(WithRetryAfter(io.EOF, time.Second)), 1, 0, false},
+		{WithKind(io.EOF, KindNotFound), 1, 0, false},
+		{WithKind(io.EOF, KindPermission), 1, 0, false},
+	}
+
+	for i, tt := range tests {
+		got, ok := NextDelay(tt.err, tt.attempt)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: NextDelay(%v, %d): got (%v, %v), want (%v, %v)", i+1, tt.err, tt.attempt, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
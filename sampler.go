@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// maxSamplerBuckets bounds the number of fingerprints a Sampler tracks. Past
+// it, the Sampler forgets the one used least recently, along with its
+// dropped count.
+const maxSamplerBuckets = 10000
+
+// A Sampler decides, per Fingerprint, whether an error should be fully
+// reported (with stack and data) or only counted, protecting downstream error
+// trackers from storms of the same error. Each fingerprint has its own token
+// bucket holding up to Burst tokens and refilled at Rate tokens per second.
+// Errors whose messages embed variable data have a fingerprint per value
+// (see Fingerprint) and are not sampled together.
+// A Sampler is safe for concurrent use.
+type Sampler struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     list.List // of *bucket, most recently sampled first
+}
+
+type bucket struct {
+	fingerprint string
+	tokens      float64
+	last        time.Time // when tokens was last refilled
+	dropped     uint64
+}
+
+// NewSampler returns a Sampler allowing burst reports of a fingerprint at
+// once and rate reports per second after that.
+func NewSampler(rate float64, burst int) *Sampler {
+	return &Sampler{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*list.Element),
+	}
+}
+
+// Sample reports whether err should be fully reported. If not, the error is
+// counted against its fingerprint; see Dropped.
+// If err is nil, Sample returns false.
+func (s *Sampler) Sample(err error) bool {
+	if err == nil {
+		return false
+	}
+	fp := Fingerprint(err)
+	now := now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b *bucket
+	if e, ok := s.buckets[fp]; ok {
+		s.lru.MoveToFront(e)
+		b = e.Value.(*bucket)
+	} else {
+		if s.lru.Len() >= maxSamplerBuckets {
+			oldest := s.lru.Remove(s.lru.Back()).(*bucket)
+			delete(s.buckets, oldest.fingerprint)
+		}
+		b = &bucket{fingerprint: fp, tokens: s.burst, last: now}
+		s.buckets[fp] = s.lru.PushFront(b)
+	}
+	s.refill(b, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	b.dropped++
+	return false
+}
+
+// Dropped returns, per fingerprint, how many errors Sample has rejected since
+// the last call to Dropped, and resets those counts.
+func (s *Sampler) Dropped() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := make(map[string]uint64)
+	for fp, e := range s.buckets {
+		if b := e.Value.(*bucket); b.dropped > 0 {
+			dropped[fp] = b.dropped
+			b.dropped = 0
+		}
+	}
+	return dropped
+}
+
+// refill adds the tokens earned by b since it was last used.
+func (s *Sampler) refill(b *bucket, now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * s.rate
+	if b.tokens > s.burst {
+		b.tokens = s.burst
+	}
+	b.last = now
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(0, 2)
+	eof := Wrap(io.EOF, "reading")
+	other := New("other")
+
+	if s.Sample(nil) {
+		t.Errorf("Sample(nil): got true")
+	}
+
+	var got []bool
+	for i := 0; i < 4; i++ {
+		got = append(got, s.Sample(eof))
+	}
+	got = append(got, s.Sample(other))
+	want := []bool{true, true, false, false, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sample: got %v, want %v", got, want)
+	}
+
+	wantDropped := map[string]uint64{Fingerprint(eof): 2}
+	if d := s.Dropped(); !reflect.DeepEqual(d, wantDropped) {
+		t.Errorf("Dropped: got %v, want %v", d, wantDropped)
+	}
+	if d := s.Dropped(); len(d) != 0 {
+		t.Errorf("Dropped after reset: got %v, want empty", d)
+	}
+}
+
+func TestSamplerRefill(t *testing.T) {
+	s := NewSampler(1e9, 1)
+	for i := 0; i < 3; i++ {
+		if !s.Sample(io.EOF) {
+			t.Errorf("Sample %d: got false with a near-instant refill", i+1)
+		}
+	}
+}
+
+func TestSamplerCap(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	defer SetClock(SetClock(ClockFunc(func() time.Time {
+		at = at.Add(time.Millisecond)
+		return at
+	})))
+	fp := func(i int) error { return WithData(io.EOF, KeyFingerprint, strconv.Itoa(i)) }
+
+	s := NewSampler(0, 1)
+	for i := 0; i < maxSamplerBuckets+10; i++ {
+		s.Sample(fp(i))
+	}
+	if n := len(s.buckets); n != maxSamplerBuckets {
+		t.Errorf("buckets: got %d, want %d", n, maxSamplerBuckets)
+	}
+	if _, ok := s.buckets["0"]; ok {
+		t.Error("the least recently used fingerprint was kept")
+	}
+	if _, ok := s.buckets[strconv.Itoa(maxSamplerBuckets+9)]; !ok {
+		t.Error("the newest fingerprint was evicted")
+	}
+}
+
+func TestSamplerLRU(t *testing.T) {
+	fp := func(i int) error { return WithData(io.EOF, KeyFingerprint, strconv.Itoa(i)) }
+
+	s := NewSampler(0, 1)
+	for i := 0; i < maxSamplerBuckets; i++ {
+		s.Sample(fp(i))
+	}
+	s.Sample(fp(0))
+	s.Sample(fp(maxSamplerBuckets))
+	if _, ok := s.buckets["0"]; !ok {
+		t.Error("a recently sampled fingerprint was evicted")
+	}
+	if _, ok := s.buckets["1"]; ok {
+		t.Error("the least recently used fingerprint was kept")
+	}
+	if d := s.Dropped(); d["0"] != 1 {
+		t.Errorf("Dropped: got %v, want one drop for 0", d)
+	}
+}
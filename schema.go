@@ -0,0 +1,155 @@
+package errors
+
+import "encoding/json"
+
+// SnapshotVersion is the version of the JSON schema of Snapshot written by
+// this release of the package. It is incremented when the meaning of
+// existing fields changes; adding fields does not require it, since
+// decoders keep the fields they do not know.
+const SnapshotVersion = 1
+
+// snapshotFields and snapshotLayerFields have the fields of Snapshot and
+// SnapshotLayer without their JSON methods.
+type (
+	snapshotFields      Snapshot
+	snapshotLayerFields SnapshotLayer
+)
+
+// MarshalJSON encodes s with its schema version: s.Version if s was decoded
+// from a newer release, SnapshotVersion otherwise. The fields unknown to
+// this release that s was decoded with are written back unchanged.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	if s.Version < SnapshotVersion {
+		s.Version = SnapshotVersion
+	}
+	s.GlobalData = envelopeSnapshots(s.GlobalData)
+	return marshalWithExtra(snapshotFields(s), s.extra)
+}
+
+// UnmarshalJSON decodes a Snapshot of any schema version. Fields unknown
+// to this release are kept, and written back by MarshalJSON, so that
+// services relaying errors between newer releases do not lose them. A
+// Snapshot written before schema versions were introduced has Version 0.
+//
+// A Snapshot of a newer version than SnapshotVersion is not rejected: the
+// fields this release knows are decoded with their current meaning, which
+// is the best a receiver that cannot be upgraded can do, and Version is
+// kept, so that callers for whom that is not good enough can check for
+// s.Version > SnapshotVersion and refuse it.
+func (s *Snapshot) UnmarshalJSON(b []byte) error {
+	var f snapshotFields
+	extra, err := unmarshalWithExtra(b, &f, "version", "layers", "global_data")
+	if err != nil {
+		return err
+	}
+	*s = Snapshot(f)
+	s.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes l, along with the fields unknown to this release that
+// it was decoded with. Nested Snapshots among its data values are written
+// in a snapshotEnvelope.
+func (l SnapshotLayer) MarshalJSON() ([]byte, error) {
+	l.Data = envelopeSnapshots(l.Data)
+	return marshalWithExtra(snapshotLayerFields(l), l.extra)
+}
+
+// UnmarshalJSON decodes l, keeping the fields unknown to this release.
+func (l *SnapshotLayer) UnmarshalJSON(b []byte) error {
+	var f snapshotLayerFields
+	extra, err := unmarshalWithExtra(b, &f, "message", "data", "stack", "branches")
+	if err != nil {
+		return err
+	}
+	*l = SnapshotLayer(f)
+	l.extra = extra
+	return nil
+}
+
+// snapshotEnvelope is the only key of the JSON object that a nested
+// Snapshot is written in, so that decoders tell it apart from data values
+// that merely have a "layers" field.
+const snapshotEnvelope = "$snapshot"
+
+// envelopeSnapshots returns data with every Snapshot value wrapped in a
+// snapshotEnvelope, or data itself if it holds none.
+func envelopeSnapshots(data map[string]interface{}) map[string]interface{} {
+	var kv map[string]interface{}
+	for k, v := range data {
+		s, ok := v.(Snapshot)
+		if !ok {
+			continue
+		}
+		if kv == nil {
+			kv = make(map[string]interface{}, len(data))
+			for k, v := range data {
+				kv[k] = v
+			}
+		}
+		kv[k] = map[string]interface{}{snapshotEnvelope: s}
+	}
+	if kv == nil {
+		return data
+	}
+	return kv
+}
+
+// nestedSnapshot returns v as a Snapshot, whether it is one or was decoded
+// from a snapshotEnvelope, and whether it was. Other values are never
+// taken for Snapshots, whatever their fields.
+func nestedSnapshot(v interface{}) (Snapshot, bool) {
+	switch v := v.(type) {
+	case Snapshot:
+		return v, true
+	case map[string]interface{}:
+		inner, ok := v[snapshotEnvelope].(map[string]interface{})
+		if !ok || len(v) != 1 {
+			break
+		}
+		var s Snapshot
+		if b, err := json.Marshal(inner); err == nil && json.Unmarshal(b, &s) == nil {
+			return s, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// marshalWithExtra returns the JSON encoding of v, a struct, with the
+// fields of extra that v does not have added.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, raw := range extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = raw
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalWithExtra decodes b into v, a pointer to a struct whose JSON
+// fields are known, and returns the other fields of b, or nil if there are
+// none.
+func unmarshalWithExtra(b []byte, v interface{}, known ...string) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for _, k := range known {
+		delete(fields, k)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotVersion(t *testing.T) {
+	b, err := json.Marshal(NewSnapshot(Wrap(io.EOF, "reading")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), `{"version":1,"layers":`) {
+		t.Errorf("Marshal: got %s, want the schema version first", b)
+	}
+
+	var old Snapshot
+	if err := json.Unmarshal([]byte(`{"layers":[{"message":"EOF"}]}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	if old.Version != 0 || old.Err().Error() != "EOF" {
+		t.Errorf("Unmarshal of an unversioned Snapshot: got %+v", old)
+	}
+}
+
+func TestSnapshotUnknownFields(t *testing.T) {
+	in := `{"version":3,"layers":[{"message":"EOF","cause_id":"c-1","stack":[{"function":"main.main","file":"/src/main.go","line":7}]}],"trace":{"id":"t-1"}}`
+	var s Snapshot
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != 3 || len(s.Layers) != 1 || s.Layers[0].Message != "EOF" || len(s.Layers[0].Stack) != 1 {
+		t.Fatalf("Unmarshal: got %+v", s)
+	}
+
+	out, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	json.Unmarshal(out, &got)
+	json.Unmarshal([]byte(in), &want)
+	if g, w := mustMarshal(t, got), mustMarshal(t, want); g != w {
+		t.Errorf("round trip:\n got %s\nwant %s", g, w)
+	}
+
+	if err := json.Unmarshal([]byte(`{"layers":"none"}`), &s); err == nil {
+		t.Error("Unmarshal of malformed layers: want an error")
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSnapshotEnvelope(t *testing.T) {
+	page := map[string]interface{}{"layers": []interface{}{"base", "overlay"}}
+	err := WithData(New("rendering"), "page", page, "cause", New("tile missing"))
+	b, jerr := json.Marshal(NewSnapshot(err))
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	if !strings.Contains(string(b), `"cause":{"$snapshot":{`) {
+		t.Errorf("Marshal: want the nested Snapshot in an envelope, got %s", b)
+	}
+
+	var s Snapshot
+	if jerr := json.Unmarshal(b, &s); jerr != nil {
+		t.Fatal(jerr)
+	}
+	got := s.Err()
+	if v, _ := GetValue(got, "page"); !reflect.DeepEqual(v, page) {
+		t.Errorf("page: got %#v, want the map unchanged", v)
+	}
+	if v, _ := GetValue(got, "cause"); fmt.Sprint(v) != "tile missing" {
+		t.Errorf("cause: got %#v, want the nested error", v)
+	} else if _, ok := v.(error); !ok {
+		t.Errorf("cause: got %T, want an error", v)
+	}
+}
+
+func TestSnapshotNewerVersion(t *testing.T) {
+	in := []byte(`{"version":2,"layers":[{"message":"reading","data":{"kind":3}},{"message":"EOF","severity":"high"}],"origin":"lockd"}`)
+	s, err := DecodeSnapshot(in)
+	if err != nil {
+		t.Fatalf("DecodeSnapshot of a newer version: %v", err)
+	}
+	if s.Version != 2 || s.Version <= SnapshotVersion {
+		t.Errorf("Version: got %d, want 2, greater than SnapshotVersion", s.Version)
+	}
+	got := s.Err()
+	if got.Error() != "reading: EOF" || KindOf(got) != Kind(3) {
+		t.Errorf("Err: got %q of Kind %v, want the known fields decoded", got, KindOf(got))
+	}
+	out, _ := json.Marshal(s)
+	if !strings.Contains(string(out), `"version":2`) || !strings.Contains(string(out), `"severity":"high"`) || !strings.Contains(string(out), `"origin":"lockd"`) {
+		t.Errorf("Marshal: got %s, want the newer version and its fields kept", out)
+	}
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Seal returns an error with err's message that hides err from its callers:
+// Unwrap, Is, As, Walk, and GetValue do not see past it, so callers cannot
+// come to depend on internal causes. Data that callers may rely on, such as
+// a Kind, should be added over the sealed error:
+//
+//	return errors.WithKind(errors.Seal(err), errors.KindUnavailable)
+//
+// Formatting the sealed error with %+v still prints err with %+v, for
+// logging. A package that needs err itself back seals it with a SealKey
+// instead.
+// If err is nil, Seal returns nil.
+func Seal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return runHooks(&sealed{err, nil}, HookWrap)
+}
+
+// A SealKey seals errors that only its holder can unseal, giving the
+// package that sealed them privileged access to their internal causes.
+// The key is kept unexported by that package:
+//
+//	var sealKey = errors.NewSealKey()
+//
+//	func (s *Store) Get(id string) (*Lock, error) {
+//	        ...
+//	        return nil, errors.WithKind(sealKey.Seal(err), errors.KindUnavailable)
+//	}
+type SealKey struct {
+	_ byte // so that distinct keys have distinct addresses
+}
+
+// NewSealKey returns a new SealKey.
+func NewSealKey() *SealKey {
+	return new(SealKey)
+}
+
+// Seal is like the package function Seal, but the error it returns can be
+// unsealed with k.
+// If err is nil, Seal returns nil.
+func (k *SealKey) Seal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return runHooks(&sealed{err, k}, HookWrap)
+}
+
+// Unseal returns the error hidden by the shallowest error in err's chain
+// sealed with k, and whether there is one. Errors sealed with other keys,
+// or with the package function Seal, are not unsealed.
+func (k *SealKey) Unseal(err error) (error, bool) {
+	var inner error
+	Walk(err, func(e error) bool {
+		if s, ok := e.(*sealed); ok && s.key == k {
+			inner = s.err
+		}
+		return inner == nil
+	})
+	return inner, inner != nil
+}
+
+type sealed struct {
+	err error
+	key *SealKey
+}
+
+func (s *sealed) Error() string { return s.err.Error() }
+
+func (s *sealed) Format(st fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if st.Flag('#') {
+			io.WriteString(st, s.GoString())
+			return
+		}
+		if st.Flag('+') {
+			fmt.Fprintf(st, "%+v", s.err)
+			if isDeepest(s.err) {
+				formatGlobalData(st)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(st, s.Error())
+	case 'q':
+		fmt.Fprintf(st, "%q", s.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like s.
+func (s *sealed) GoString() string {
+	return fmt.Sprintf("errors.Seal(%#v)", s.err)
+}
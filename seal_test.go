@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSeal(t *testing.T) {
+	inner := WrapWithData(io.EOF, "reading row", "table", "locks")
+	err := WithKind(Seal(inner), KindUnavailable)
+
+	if err.Error() != "reading row: EOF" {
+		t.Errorf("Error: got %q", err.Error())
+	}
+	if Is(err, io.EOF) {
+		t.Error("Is: matched through the seal")
+	}
+	if _, ok := GetValue(err, "table"); ok {
+		t.Error("GetValue: found data through the seal")
+	}
+	if KindOf(err) != KindUnavailable {
+		t.Errorf("KindOf: got %v, want the kind added over the seal", KindOf(err))
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "ERROR DATA: map[table:locks]") {
+		t.Errorf("%%+v: got %q, want the sealed error's details", got)
+	}
+	if Seal(nil) != nil {
+		t.Error("Seal(nil): want nil")
+	}
+}
+
+func TestSealKey(t *testing.T) {
+	key, other := NewSealKey(), NewSealKey()
+	inner := WrapWithData(io.EOF, "reading row", "table", "locks")
+	err := Wrap(WithKind(key.Seal(inner), KindUnavailable), "loading lock")
+
+	if Is(err, io.EOF) {
+		t.Error("Is: matched through the seal")
+	}
+	if got, ok := key.Unseal(err); !ok || got != inner {
+		t.Errorf("Unseal: got %v, %v, want the sealed error", got, ok)
+	}
+	if _, ok := other.Unseal(err); ok {
+		t.Error("Unseal with another key: got true")
+	}
+	if _, ok := key.Unseal(Seal(inner)); ok {
+		t.Error("Unseal of an error sealed without a key: got true")
+	}
+	if _, ok := key.Unseal(inner); ok {
+		t.Error("Unseal of an unsealed error: got true")
+	}
+	if key.Seal(nil) != nil {
+		t.Error("Seal(nil): want nil")
+	}
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WithSecondary annotates err with related errors that did not cause it,
+// such as the rollback that failed while handling err. Unlike wrapping, the
+// related errors do not change err's message and are not searched by Is,
+// As, Walk, or GetValue; they are printed in a section of their own by %+v
+// and returned by Secondaries.
+// If err is nil, WithSecondary returns nil. Nil related errors are ignored,
+// and err is returned unchanged if there are no others.
+func WithSecondary(err error, related ...error) error {
+	if err == nil {
+		return nil
+	}
+	var errs []error
+	for _, r := range related {
+		if r != nil {
+			errs = append(errs, r)
+		}
+	}
+	if len(errs) == 0 {
+		return err
+	}
+	return runHooks(&withSecondary{err, errs}, HookWrap)
+}
+
+// Secondaries returns the errors attached with WithSecondary anywhere in
+// err's chain, shallowest first.
+func Secondaries(err error) []error {
+	var errs []error
+	Walk(err, func(e error) bool {
+		if w, ok := e.(*withSecondary); ok {
+			errs = append(errs, w.related...)
+		}
+		return true
+	})
+	return errs
+}
+
+type withSecondary struct {
+	error
+	related []error
+}
+
+func (w *withSecondary) Unwrap() error { return w.error }
+
+func (w *withSecondary) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, w) {
+				return
+			}
+			fmt.Fprintf(s, "%+v", w.Unwrap())
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
+			c := formatConfig()
+			formatRelated(s, c.SecondaryLabel, w.related, c)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like w.
+func (w *withSecondary) GoString() string {
+	var b strings.Builder
+	for _, r := range w.related {
+		fmt.Fprintf(&b, ", %#v", r)
+	}
+	return fmt.Sprintf("errors.WithSecondary(%#v%s)", w.error, b.String())
+}
+
+// formatRelated writes a section for each of errs: label followed by the
+// first line of its %+v output, then the remaining lines prefixed with the
+// NestedPrefix.
+func formatRelated(w io.Writer, label string, errs []error, c FormatConfig) {
+	for _, err := range errs {
+		for i, line := range strings.Split(fmt.Sprintf("%+v", err), c.Separator) {
+			io.WriteString(w, c.Separator)
+			if i == 0 {
+				io.WriteString(w, label)
+			} else {
+				io.WriteString(w, c.NestedPrefix)
+			}
+			io.WriteString(w, line)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestWithSecondary(t *testing.T) {
+	defer SetStackProvider(SetStackProvider(FixedStack(FakeFrame("main.main", "/src/main.go", 7))))
+	rollback := New("rollback failed")
+	err := Wrap(WithSecondary(io.EOF, nil, rollback), "committing")
+
+	if got := err.Error(); got != "committing: EOF" {
+		t.Errorf("Error: got %q, want %q", got, "committing: EOF")
+	}
+	if Is(err, rollback) {
+		t.Error("Is: matched a secondary error")
+	}
+	if got := Secondaries(err); !reflect.DeepEqual(got, []error{rollback}) {
+		t.Errorf("Secondaries: got %v", got)
+	}
+	want := "EOF\n" +
+		"SECONDARY ERROR: rollback failed\n" +
+		"> main.main\n" +
+		"> \t/src/main.go:7\n" +
+		"committing\n" +
+		"main.main\n\t/src/main.go:7"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%#v", WithSecondary(io.EOF, rollback)), `errors.WithSecondary(&errors.errorString{s:"EOF"}, errors.New("rollback failed"))`; got != want {
+		t.Errorf("%%#v: got %q, want %q", got, want)
+	}
+
+	if WithSecondary(nil, rollback) != nil {
+		t.Error("WithSecondary(nil): want nil")
+	}
+	if WithSecondary(io.EOF, nil) != io.EOF {
+		t.Error("WithSecondary without related errors: want err unchanged")
+	}
+}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatStack returns the stack trace sections of err's chain as %+v prints
+// them, root cause first, without the messages and data around them. It is
+// meant for log layers that record the stack in a field of its own.
+// If err is nil or records no stack trace, FormatStack returns "".
+func FormatStack(err error) string {
+	layers := Layers(err)
+	var b strings.Builder
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, f := range layers[i].Stack {
+			if b.Len() > 0 {
+				b.WriteString(formatConfig().Separator)
+			}
+			fmt.Fprintf(&b, "%+v", f)
+		}
+	}
+	return b.String()
+}
+
+// FormatData returns the data and argument sections of err's chain as %+v
+// prints them, labels included, root cause first and the global data set
+// with SetGlobalData last. It is meant for log layers that record the data
+// in a field of its own.
+// If err is nil, FormatData returns "".
+func FormatData(err error) string {
+	if err == nil {
+		return ""
+	}
+	c := formatConfig()
+	var sections []string
+	layers := Layers(err)
+	for i := len(layers) - 1; i >= 0; i-- {
+		switch e := layers[i].Err.(type) {
+		case *withData:
+			if len(layers[i].Data) > 0 {
+				sections = append(sections, dataSection(layers[i].Data, c))
+			}
+		case *withArgs:
+			sections = append(sections, argsSection(e.args, c))
+		}
+	}
+	if g := GlobalData(); len(g) > 0 {
+		sections = append(sections, c.GlobalDataLabel+formatDataMap(g))
+	}
+	return strings.Join(sections, c.Separator)
+}
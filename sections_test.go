@@ -0,0 +1,25 @@
+package errors
+
+import "testing"
+
+func TestFormatSections(t *testing.T) {
+	get := FakeFrame("store.Get", "/src/store/store.go", 42)
+	main := FakeFrame("main.main", "/src/main.go", 7)
+	prev := SetStackProvider(FixedStack(get))
+	inner := WithData(New("boom"), "lock_id", "L-1")
+	SetStackProvider(FixedStack(main))
+	err := WrapWithData(inner, "unlocking", "attempt", 2)
+	SetStackProvider(prev)
+	defer SetGlobalData()
+	SetGlobalData("service", "locks")
+
+	if got, want := FormatStack(err), "store.Get\n\t/src/store/store.go:42\nmain.main\n\t/src/main.go:7"; got != want {
+		t.Errorf("FormatStack: got %q, want %q", got, want)
+	}
+	if got, want := FormatData(err), "ERROR DATA: map[lock_id:L-1]\nERROR DATA: map[attempt:2]\nGLOBAL DATA: map[service:locks]"; got != want {
+		t.Errorf("FormatData: got %q, want %q", got, want)
+	}
+	if FormatStack(nil) != "" || FormatData(nil) != "" {
+		t.Errorf("FormatStack, FormatData of nil: want empty")
+	}
+}
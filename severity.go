@@ -0,0 +1,76 @@
+package errors
+
+// Severity ranks how urgently an error needs attention.
+type Severity uint8
+
+// The Severities understood by this package. SeverityUnset is the zero value
+// and means the severity should be derived from the error's Kind.
+const (
+	SeverityUnset Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityUnset:    "unset",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower-case name of the Severity.
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return severityNames[SeverityUnset]
+}
+
+// ParseSeverity returns the Severity whose String is name, and whether there
+// is one.
+func ParseSeverity(name string) (Severity, bool) {
+	for s, n := range severityNames {
+		if n == name {
+			return Severity(s), true
+		}
+	}
+	return SeverityUnset, false
+}
+
+// KeySeverity is the data key under which WithSeverity records the Severity
+// of an error.
+const KeySeverity = "severity"
+
+// WithSeverity annotates err with the Severity s.
+// If err is nil, WithSeverity returns nil.
+func WithSeverity(err error, s Severity) error {
+	return WithData(err, KeySeverity, s)
+}
+
+// SeverityOf returns the shallowest Severity recorded in err's chain. If none
+// was recorded it is derived from the error's Kind: failures caused by the
+// caller (invalid input, missing resources, conflicts, authentication,
+// permission, rate limiting, cancellation) are SeverityWarning, and all other
+// failures are SeverityError.
+// If err is nil, SeverityOf returns SeverityUnset.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return SeverityUnset
+	}
+	if v, ok := GetValue(err, KeySeverity); ok {
+		if s, ok := v.(Severity); ok && s != SeverityUnset {
+			return s
+		}
+	}
+	switch KindOf(err) {
+	case KindInvalid, KindNotFound, KindConflict, KindUnauthenticated,
+		KindPermission, KindRateLimited, KindCanceled:
+		return SeverityWarning
+	}
+	return SeverityError
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Severity
+	}{
+		{nil, SeverityUnset},
+		{io.EOF, SeverityError},
+		{WithKind(io.EOF, KindNotFound), SeverityWarning},
+		{WithKind(io.EOF, KindUnavailable), SeverityError},
+		{WithSeverity(WithKind(io.EOF, KindNotFound), SeverityCritical), SeverityCritical},
+		{Wrap(WithSeverity(io.EOF, SeverityInfo), "wrapped"), SeverityInfo},
+		{WithSeverity(io.EOF, SeverityUnset), SeverityError},
+	}
+
+	for i, tt := range tests {
+		if got := SeverityOf(tt.err); got != tt.want {
+			t.Errorf("test %d: SeverityOf(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithSeverity(nil, SeverityError); got != nil {
+		t.Errorf("WithSeverity(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	tests := []struct {
+		s    Severity
+		want string
+	}{
+		{SeverityUnset, "unset"},
+		{SeverityWarning, "warning"},
+		{SeverityCritical, "critical"},
+		{Severity(42), "unset"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("Severity(%d).String(): got %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
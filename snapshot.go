@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// A Snapshot is a plain, serializable record of an error chain: what each
+// error contributed to it, without the error values themselves. Errors
+// recorded as data values are themselves recorded as nested Snapshots.
+type Snapshot struct {
+	// Version is the schema version the Snapshot was decoded with, or 0 if
+	// it was not decoded from JSON; see SnapshotVersion. It may be greater
+	// than SnapshotVersion; see UnmarshalJSON.
+	Version int `json:"version"`
+	// Layers describes the chain, starting with the outermost error and
+	// ending with the root cause.
+	Layers []SnapshotLayer `json:"layers"`
+	// GlobalData holds the data set with SetGlobalData.
+	GlobalData map[string]interface{} `json:"global_data,omitempty"`
+
+	// extra holds the JSON fields unknown to this release.
+	extra map[string]json.RawMessage
+}
+
+// A SnapshotLayer describes one step of a chain as it is printed with %+v:
+// a message, followed by the data and the stack trace recorded around it.
+// Errors that only annotate the error they wrap are folded into the layer
+// of that error, so that New and Wrap each produce a single layer. The
+// errors wrapped by a multi-error, such as one built with Join, are
+// recorded as nested Snapshots in the Branches of the root layer.
+type SnapshotLayer struct {
+	Message  string                 `json:"message,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Stack    []SnapshotFrame        `json:"stack,omitempty"`
+	Branches []Snapshot             `json:"branches,omitempty"`
+
+	// extra holds the JSON fields unknown to this release.
+	extra map[string]json.RawMessage
+}
+
+// A SnapshotFrame is a resolved Frame.
+type SnapshotFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// NewSnapshot returns a Snapshot of err's chain.
+// If err is nil, NewSnapshot returns an empty Snapshot.
+func NewSnapshot(err error) Snapshot {
+	layers := Layers(err)
+	var b snapshotBuilder
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		if l.Message != "" || len(l.Branches) > 0 {
+			ml := b.layer(snapshotMessage)
+			ml.Message = l.Message
+			for _, e := range l.Branches {
+				ml.Branches = append(ml.Branches, NewSnapshot(e))
+			}
+		}
+		if len(l.Data) > 0 {
+			b.layer(snapshotData).Data = nestSnapshots(l.Data)
+		}
+		if len(l.Stack) > 0 {
+			frames := make([]SnapshotFrame, len(l.Stack))
+			for i, f := range l.Stack {
+				frames[i].Function, frames[i].File, frames[i].Line = f.Location()
+			}
+			b.layer(snapshotStack).Stack = frames
+		}
+	}
+	s := b.snapshot()
+	if g := GlobalData(); err != nil && len(g) > 0 {
+		s.GlobalData = g
+	}
+	return s
+}
+
+// Err returns an error rebuilt from s, for a process that received s from
+// another one. The error has the messages, data, and stack traces of the
+// original chain, including errors recorded as data values and the
+// branches of multi-errors, so Error, GetValue, and %+v give the same
+// results, but none of its types: Is and As only match errors of this
+// package. Values of the package's standard keys
+// that were decoded from JSON, such as a Kind that became a float64, are
+// converted back to their types.
+// If s has no layers, Err returns nil.
+func (s Snapshot) Err() error {
+	var err error
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		l := s.Layers[i]
+		if err == nil {
+			if errs := branchErrs(l.Branches); len(errs) > 0 {
+				err = &multiError{errs, l.Message}
+				if data := restoreData(l.Data); len(data) > 0 {
+					err = &withData{err, data}
+				}
+				if len(l.Stack) > 0 {
+					err = &withStack{err, stackOf(l.Stack)}
+				}
+				continue
+			}
+			err = &fundamental{msg: l.Message, stack: stackOf(l.Stack)}
+			if data := restoreData(l.Data); len(data) > 0 {
+				err = &withData{err, data}
+			}
+			continue
+		}
+		if l.Message != "" {
+			err = &withMessage{error: err, msg: l.Message}
+		}
+		if data := restoreData(l.Data); len(data) > 0 {
+			err = &withData{err, data}
+		}
+		if len(l.Stack) > 0 {
+			err = &withStack{err, stackOf(l.Stack)}
+		}
+	}
+	return err
+}
+
+// branchErrs returns the errors rebuilt from the non-empty branches.
+func branchErrs(branches []Snapshot) []error {
+	var errs []error
+	for _, b := range branches {
+		if err := b.Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// nestSnapshots returns data with every error value replaced by its
+// Snapshot, so that serializing the Snapshot expands those errors too.
+func nestSnapshots(data map[string]interface{}) map[string]interface{} {
+	kv := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if err, ok := v.(error); ok {
+			v = NewSnapshot(err)
+		}
+		kv[k] = v
+	}
+	return kv
+}
+
+// stackOf returns a stack of FakeFrames standing for frames.
+func stackOf(frames []SnapshotFrame) *stack {
+	s := make(stack, len(frames))
+	for i, f := range frames {
+		s[i] = uintptr(FakeFrame(f.Function, f.File, f.Line))
+	}
+	return &s
+}
+
+// restoreData returns a copy of data with the values of standard keys
+// converted back to their types after a round trip through JSON.
+func restoreData(data map[string]interface{}) map[string]interface{} {
+	kv := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		kv[k] = restoreValue(k, v)
+	}
+	return kv
+}
+
+func restoreValue(key string, v interface{}) interface{} {
+	if s, ok := nestedSnapshot(v); ok {
+		return s.Err()
+	}
+	switch v := v.(type) {
+	case float64:
+		switch key {
+		case KeyKind:
+			return Kind(v)
+		case KeySeverity:
+			return Severity(v)
+		case KeyStatusCode, KeyExitCode, KeyAttempt, KeyQueryParams:
+			return int(v)
+		case KeyRetryAfter, KeyElapsed, KeyDeadlineRemaining, KeyDelay:
+			return time.Duration(v)
+		}
+	case string:
+		if key == KeyTimestamp || key == KeyDeadline {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		}
+	}
+	return v
+}
+
+// The parts of a SnapshotLayer, in the order %+v prints them.
+const (
+	snapshotMessage = iota + 1
+	snapshotData
+	snapshotStack
+)
+
+// snapshotBuilder assembles the layers of a Snapshot from the parts of a
+// chain, root cause first.
+type snapshotBuilder struct {
+	layers []SnapshotLayer
+	part   int
+}
+
+// layer returns the layer that the given part belongs to: the current layer
+// if it has no such part yet and nothing printed after it, and a new layer
+// otherwise.
+func (b *snapshotBuilder) layer(part int) *SnapshotLayer {
+	if len(b.layers) == 0 || part <= b.part {
+		b.layers = append(b.layers, SnapshotLayer{})
+	}
+	b.part = part
+	return &b.layers[len(b.layers)-1]
+}
+
+// snapshot returns the Snapshot built so far, outermost layer first.
+func (b *snapshotBuilder) snapshot() Snapshot {
+	layers := make([]SnapshotLayer, len(b.layers))
+	for i, l := range b.layers {
+		layers[len(layers)-1-i] = l
+	}
+	return Snapshot{Layers: layers}
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotErr(t *testing.T) {
+	if err := (Snapshot{}).Err(); err != nil {
+		t.Errorf("Err: got %v, want nil", err)
+	}
+
+	get := FakeFrame("github.com/acme/store.Get", "/src/store/store.go", 42)
+	handle := FakeFrame("github.com/acme/api.handle", "/src/api/api.go", 17)
+	prev := SetStackProvider(FixedStack(get, handle))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := WithKind(New("lock offline"), KindUnavailable)
+	err = WrapWithData(err, "reading state", "lock_id", "7", KeyTimestamp, at)
+	err = WithRetryAfter(WithHTTPStatus(Wrap(WithMessage(err, "calling lockd"), "handling request"), 502), 3*time.Second)
+	SetStackProvider(prev)
+
+	buf, jerr := json.Marshal(NewSnapshot(err))
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var snap Snapshot
+	if jerr := json.Unmarshal(buf, &snap); jerr != nil {
+		t.Fatal(jerr)
+	}
+	got := snap.Err()
+
+	if got.Error() != err.Error() {
+		t.Errorf("Error: got %q, want %q", got.Error(), err.Error())
+	}
+	if g, w := fmt.Sprintf("%+v", got), fmt.Sprintf("%+v", err); g != w {
+		t.Errorf("%%+v: got\n%s\nwant\n%s", g, w)
+	}
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"KindOf", KindOf(got), KindUnavailable},
+		{"HTTPStatus", HTTPStatus(got), 502},
+		{"lock_id", GetAllData(got)["lock_id"], "7"},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if d, ok := RetryAfter(got); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter: got %v, %v, want 3s", d, ok)
+	}
+	if ts, ok := Timestamp(got); !ok || !ts.Equal(at) {
+		t.Errorf("Timestamp: got %v, %v, want %v", ts, ok, at)
+	}
+	if Is(got, io.EOF) {
+		t.Error("Is: rebuilt error matches an unrelated error")
+	}
+}
+
+func TestSnapshotJoin(t *testing.T) {
+	err := Wrap(Join(WithData(New("a"), "lock_id", 7), WithCode(New("b"), "bad")), "outer")
+	b, eerr := (&Codec{}).Encode(NewSnapshot(err))
+	if eerr != nil {
+		t.Fatalf("Encode: %v", eerr)
+	}
+	snap, derr := (&Codec{}).Decode(b)
+	if derr != nil {
+		t.Fatalf("Decode: %v", derr)
+	}
+	got := snap.Err()
+	if got.Error() != err.Error() {
+		t.Errorf("Error: got %q, want %q", got.Error(), err.Error())
+	}
+	if v, _ := GetValue(got, "lock_id"); v != float64(7) {
+		t.Errorf("GetValue(lock_id): got %#v, want 7", v)
+	}
+	if code := Code(got); code != "bad" {
+		t.Errorf("Code: got %q, want %q", code, "bad")
+	}
+	if n := len(Leaves(got)); n != 2 {
+		t.Errorf("Leaves: got %d errors, want 2", n)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", got), "TestSnapshotJoin") {
+		t.Errorf("%%+v: the stack traces of the branches are missing:\n%+v", got)
+	}
+}
@@ -0,0 +1,197 @@
+package errors
+
+import "unicode"
+
+// The data keys under which WrapQuery describes a failed SQL statement.
+const (
+	// KeyQuery holds the statement as normalized by NormalizeQuery.
+	KeyQuery = "sql_query"
+	// KeyQueryParams holds the number of parameters passed with it.
+	KeyQueryParams = "sql_params"
+)
+
+// WrapQuery returns an error annotating err with a stack trace at the point
+// WrapQuery is called, the message "sql query", and the statement that
+// failed: query as normalized by NormalizeQuery under KeyQuery, and the
+// number of args under KeyQueryParams. The values of args, and the literals
+// written in query, are never recorded, as they may hold personal data or
+// secrets; a query whose literals cannot be told apart is not recorded at
+// all. Timer.WrapQuery also records how long the statement ran.
+// If err is nil, WrapQuery returns nil.
+func WrapQuery(err error, query string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	err = &withMessage{
+		error: err,
+		msg:   "sql query",
+	}
+	err = attachData(err, queryData(query, args))
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// WrapQuery is like the package function WrapQuery, but also records the
+// time elapsed since t was started under KeyElapsed.
+// If err is nil, WrapQuery returns nil.
+func (t Timer) WrapQuery(err error, query string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	err = &withMessage{
+		error: err,
+		msg:   "sql query",
+	}
+	err = attachData(err, append(queryData(query, args), KeyElapsed, t.Elapsed()))
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// queryData returns the key/value pairs recorded by WrapQuery.
+func queryData(query string, args []interface{}) []interface{} {
+	keyVals := []interface{}{KeyQueryParams, len(args)}
+	if q := NormalizeQuery(query); q != "" {
+		keyVals = append(keyVals, KeyQuery, q)
+	}
+	return keyVals
+}
+
+// NormalizeQuery returns query with its string and numeric literals replaced
+// by "?", its comments removed, and runs of white space collapsed to a single
+// space, so that statements differing only in their values read the same:
+//
+//	NormalizeQuery("SELECT * FROM locks\n WHERE id = 42 AND owner = 'ann'")
+//	// "SELECT * FROM locks WHERE id = ? AND owner = ?"
+//
+// String literals may be quoted with ', with prefixes such as E'...', or
+// with the dollar quotes $$...$$ and $tag$...$tag$ of PostgreSQL. Text
+// quoted with ", a string literal in MySQL but an identifier elsewhere, is
+// replaced too. Placeholders such as ?, $1, :name, and @p1, and identifiers
+// quoted with `, are kept.
+//
+// Whether a backslash escapes the quote that follows it depends on the
+// database and its settings. If that decides where a literal ends, the
+// query cannot be normalized safely, and NormalizeQuery returns "".
+func NormalizeQuery(query string) string {
+	var out []rune
+	space := false
+	r := []rune(query)
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			space = true
+			continue
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+			space = true
+			continue
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			for i += 2; i < len(r) && !(r[i] == '*' && i+1 < len(r) && r[i+1] == '/'); i++ {
+			}
+			i++
+			space = true
+			continue
+		}
+		if space && len(out) > 0 {
+			out = append(out, ' ')
+		}
+		space = false
+		switch {
+		case c == '\'' || c == '"':
+			end := quoteEnd(r, i, false)
+			if c == '\'' && stringPrefix(r, i) {
+				// E'...' and the like: drop the prefix along with the literal.
+				if p := out[len(out)-1]; p == 'E' || p == 'e' {
+					end = quoteEnd(r, i, true)
+				} else if quoteEnd(r, i, true) != end {
+					return ""
+				}
+				out = out[:len(out)-1]
+			} else if quoteEnd(r, i, true) != end {
+				return ""
+			}
+			i = end
+			out = append(out, '?')
+		case c == '`':
+			start := i
+			for i++; i < len(r) && r[i] != c; i++ {
+			}
+			if i >= len(r) {
+				i = len(r) - 1
+			}
+			out = append(out, r[start:i+1]...)
+		case c == '$' && (i == 0 || !isIdentRune(r[i-1])) && dollarTag(r, i) > 0:
+			n := dollarTag(r, i)
+			tag := string(r[i : i+n])
+			end := len(r)
+			for j := i + n; j+n <= len(r); j++ {
+				if string(r[j:j+n]) == tag {
+					end = j + n - 1
+					break
+				}
+			}
+			i = end
+			out = append(out, '?')
+		case unicode.IsDigit(c) && (i == 0 || !isIdentRune(r[i-1])):
+			for i+1 < len(r) && (isIdentRune(r[i+1]) || r[i+1] == '.') {
+				i++
+			}
+			out = append(out, '?')
+		default:
+			out = append(out, c)
+		}
+	}
+	return string(out)
+}
+
+// quoteEnd returns the index of the quote closing the literal opened by the
+// quote at r[i], or len(r) if it is not closed. A quote written twice stands
+// for itself, and so does one following a backslash if backslash is true.
+func quoteEnd(r []rune, i int, backslash bool) int {
+	q := r[i]
+	for j := i + 1; j < len(r); j++ {
+		switch {
+		case backslash && r[j] == '\\':
+			j++
+		case r[j] == q:
+			if j+1 < len(r) && r[j+1] == q {
+				j++
+				continue
+			}
+			return j
+		}
+	}
+	return len(r)
+}
+
+// stringPrefix reports whether the quote at r[i] follows a single letter
+// prefixing a string literal, such as the E of E'...' or the N of N'...'.
+func stringPrefix(r []rune, i int) bool {
+	return i > 0 && unicode.IsLetter(r[i-1]) && (i == 1 || !isIdentRune(r[i-2]))
+}
+
+// dollarTag returns the length of the dollar quote, such as $$ or $body$,
+// starting at r[i], or 0 if there is none.
+func dollarTag(r []rune, i int) int {
+	j := i + 1
+	for j < len(r) && (r[j] == '_' || unicode.IsLetter(r[j]) || j > i+1 && unicode.IsDigit(r[j])) {
+		j++
+	}
+	if j < len(r) && r[j] == '$' {
+		return j - i + 1
+	}
+	return 0
+}
+
+// isIdentRune reports whether c may continue an SQL identifier or
+// placeholder, so that a digit following it is not a numeric literal.
+func isIdentRune(c rune) bool {
+	return c == '_' || c == '$' || c == '@' || c == ':' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
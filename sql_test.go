@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"SELECT * FROM locks\n\tWHERE id = 42 AND owner = 'ann'", "SELECT * FROM locks WHERE id = ? AND owner = ?"},
+		{"UPDATE locks SET note = 'it''s -- mine' WHERE id = $1", "UPDATE locks SET note = ? WHERE id = $1"},
+		{"SELECT t1.id FROM t1 -- the first table\nLIMIT 10 OFFSET 2.5", "SELECT t1.id FROM t1 LIMIT ? OFFSET ?"},
+		{"SELECT /* hint */ \"col 1\" FROM x WHERE y IN (1, 2, -3) AND z = :name", "SELECT ? FROM x WHERE y IN (?, ?, -?) AND z = :name"},
+		{"INSERT INTO logs VALUES (@p1, 0x1F, 'unterminated", "INSERT INTO logs VALUES (@p1, ?, ?"},
+		{"SELECT * FROM users WHERE name = \"ann\" AND `key` = 'a\\\\b'", "SELECT * FROM users WHERE name = ? AND `key` = ?"},
+		{"SELECT E'it\\'s secret', N'ann' FROM x", "SELECT ?, ? FROM x"},
+		{"SELECT $$it's secret$$, $body$ a $$ b $body$, $1 FROM x", "SELECT ?, ?, $1 FROM x"},
+		{"SELECT $$unterminated", "SELECT ?"},
+		{"UPDATE notes SET body = 'it\\'s secret' WHERE id = 1", ""},
+		{"SELECT 'C:\\', 'x'", ""},
+		{"SELECT \"a\\\"b\" FROM x", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeQuery(tt.query); got != tt.want {
+			t.Errorf("NormalizeQuery(%q):\n got %q\nwant %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestWrapQuery(t *testing.T) {
+	err := WrapQuery(io.EOF, "SELECT * FROM users WHERE email = 'ann@example.com' AND id = ?", 7)
+	if got := err.Error(); got != "sql query: EOF" {
+		t.Errorf("Error: got %q, want %q", got, "sql query: EOF")
+	}
+	if got, _ := GetValue(err, KeyQuery); got != "SELECT * FROM users WHERE email = ? AND id = ?" {
+		t.Errorf("%s: got %q", KeyQuery, got)
+	}
+	if got, _ := GetValue(err, KeyQueryParams); got != 1 {
+		t.Errorf("%s: got %v, want 1", KeyQueryParams, got)
+	}
+	for _, secret := range []string{"ann@example.com", "7"} {
+		if data := FormatData(err); strings.Contains(data, secret) {
+			t.Errorf("FormatData: %q holds %q", data, secret)
+		}
+	}
+
+	timer := StartTimer()
+	time.Sleep(time.Millisecond)
+	err = timer.WrapQuery(io.EOF, "DELETE FROM locks")
+	if d, _ := GetValue(err, KeyElapsed); d.(time.Duration) < time.Millisecond {
+		t.Errorf("Timer.WrapQuery: %s: got %v", KeyElapsed, d)
+	}
+
+	err = WrapQuery(io.EOF, "UPDATE notes SET body = 'it\\'s secret'")
+	if _, ok := GetValue(err, KeyQuery); ok {
+		t.Errorf("WrapQuery of an ambiguous query: got %s recorded", KeyQuery)
+	}
+
+	if WrapQuery(nil, "SELECT 1") != nil {
+		t.Error("WrapQuery(nil): want nil")
+	}
+	if timer.WrapQuery(nil, "SELECT 1") != nil {
+		t.Error("Timer.WrapQuery(nil): want nil")
+	}
+}
@@ -1,12 +1,15 @@
 package errors
 
 import (
+	"container/list"
 	"fmt"
 	"io"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Frame represents a program counter inside a stack frame.
@@ -21,6 +24,9 @@ func (f Frame) pc() uintptr { return uintptr(f) - 1 }
 // file returns the full path to the file that contains the
 // function for this Frame's pc.
 func (f Frame) file() string {
+	if ff, ok := f.fake(); ok {
+		return ff.file
+	}
 	fn := runtime.FuncForPC(f.pc())
 	if fn == nil {
 		return "unknown"
@@ -32,6 +38,9 @@ func (f Frame) file() string {
 // line returns the line number of source code of the
 // function for this Frame's pc.
 func (f Frame) line() int {
+	if ff, ok := f.fake(); ok {
+		return ff.line
+	}
 	fn := runtime.FuncForPC(f.pc())
 	if fn == nil {
 		return 0
@@ -42,6 +51,9 @@ func (f Frame) line() int {
 
 // name returns the name of this function, if known.
 func (f Frame) name() string {
+	if ff, ok := f.fake(); ok {
+		return ff.function
+	}
 	fn := runtime.FuncForPC(f.pc())
 	if fn == nil {
 		return "unknown"
@@ -59,15 +71,17 @@ func (f Frame) name() string {
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
 //    %+s   function name and path of source file relative to the compile time
-//          GOPATH separated by \n\t (<funcname>\n\t<path>)
+//          GOPATH separated by \n\t (<funcname>\n\t<path>), or by the
+//          Separator and FrameIndent set with SetFormatConfig
 //    %+v   equivalent to %+s:%d
 func (f Frame) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 's':
 		switch {
 		case s.Flag('+'):
+			c := formatConfig()
 			io.WriteString(s, f.name())
-			io.WriteString(s, "\n\t")
+			io.WriteString(s, c.Separator+c.FrameIndent)
 			io.WriteString(s, f.file())
 		default:
 			io.WriteString(s, path.Base(f.file()))
@@ -109,8 +123,9 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 	case 'v':
 		switch {
 		case s.Flag('+'):
+			sep := formatConfig().Separator
 			for _, f := range st {
-				io.WriteString(s, "\n")
+				io.WriteString(s, sep)
 				f.Format(s, verb)
 			}
 		case s.Flag('#'):
@@ -144,9 +159,10 @@ func (s *stack) Format(st fmt.State, verb rune) {
 	case 'v':
 		switch {
 		case st.Flag('+'):
+			sep := formatConfig().Separator
 			for _, pc := range *s {
 				f := Frame(pc)
-				fmt.Fprintf(st, "\n%+v", f)
+				fmt.Fprintf(st, "%s%+v", sep, f)
 			}
 		}
 	}
@@ -161,6 +177,17 @@ func (s *stack) StackTrace() StackTrace {
 }
 
 func callers() *stack {
+	if atomic.LoadInt32(&skipStacks) != 0 {
+		return &stack{}
+	}
+	if p, _ := stackProvider.Load().(StackProvider); p != nil {
+		st := p()
+		s := make(stack, len(st))
+		for i, f := range st {
+			s[i] = uintptr(f)
+		}
+		return &s
+	}
 	const depth = 32
 	var pcs [depth]uintptr
 	n := runtime.Callers(3, pcs[:])
@@ -175,3 +202,138 @@ func funcname(name string) string {
 	i = strings.Index(name, ".")
 	return name[i+1:]
 }
+
+// skipStacks is non-zero when newly constructed errors record no stack trace.
+var skipStacks int32
+
+// CaptureStacks sets whether newly constructed errors record a stack trace.
+// It is on by default; turning it off trades diagnostics for speed on hot
+// paths where errors are expected.
+func CaptureStacks(enabled bool) {
+	var v int32
+	if !enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&skipStacks, v)
+}
+
+// StackCaptureEnabled reports whether newly constructed errors record a stack
+// trace; see CaptureStacks.
+func StackCaptureEnabled() bool {
+	return atomic.LoadInt32(&skipStacks) == 0
+}
+
+// A StackProvider returns the stack trace recorded by a newly constructed
+// error in place of the real call stack.
+type StackProvider func() StackTrace
+
+// stackProvider holds the StackProvider set with SetStackProvider.
+var stackProvider atomic.Value
+
+// SetStackProvider makes every error constructed afterwards record the stack
+// trace returned by p instead of the real call stack, and returns the
+// previous provider. Setting a nil provider restores the real call stack.
+//
+// SetStackProvider is intended for tests that compare formatted errors with
+// golden files; combine it with FakeFrame for fixed, readable frames:
+//
+//     defer errors.SetStackProvider(errors.SetStackProvider(errors.FixedStack(
+//             errors.FakeFrame("store.Get", "store/store.go", 42),
+//             errors.FakeFrame("main.main", "main.go", 7),
+//     )))
+//     got := fmt.Sprintf("%+v", errors.New("boom"))
+func SetStackProvider(p StackProvider) (previous StackProvider) {
+	previous, _ = stackProvider.Load().(StackProvider)
+	stackProvider.Store(p)
+	return previous
+}
+
+// FixedStack returns a StackProvider that always returns frames.
+func FixedStack(frames ...Frame) StackProvider {
+	st := StackTrace(frames)
+	return func() StackTrace { return st }
+}
+
+// fakeFrameBase is the first Frame value used for frames created by
+// FakeFrame. Real program counters never come near it.
+const fakeFrameBase = ^uintptr(0) - 1<<20
+
+// The 1<<20 Frame values above fakeFrameBase are split into fakeFrameSlots
+// slots, each reused for up to 16 generations of FakeFrames as the least
+// recently requested ones are evicted.
+const fakeFrameSlots = 1 << 16
+
+var fakeFrames struct {
+	sync.Mutex
+	slots []fakeSlot
+	index map[fakeFrame]*list.Element
+	lru   list.List // of slot indexes, most recently requested first
+}
+
+type fakeFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+type fakeSlot struct {
+	frame fakeFrame
+	gen   uintptr
+}
+
+// FakeFrame returns a Frame that reports the given function, file, and line
+// instead of resolving a program counter. Calling FakeFrame again with the
+// same arguments returns the same Frame.
+//
+// Only the 65536 most recently requested FakeFrames are kept, so that
+// stack traces decoded from other processes do not accumulate; a Frame
+// whose entry was evicted reports an unknown function and file.
+func FakeFrame(function, file string, line int) Frame {
+	ff := fakeFrame{function, file, line}
+	fakeFrames.Lock()
+	defer fakeFrames.Unlock()
+	if e, ok := fakeFrames.index[ff]; ok {
+		fakeFrames.lru.MoveToFront(e)
+		return fakeFrameAt(e.Value.(int))
+	}
+	if fakeFrames.index == nil {
+		fakeFrames.index = make(map[fakeFrame]*list.Element)
+	}
+	var slot int
+	if len(fakeFrames.slots) < fakeFrameSlots {
+		slot = len(fakeFrames.slots)
+		fakeFrames.slots = append(fakeFrames.slots, fakeSlot{})
+	} else {
+		e := fakeFrames.lru.Back()
+		slot = fakeFrames.lru.Remove(e).(int)
+		delete(fakeFrames.index, fakeFrames.slots[slot].frame)
+		fakeFrames.slots[slot].gen = (fakeFrames.slots[slot].gen + 1) % (1 << 20 / fakeFrameSlots)
+	}
+	fakeFrames.slots[slot].frame = ff
+	fakeFrames.index[ff] = fakeFrames.lru.PushFront(slot)
+	return fakeFrameAt(slot)
+}
+
+// fakeFrameAt returns the Frame standing for the current generation of
+// slot. The caller must hold the lock of fakeFrames.
+func fakeFrameAt(slot int) Frame {
+	return Frame(fakeFrameBase + fakeFrames.slots[slot].gen*fakeFrameSlots + uintptr(slot))
+}
+
+// fake returns the fakeFrame f stands for, if f was created by FakeFrame.
+func (f Frame) fake() (fakeFrame, bool) {
+	if uintptr(f) < fakeFrameBase {
+		return fakeFrame{}, false
+	}
+	i := uintptr(f) - fakeFrameBase
+	slot, gen := int(i%fakeFrameSlots), i/fakeFrameSlots
+	fakeFrames.Lock()
+	defer fakeFrames.Unlock()
+	if slot >= len(fakeFrames.slots) {
+		return fakeFrame{}, false
+	}
+	if fakeFrames.slots[slot].gen != gen {
+		return fakeFrame{"unknown", "unknown", 0}, true
+	}
+	return fakeFrames.slots[slot].frame, true
+}
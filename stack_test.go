@@ -248,3 +248,71 @@ func caller() Frame {
 	frame, _ := frames.Next()
 	return Frame(frame.PC)
 }
+
+func TestSetStackProvider(t *testing.T) {
+	get := FakeFrame("github.com/acme/store.Get", "/src/store/store.go", 42)
+	main := FakeFrame("main.main", "/src/main.go", 7)
+	if FakeFrame("main.main", "/src/main.go", 7) != main {
+		t.Errorf("FakeFrame: same arguments returned a different Frame")
+	}
+
+	prev := SetStackProvider(FixedStack(get, main))
+	err := New("boom")
+	if SetStackProvider(prev) == nil {
+		t.Errorf("SetStackProvider: want previous provider, got nil")
+	}
+
+	want := "boom\n" +
+		"github.com/acme/store.Get\n\t/src/store/store.go:42\n" +
+		"main.main\n\t/src/main.go:7"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%v", get); got != "store.go:42" {
+		t.Errorf("%%v: got %q, want %q", got, "store.go:42")
+	}
+
+	err = New("real")
+	st := err.(*fundamental).StackTrace()
+	if len(st) == 0 || st[0].name() != "github.com/noke-inc/lib_errors.TestSetStackProvider" {
+		t.Errorf("after restore: got %v, want the real call stack", st)
+	}
+}
+
+func TestCaptureStacks(t *testing.T) {
+	CaptureStacks(false)
+	err := New("boom")
+	CaptureStacks(true)
+	if StackCaptureEnabled() != true {
+		t.Errorf("StackCaptureEnabled: got false after re-enabling")
+	}
+	if st := err.(*fundamental).StackTrace(); len(st) != 0 {
+		t.Errorf("StackTrace with capture off: got %v, want none", st)
+	}
+	if got := fmt.Sprintf("%+v", err); got != "boom" {
+		t.Errorf("%%+v with capture off: got %q, want %q", got, "boom")
+	}
+}
+
+func TestFakeFrameEviction(t *testing.T) {
+	first := FakeFrame("evicted.first", "/src/first.go", 1)
+	for i := 0; i < fakeFrameSlots; i++ {
+		f := FakeFrame("evicted.fill", "/src/fill.go", i)
+		if uintptr(f) < fakeFrameBase {
+			t.Fatalf("FakeFrame %d: got %#x, below the range of fake frames", i, uintptr(f))
+		}
+	}
+	fakeFrames.Lock()
+	n := len(fakeFrames.index)
+	fakeFrames.Unlock()
+	if n > fakeFrameSlots {
+		t.Errorf("FakeFrame: got %d frames kept, want at most %d", n, fakeFrameSlots)
+	}
+	if fn, file, _ := first.Location(); fn != "unknown" || file != "unknown" {
+		t.Errorf("Location of an evicted FakeFrame: got %s %s, want unknown", fn, file)
+	}
+	again := FakeFrame("evicted.first", "/src/first.go", 1)
+	if fn, _, line := again.Location(); fn != "evicted.first" || line != 1 {
+		t.Errorf("Location of a recreated FakeFrame: got %s:%d", fn, line)
+	}
+}
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// A StrictHook is told about sloppy error construction detected in strict
+// mode: problem describes what is wrong and caller is the frame outside this
+// package that constructed the error.
+type StrictHook func(problem string, caller Frame)
+
+// strictHook holds the StrictHook set with SetStrictHook.
+var strictHook atomic.Value
+
+// SetStrictHook turns on strict mode, in which every error constructed by
+// this package is checked and h is called for each of the following:
+//
+//   - an empty message given to New, Errorf, Wrap, Wrapf, WithMessage,
+//     WithMessagef, or WrapWithData;
+//   - an odd number of key/value arguments, a key that is not a string, or
+//     a key rejected by ValidateKey or by the KeyPolicy (see SetKeyPolicy);
+//   - a value of the wrong type under one of this package's keys, such as a
+//     string under KeyKind;
+//   - a value that cannot be encoded as JSON.
+//
+// SetStrictHook returns the previous hook. Setting a nil hook turns strict
+// mode off, which is the default. Strict mode is meant for development and
+// CI, where StrictPanic makes sloppy construction fail loudly:
+//
+//	func TestMain(m *testing.M) {
+//	        errors.SetStrictHook(errors.StrictPanic)
+//	        os.Exit(m.Run())
+//	}
+func SetStrictHook(h StrictHook) (previous StrictHook) {
+	previous, _ = strictHook.Load().(StrictHook)
+	strictHook.Store(h)
+	return previous
+}
+
+// StrictPanic is a StrictHook that panics with the problem and its caller.
+func StrictPanic(problem string, caller Frame) {
+	panic(fmt.Sprintf("errors: %s at %+s:%d", problem, caller, caller))
+}
+
+// reservedKeys maps the data keys of this package to the type of value they
+// must hold.
+var reservedKeys = map[string]reflect.Type{
+	KeyCode:              reflect.TypeOf(""),
+	KeyStage:             reflect.TypeOf(""),
+	KeyFingerprint:       reflect.TypeOf(""),
+	KeyUserMessage:       reflect.TypeOf(""),
+	KeyTitle:             reflect.TypeOf(""),
+	KeyHint:              reflect.TypeOf(""),
+	KeyStatusCode:        reflect.TypeOf(0),
+	KeyExitCode:          reflect.TypeOf(0),
+	KeyOp:                reflect.TypeOf(""),
+	KeyKind:              reflect.TypeOf(KindUnknown),
+	KeySeverity:          reflect.TypeOf(SeverityUnset),
+	KeyRetryAfter:        reflect.TypeOf(time.Duration(0)),
+	KeyElapsed:           reflect.TypeOf(time.Duration(0)),
+	KeyTimestamp:         reflect.TypeOf(time.Time{}),
+	KeyDeadline:          reflect.TypeOf(time.Time{}),
+	KeyDeadlineRemaining: reflect.TypeOf(time.Duration(0)),
+	KeyContextErr:        reflect.TypeOf(""),
+	KeyAttempt:           reflect.TypeOf(0),
+	KeyDelay:             reflect.TypeOf(time.Duration(0)),
+	KeyQuery:             reflect.TypeOf(""),
+	KeyQueryParams:       reflect.TypeOf(0),
+	KeyDroppedData:       reflect.TypeOf([]string(nil)),
+	KeyTenant:            reflect.TypeOf(""),
+	KeyRequestID:         reflect.TypeOf(""),
+	KeyTraceparent:       reflect.TypeOf(""),
+	KeyBuildModule:       reflect.TypeOf(""),
+	KeyBuildVersion:      reflect.TypeOf(""),
+	KeyBuildRevision:     reflect.TypeOf(""),
+	KeyBuildDirty:        reflect.TypeOf(false),
+	KeyFields:            reflect.TypeOf(map[string][]string(nil)),
+}
+
+// strictMessage checks message in strict mode.
+func strictMessage(message string) {
+	h, _ := strictHook.Load().(StrictHook)
+	if h == nil || message != "" {
+		return
+	}
+	h("empty message", strictCaller())
+}
+
+// strictData checks keyVals, as described for WithData, in strict mode.
+func strictData(keyVals []interface{}) {
+	h, _ := strictHook.Load().(StrictHook)
+	if h == nil {
+		return
+	}
+	if len(keyVals)%2 != 0 {
+		h(fmt.Sprintf("odd number of key/value arguments (%d)", len(keyVals)), strictCaller())
+	}
+	for i := 0; i+1 < len(keyVals); i += 2 {
+		key, ok := keyVals[i].(string)
+		if !ok {
+			h(fmt.Sprintf("key %v is a %T, not a string", keyVals[i], keyVals[i]), strictCaller())
+			continue
+		}
+		if err := ValidateKey(key); err != nil {
+			h(err.Error(), strictCaller())
+			continue
+		}
+		if err := checkKey(key); err != nil {
+			h(err.Error()+" (dropped by the key policy)", strictCaller())
+			continue
+		}
+		v := keyVals[i+1]
+		if want, ok := reservedKeys[key]; ok && reflect.TypeOf(v) != want {
+			h(fmt.Sprintf("key %q holds a %T, want %v", key, v, want), strictCaller())
+			continue
+		}
+		if _, err := json.Marshal(v); err != nil {
+			h(fmt.Sprintf("value of key %q is not serializable: %v", key, err), strictCaller())
+		}
+	}
+}
+
+// packageDir is the directory holding this package's source files.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// strictCaller returns the first frame on the stack outside this package's
+// non-test source files.
+func strictCaller() Frame {
+	const depth = 32
+	var pcs [depth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	for _, pc := range pcs[:n] {
+		file := Frame(pc).file()
+		if filepath.Dir(file) != packageDir || strings.HasSuffix(file, "_test.go") {
+			return Frame(pc)
+		}
+	}
+	return Frame(pcs[0])
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetStrictHook(t *testing.T) {
+	var got []string
+	prev := SetStrictHook(func(problem string, caller Frame) {
+		got = append(got, fmt.Sprintf("%s @%n", problem, caller))
+	})
+	defer SetStrictHook(prev)
+
+	New("")
+	Wrapf(New("boom"), "")
+	WithData(New("boom"), "lock_id")
+	WithData(New("boom"), 7, "x")
+	WithData(New("boom"), "", "x")
+	WithKind(New("boom"), KindNotFound)
+	WithData(New("boom"), KeyKind, "not_found")
+	WithData(New("boom"), "callback", func() {})
+	WrapWithData(New("boom"), "reading", "lock_id", "L-1", KeyStatusCode, 404)
+
+	want := []string{
+		"empty message @TestSetStrictHook",
+		"empty message @TestSetStrictHook",
+		"odd number of key/value arguments (1) @TestSetStrictHook",
+		"key 7 is a int, not a string @TestSetStrictHook",
+		`invalid data key "": empty @TestSetStrictHook`,
+		`key "kind" holds a string, want errors.Kind @TestSetStrictHook`,
+		`value of key "callback" is not serializable: json: unsupported type: func() @TestSetStrictHook`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StrictHook:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestStrictPanic(t *testing.T) {
+	prev := SetStrictHook(StrictPanic)
+	defer SetStrictHook(prev)
+	defer func() {
+		v := recover()
+		if s, _ := v.(string); !strings.HasPrefix(s, "errors: empty message at github.com/noke-inc/lib_errors.TestStrictPanic") {
+			t.Errorf("StrictPanic: got %v", v)
+		}
+	}()
+	New("")
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSummaryLength is the maximum length in bytes of the summaries returned
+// by Summarize. Zero or less means no limit.
+var MaxSummaryLength = 160
+
+// Summarize returns a one-line summary of err fitting MaxSummaryLength, such
+// as the title of an alert, where the whole chain would be unreadable:
+//
+//	lock_offline unavailable: loading lock at store.(*Repo).Save [9c2f6b1d03e4a871]
+//
+// It has the code and Kind of err, if recorded, the outermost message of
+// its chain, the function at the top of its origin stack (see FunctionOf),
+// if any, and its Fingerprint. Whitespace in the message is collapsed, and
+// the message is shortened, ending with "...", so that the summary fits.
+// If err is nil, Summarize returns "".
+func Summarize(err error) string {
+	if err == nil {
+		return ""
+	}
+	var head []string
+	if code := Code(err); code != "" {
+		head = append(head, code)
+	}
+	if k := KindOf(err); k != KindUnknown {
+		head = append(head, k.String())
+	}
+	var prefix, suffix string
+	if len(head) > 0 {
+		prefix = strings.Join(head, " ") + ": "
+	}
+	if fn := FunctionOf(err); fn != "" {
+		suffix = " at " + fn
+	}
+	suffix += " [" + Fingerprint(err) + "]"
+
+	var msg string
+	for _, l := range Layers(err) {
+		if l.Message != "" {
+			msg = strings.Join(strings.Fields(l.Message), " ")
+			break
+		}
+	}
+	if MaxSummaryLength <= 0 {
+		return prefix + msg + suffix
+	}
+	msg = truncate(msg, MaxSummaryLength-len(prefix)-len(suffix))
+	return truncate(prefix+msg+suffix, MaxSummaryLength)
+}
+
+// truncate returns s shortened to at most n bytes, ending with "..." if it
+// was, without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	const ellipsis = "..."
+	if len(s) <= n {
+		return s
+	}
+	if n < len(ellipsis) {
+		return ""
+	}
+	cut := n - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	err := Wrap(WithKind(WithCode(New("lock\noffline"), "lock_offline"), KindUnavailable), "loading  lock")
+	want := "lock_offline unavailable: loading lock at lib_errors.TestSummarize [" + Fingerprint(err) + "]"
+	if got := Summarize(err); got != want {
+		t.Errorf("Summarize: got %q, want %q", got, want)
+	}
+
+	if got, want := Summarize(io.EOF), "EOF ["+Fingerprint(io.EOF)+"]"; got != want {
+		t.Errorf("Summarize of a plain error: got %q, want %q", got, want)
+	}
+
+	long := Wrap(New("root"), strings.Repeat("é", 200))
+	got := Summarize(long)
+	if len(got) > MaxSummaryLength {
+		t.Errorf("Summarize: got %d bytes, want at most %d", len(got), MaxSummaryLength)
+	}
+	if !strings.Contains(got, "é...") || !strings.HasSuffix(got, "["+Fingerprint(long)+"]") {
+		t.Errorf("Summarize of a long message: got %q", got)
+	}
+
+	if Summarize(nil) != "" {
+		t.Error("Summarize(nil): want \"\"")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"abc", 3, "abc"},
+		{"abcdef", 5, "ab..."},
+		{"abcdef", 2, ""},
+		{"aébcd", 5, "a..."},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d): got %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Suppress records that suppressed was swallowed while primary was being
+// returned, like the suppressed exceptions of Java, so that errors from
+// cleanup in deferred calls are not lost:
+//
+//	defer func() { err = errors.Suppress(err, f.Close()) }()
+//
+// The suppressed errors do not change primary's message and are not
+// searched by Is, As, Walk, or GetValue; they are printed in a section of
+// their own by %+v and returned by Suppressed.
+// If suppressed is nil, Suppress returns primary. If primary is nil, there
+// is nothing to suppress suppressed in favor of, and Suppress returns it.
+func Suppress(primary, suppressed error) error {
+	if suppressed == nil {
+		return primary
+	}
+	if primary == nil {
+		return suppressed
+	}
+	if w, ok := primary.(*withSuppressed); ok {
+		errs := make([]error, len(w.suppressed), len(w.suppressed)+1)
+		copy(errs, w.suppressed)
+		return runHooks(&withSuppressed{w.error, append(errs, suppressed)}, HookWrap)
+	}
+	return runHooks(&withSuppressed{primary, []error{suppressed}}, HookWrap)
+}
+
+// Suppressed returns the errors recorded with Suppress anywhere in err's
+// chain, shallowest first and, for each error, in the order they were
+// suppressed.
+func Suppressed(err error) []error {
+	var errs []error
+	Walk(err, func(e error) bool {
+		if w, ok := e.(*withSuppressed); ok {
+			errs = append(errs, w.suppressed...)
+		}
+		return true
+	})
+	return errs
+}
+
+type withSuppressed struct {
+	error
+	suppressed []error
+}
+
+func (w *withSuppressed) Unwrap() error { return w.error }
+
+func (w *withSuppressed) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, w.GoString())
+			return
+		}
+		if s.Flag('+') {
+			if formatRegistered(s, w) {
+				return
+			}
+			fmt.Fprintf(s, "%+v", w.Unwrap())
+			if isDeepest(w.error) {
+				formatGlobalData(s)
+			}
+			c := formatConfig()
+			formatRelated(s, c.SuppressedLabel, w.suppressed, c)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like w.
+func (w *withSuppressed) GoString() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%#v", w.error)
+	for _, err := range w.suppressed {
+		fmt.Fprintf(&b, ", %#v)", err)
+	}
+	return strings.Repeat("errors.Suppress(", len(w.suppressed)) + b.String()
+}
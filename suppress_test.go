@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSuppress(t *testing.T) {
+	closeErr := New("close failed")
+	flushErr := New("flush failed")
+
+	if Suppress(io.EOF, nil) != io.EOF {
+		t.Error("Suppress(err, nil): want err")
+	}
+	if Suppress(nil, closeErr) != closeErr {
+		t.Error("Suppress(nil, err): want err")
+	}
+
+	err := Suppress(Suppress(io.EOF, flushErr), closeErr)
+	if err.Error() != "EOF" || !Is(err, io.EOF) || Is(err, closeErr) {
+		t.Errorf("Suppress: got %q, want the primary error only", err)
+	}
+	if got := Suppressed(WithMessage(err, "reading")); !reflect.DeepEqual(got, []error{flushErr, closeErr}) {
+		t.Errorf("Suppressed: got %v", got)
+	}
+	if got, want := fmt.Sprintf("%#v", Suppress(Suppress(io.EOF, io.ErrClosedPipe), io.ErrUnexpectedEOF)),
+		`errors.Suppress(errors.Suppress(&errors.errorString{s:"EOF"}, &errors.errorString{s:"io: read/write on closed pipe"}), &errors.errorString{s:"unexpected EOF"})`; got != want {
+		t.Errorf("%%#v: got %q, want %q", got, want)
+	}
+}
+
+func TestSuppressFormat(t *testing.T) {
+	defer SetStackProvider(SetStackProvider(FixedStack(FakeFrame("main.main", "/src/main.go", 7))))
+	err := Suppress(New("write failed"), New("close failed"))
+	want := "write failed\n" +
+		"main.main\n\t/src/main.go:7\n" +
+		"SUPPRESSED ERROR: close failed\n" +
+		"> main.main\n" +
+		"> \t/src/main.go:7"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	if snap, perr := Parse(want); perr != nil || len(snap.Layers) != 1 {
+		t.Errorf("Parse: got %+v, %v", snap, perr)
+	}
+}
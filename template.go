@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"io"
+	"text/template"
+)
+
+// TemplateData is the value a template is executed with by RenderTemplate.
+// Its embedded Snapshot provides the chain, outermost error first:
+//
+//	.Error                    the error's message, as returned by Error
+//	.Kind, .Code, .Severity   as returned by KindOf, Code, and SeverityOf
+//	.Data                     every key/value pair, as returned by GetAllData
+//	.Layers                   the SnapshotLayers of the chain, each with
+//	    .Message              the message added by the layer
+//	    .Data                 the key/value pairs recorded by the layer
+//	    .Stack                the SnapshotFrames recorded by the layer, each
+//	                          with .Function, .File, and .Line
+//	.GlobalData               the data set with SetGlobalData
+type TemplateData struct {
+	Snapshot
+	Error    string
+	Kind     Kind
+	Code     string
+	Severity Severity
+	Data     map[string]interface{}
+}
+
+// NewTemplateData returns the TemplateData describing err.
+func NewTemplateData(err error) TemplateData {
+	return TemplateData{
+		Snapshot: NewSnapshot(err),
+		Error:    err.Error(),
+		Kind:     KindOf(err),
+		Code:     Code(err),
+		Severity: SeverityOf(err),
+		Data:     GetAllData(err),
+	}
+}
+
+// RenderTemplate executes tmpl with the TemplateData of err and writes the
+// output to w, so that products can define their own output formats:
+//
+//	var short = template.Must(template.New("short").Parse(
+//	        `{{.Error}}{{range .Layers}}{{range .Stack}}{{"\n"}}  at {{.Function}} ({{.File}}:{{.Line}}){{end}}{{end}}`))
+//
+//	errors.RenderTemplate(os.Stderr, short, err)
+//
+// If err is nil, RenderTemplate writes nothing and returns nil.
+func RenderTemplate(w io.Writer, tmpl *template.Template, err error) error {
+	if err == nil {
+		return nil
+	}
+	return tmpl.Execute(w, NewTemplateData(err))
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	defer SetStackProvider(SetStackProvider(FixedStack(FakeFrame("main.main", "/src/main.go", 7))))
+
+	tmpl := template.Must(template.New("t").Parse(
+		`{{.Kind}} {{.Code}}: {{.Error}}` +
+			`{{range .Layers}}{{"\n"}}- {{.Message}}{{range $k, $v := .Data}} {{$k}}={{$v}}{{end}}` +
+			`{{range .Stack}} @{{.Function}}:{{.Line}}{{end}}{{end}}`))
+
+	err := Wrap(WithCode(WithKind(New("boom"), KindNotFound), "lock_missing"), "unlocking")
+	var b strings.Builder
+	if rerr := RenderTemplate(&b, tmpl, err); rerr != nil {
+		t.Fatalf("RenderTemplate: %v", rerr)
+	}
+	want := "not_found lock_missing: unlocking: boom\n" +
+		"- unlocking @main.main:7\n" +
+		"-  code=lock_missing\n" +
+		"-  kind=not_found\n" +
+		"- boom @main.main:7"
+	if got := b.String(); got != want {
+		t.Errorf("RenderTemplate:\n got %q\nwant %q", got, want)
+	}
+
+	b.Reset()
+	if rerr := RenderTemplate(&b, tmpl, nil); rerr != nil || b.Len() != 0 {
+		t.Errorf("RenderTemplate(nil): got %q, %v", b.String(), rerr)
+	}
+}
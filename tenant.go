@@ -0,0 +1,53 @@
+package errors
+
+import "context"
+
+// KeyTenant is the data key under which WithTenant records the tenant, such
+// as the customer account, that a failure affected.
+const KeyTenant = "tenant"
+
+// WithTenant annotates err with the ID of the tenant the failed operation
+// was performed for, so that errors can be counted and dashboards filtered
+// per customer.
+// If err is nil, WithTenant returns nil.
+func WithTenant(err error, tenant string) error {
+	return WithData(err, KeyTenant, tenant)
+}
+
+// WithContextTenant annotates err with the tenant recorded in ctx with
+// ContextWithTenant, as WithTenant does. If ctx has no tenant, err is
+// returned unchanged.
+// If err is nil, WithContextTenant returns nil.
+func WithContextTenant(ctx context.Context, err error) error {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return WithTenant(err, tenant)
+	}
+	return err
+}
+
+// Tenant returns the shallowest tenant recorded with WithTenant in err's
+// chain, and whether there was one.
+func Tenant(err error) (string, bool) {
+	if v, ok := GetValue(err, KeyTenant); ok {
+		if tenant, ok := v.(string); ok {
+			return tenant, true
+		}
+	}
+	return "", false
+}
+
+// tenantKey is the context key of the tenant set with ContextWithTenant.
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying tenant, typically set by
+// the middleware that authenticates a request, for WithContextTenant.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set with ContextWithTenant in ctx,
+// and whether there is one.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
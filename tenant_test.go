@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestTenant(t *testing.T) {
+	ctx := ContextWithTenant(context.Background(), "acme")
+
+	tests := []struct {
+		err    error
+		want   string
+		wantOk bool
+	}{
+		{nil, "", false},
+		{io.EOF, "", false},
+		{WithTenant(io.EOF, "acme"), "acme", true},
+		{Wrap(WithContextTenant(ctx, io.EOF), "wrapped"), "acme", true},
+		{WithTenant(WithTenant(io.EOF, "inner"), "outer"), "outer", true},
+		{WithContextTenant(context.Background(), io.EOF), "", false},
+		{WithContextTenant(ContextWithTenant(ctx, ""), io.EOF), "", false},
+		{WithData(io.EOF, KeyTenant, 7), "", false},
+	}
+	for i, tt := range tests {
+		got, ok := Tenant(tt.err)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("test %d: Tenant(%v): got (%q, %v), want (%q, %v)", i+1, tt.err, got, ok, tt.want, tt.wantOk)
+		}
+	}
+
+	if WithContextTenant(ctx, nil) != nil {
+		t.Error("WithContextTenant(nil): want nil")
+	}
+	if WithContextTenant(context.Background(), io.EOF) != io.EOF {
+		t.Error("WithContextTenant without a tenant: want err unchanged")
+	}
+}
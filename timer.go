@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyElapsed is the data key under which a Timer records how long the failed
+// operation ran.
+const KeyElapsed = "elapsed"
+
+// A Timer measures an operation so that its duration can be recorded in the
+// error it fails with:
+//
+//	timer := errors.StartTimer()
+//	state, err := lock.ReadState(ctx)
+//	if err != nil {
+//	        return timer.Wrap(err, "reading lock state")
+//	}
+type Timer struct {
+	start time.Time
+}
+
+// StartTimer returns a Timer started at the current time.
+func StartTimer() Timer {
+	return Timer{start: now()}
+}
+
+// Elapsed returns the time since t was started.
+func (t Timer) Elapsed() time.Duration {
+	return since(t.start)
+}
+
+// Wrap returns an error annotating err with a stack trace at the point Wrap
+// is called, the supplied message, and the time elapsed since t was started
+// under KeyElapsed.
+// If err is nil, Wrap returns nil.
+func (t Timer) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	err = &withMessage{
+		error: err,
+		msg:   message,
+	}
+	err = attachData(err, []interface{}{KeyElapsed, t.Elapsed()})
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
+
+// Wrapf returns an error annotating err with a stack trace at the point Wrapf
+// is called, the format specifier, and the time elapsed since t was started
+// under KeyElapsed.
+// If err is nil, Wrapf returns nil.
+func (t Timer) Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	err = &withMessage{
+		error: err,
+		msg:   fmt.Sprintf(format, args...),
+	}
+	err = attachData(err, []interface{}{KeyElapsed, t.Elapsed()})
+	return runHooks(&withStack{
+		err,
+		callers(),
+	}, HookWrap)
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestTimerWrapNil(t *testing.T) {
+	timer := StartTimer()
+	if got := timer.Wrap(nil, "no error"); got != nil {
+		t.Errorf("Timer.Wrap(nil): got %#v, expected nil", got)
+	}
+	if got := timer.Wrapf(nil, "no error %d", 1); got != nil {
+		t.Errorf("Timer.Wrapf(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestTimerWrap(t *testing.T) {
+	timer := StartTimer()
+	time.Sleep(time.Millisecond)
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{timer.Wrap(io.EOF, "reading lock state"), "reading lock state: EOF"},
+		{timer.Wrapf(io.EOF, "reading lock %d", 7), "reading lock 7: EOF"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+		v, ok := GetValue(tt.err, KeyElapsed)
+		if d, _ := v.(time.Duration); !ok || d < time.Millisecond {
+			t.Errorf("test %d: GetValue(%s): got (%v, %v)", i+1, KeyElapsed, v, ok)
+		}
+		stack := fmt.Sprintf("%+v", tt.err)
+		if !regexp.MustCompile(`github.com/noke-inc/lib_errors.TestTimerWrap\n`).MatchString(stack) {
+			t.Errorf("test %d: stack does not start at the caller:\n%s", i+1, stack)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// KeyTimestamp is the data key under which WithTimestamp records when an
+// error was created.
+const KeyTimestamp = "timestamp"
+
+// captureTimestamps is non-zero when New and Errorf record timestamps.
+var captureTimestamps int32
+
+// CaptureTimestamps sets whether New and Errorf automatically record the time
+// of their call as if by WithTimestamp. It is off by default.
+func CaptureTimestamps(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&captureTimestamps, v)
+}
+
+// TimestampCaptureEnabled reports whether New and Errorf automatically record
+// timestamps; see CaptureTimestamps.
+func TimestampCaptureEnabled() bool {
+	return atomic.LoadInt32(&captureTimestamps) != 0
+}
+
+// WithTimestamp annotates err with the current time, so that errors which are
+// queued or retried can report how stale they are with Age.
+// If err is nil, WithTimestamp returns nil.
+func WithTimestamp(err error) error {
+	return WithData(err, KeyTimestamp, now())
+}
+
+// withTimestamp returns f annotated with the current time if
+// CaptureTimestamps is enabled, and f unchanged otherwise.
+func withTimestamp(f *fundamental) error {
+	if atomic.LoadInt32(&captureTimestamps) == 0 {
+		return f
+	}
+	return attachData(f, []interface{}{KeyTimestamp, now()})
+}
+
+// Timestamp returns the deepest (and so earliest) time recorded with
+// WithTimestamp in err's chain, and whether there was one.
+func Timestamp(err error) (time.Time, bool) {
+	var ts time.Time
+	var found bool
+	for ; err != nil; err = Unwrap(err) {
+		w, ok := err.(*withData)
+		if !ok {
+			continue
+		}
+		if t, ok := w.data[KeyTimestamp].(time.Time); ok {
+			ts, found = t, true
+		}
+	}
+	return ts, found
+}
+
+// Age returns how long ago err was created according to Timestamp, and
+// whether err had a timestamp.
+func Age(err error) (time.Duration, bool) {
+	ts, ok := Timestamp(err)
+	if !ok {
+		return 0, false
+	}
+	return since(ts), true
+}
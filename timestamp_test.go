@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	if got := WithTimestamp(nil); got != nil {
+		t.Errorf("WithTimestamp(nil): got %#v, expected nil", got)
+	}
+	if _, ok := Timestamp(io.EOF); ok {
+		t.Errorf("Timestamp(io.EOF): got a timestamp")
+	}
+	if _, ok := Age(io.EOF); ok {
+		t.Errorf("Age(io.EOF): got an age")
+	}
+
+	before := time.Now()
+	inner := WithTimestamp(io.EOF)
+	time.Sleep(time.Millisecond)
+	err := WithTimestamp(Wrap(inner, "retrying"))
+
+	innerTs, _ := GetValue(inner, KeyTimestamp)
+	ts, ok := Timestamp(err)
+	if !ok || !ts.Equal(innerTs.(time.Time)) {
+		t.Errorf("Timestamp: got (%v, %v), want the deepest timestamp %v", ts, ok, innerTs)
+	}
+	if age, ok := Age(err); !ok || age < time.Millisecond || age > time.Since(before) {
+		t.Errorf("Age: got (%v, %v)", age, ok)
+	}
+}
+
+func TestCaptureTimestamps(t *testing.T) {
+	if _, ok := Timestamp(New("off")); ok {
+		t.Errorf("New: timestamp recorded while capture is off")
+	}
+
+	CaptureTimestamps(true)
+	defer CaptureTimestamps(false)
+	for _, err := range []error{New("on"), Errorf("on %d", 1)} {
+		if _, ok := Timestamp(err); !ok {
+			t.Errorf("%v: no timestamp recorded while capture is on", err)
+		}
+	}
+}
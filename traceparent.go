@@ -0,0 +1,64 @@
+package errors
+
+import "strings"
+
+// KeyTraceparent is the data key under which WithTraceparent records the
+// W3C Trace Context traceparent of the trace during which an error occurred.
+const KeyTraceparent = "traceparent"
+
+// WithTraceparent annotates err with traceparent, the value of a W3C Trace
+// Context traceparent header such as
+//
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+// so that an error surfacing hours later, for example in a queue consumer,
+// can still be linked to the trace it originated in. The traceparent is
+// carried wherever data is: in Snapshots and in the headers of errhttp.
+// If traceparent is not valid, err is returned unchanged.
+// If err is nil, WithTraceparent returns nil.
+func WithTraceparent(err error, traceparent string) error {
+	if !ValidTraceparent(traceparent) {
+		return err
+	}
+	return WithData(err, KeyTraceparent, traceparent)
+}
+
+// Traceparent returns the shallowest traceparent recorded with
+// WithTraceparent in err's chain, and whether there was a valid one.
+func Traceparent(err error) (string, bool) {
+	if v, ok := GetValue(err, KeyTraceparent); ok {
+		if tp, ok := v.(string); ok && ValidTraceparent(tp) {
+			return tp, true
+		}
+	}
+	return "", false
+}
+
+// ValidTraceparent reports whether s is a valid W3C Trace Context
+// traceparent: a version, a trace ID, a parent ID, and flags, in lower-case
+// hexadecimal separated by dashes, with non-zero IDs. Versions after 00 may
+// be followed by more fields.
+func ValidTraceparent(s string) bool {
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return false
+	}
+	version, traceID, parentID, flags := s[:2], s[3:35], s[36:52], s[53:55]
+	if !isLowerHex(version) || version == "ff" || !isLowerHex(flags) {
+		return false
+	}
+	if len(s) > 55 && (version == "00" || s[55] != '-') {
+		return false
+	}
+	return isLowerHex(traceID) && strings.Trim(traceID, "0") != "" &&
+		isLowerHex(parentID) && strings.Trim(parentID, "0") != ""
+}
+
+// isLowerHex reports whether s consists of lower-case hexadecimal digits.
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
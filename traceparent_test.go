@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestValidTraceparent(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{testTraceparent, true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{testTraceparent + "-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidTraceparent(tt.s); got != tt.want {
+			t.Errorf("ValidTraceparent(%q): got %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTraceparent(t *testing.T) {
+	err := Wrap(WithTraceparent(io.EOF, testTraceparent), "consuming job")
+	if tp, ok := Traceparent(err); tp != testTraceparent || !ok {
+		t.Errorf("Traceparent: got (%q, %v)", tp, ok)
+	}
+
+	b, jerr := json.Marshal(NewSnapshot(err))
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var s Snapshot
+	if jerr := json.Unmarshal(b, &s); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if tp, ok := Traceparent(s.Err()); tp != testTraceparent || !ok {
+		t.Errorf("Traceparent after a Snapshot round trip: got (%q, %v)", tp, ok)
+	}
+
+	if WithTraceparent(io.EOF, "bogus") != io.EOF {
+		t.Error("WithTraceparent with an invalid traceparent: want err unchanged")
+	}
+	if _, ok := Traceparent(WithData(io.EOF, KeyTraceparent, "bogus")); ok {
+		t.Error("Traceparent of an invalid value: want false")
+	}
+	if WithTraceparent(nil, testTraceparent) != nil {
+		t.Error("WithTraceparent(nil): want nil")
+	}
+}
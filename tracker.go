@@ -0,0 +1,212 @@
+package errors
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// trackerSlots is the number of slots a Tracker divides its window into.
+// Counts expire one slot at a time as the window slides.
+const trackerSlots = 10
+
+// maxTrackerEntries bounds the number of fingerprints a Tracker keeps. Past
+// it, the Tracker forgets those with no errors left in the window and, if
+// that is not enough, the one with the fewest, so that a spike is not lost
+// to a flood of distinct errors.
+const maxTrackerEntries = 10000
+
+// A Tracker counts errors per Fingerprint over a sliding window, so that a
+// service can notice a single error suddenly spiking without relying on an
+// external system. Use one Tracker per window length of interest.
+// A Tracker is safe for concurrent use.
+type Tracker struct {
+	slot time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]*trackerEntry
+	thresholds []trackerThreshold
+}
+
+// TrackerCount is the number of errors with a fingerprint seen within a
+// Tracker's window.
+type TrackerCount struct {
+	Fingerprint string
+	Count       uint64
+	// Err is the most recent error with the fingerprint.
+	Err error
+}
+
+type trackerEntry struct {
+	counts [trackerSlots]uint64
+	slot   int64     // absolute index of the newest slot in counts
+	seen   time.Time // when the fingerprint was last tracked
+	last   error
+}
+
+type trackerThreshold struct {
+	count uint64
+	fn    func(TrackerCount)
+}
+
+// NewTracker returns a Tracker counting errors seen within the last window.
+func NewTracker(window time.Duration) *Tracker {
+	slot := window / trackerSlots
+	if slot <= 0 {
+		slot = 1
+	}
+	return &Tracker{
+		slot:    slot,
+		entries: make(map[string]*trackerEntry),
+	}
+}
+
+// OnThreshold arranges for fn to be called whenever the count of a
+// fingerprint within the window reaches count. fn is called again for the
+// same fingerprint only after its count has fallen below count. fn is called
+// synchronously from Track and must not call back into t.
+func (t *Tracker) OnThreshold(count uint64, fn func(TrackerCount)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.thresholds = append(t.thresholds, trackerThreshold{count, fn})
+}
+
+// Track counts err against its fingerprint.
+// If err is nil, Track does nothing.
+func (t *Tracker) Track(err error) {
+	if err == nil {
+		return
+	}
+	fp := Fingerprint(err)
+	at := now()
+	slot := t.slotOf(at)
+
+	t.mu.Lock()
+	e, ok := t.entries[fp]
+	if !ok {
+		if len(t.entries) >= maxTrackerEntries {
+			t.prune(slot)
+		}
+		if len(t.entries) >= maxTrackerEntries {
+			t.evict()
+		}
+		e = &trackerEntry{slot: slot}
+		t.entries[fp] = e
+	}
+	e.advance(slot)
+	prev := e.total()
+	e.counts[slot%trackerSlots]++
+	e.seen = at
+	e.last = err
+	tc := TrackerCount{fp, prev + 1, err}
+	var fire []func(TrackerCount)
+	for _, th := range t.thresholds {
+		if prev < th.count && tc.Count >= th.count {
+			fire = append(fire, th.fn)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, fn := range fire {
+		fn(tc)
+	}
+}
+
+// Report implements Reporter by tracking err, so that a Tracker can be
+// registered with RegisterReporter.
+func (t *Tracker) Report(ctx context.Context, err error) { t.Track(err) }
+
+// Count returns the number of errors with the fingerprint fp seen within the
+// window.
+func (t *Tracker) Count(fp string) uint64 {
+	slot := t.slotOf(now())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[fp]
+	if !ok {
+		return 0
+	}
+	e.advance(slot)
+	return e.total()
+}
+
+// Top returns the n fingerprints with the most errors within the window,
+// most frequent first. If n <= 0, Top returns every fingerprint seen within
+// the window.
+func (t *Tracker) Top(n int) []TrackerCount {
+	slot := t.slotOf(now())
+	t.mu.Lock()
+	var top []TrackerCount
+	for fp, e := range t.entries {
+		e.advance(slot)
+		if c := e.total(); c > 0 {
+			top = append(top, TrackerCount{fp, c, e.last})
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Fingerprint < top[j].Fingerprint
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// slotOf returns the absolute index of the slot containing tm.
+func (t *Tracker) slotOf(tm time.Time) int64 {
+	return tm.UnixNano() / int64(t.slot)
+}
+
+// prune forgets the fingerprints with no errors left in the window.
+func (t *Tracker) prune(slot int64) {
+	for fp, e := range t.entries {
+		e.advance(slot)
+		if e.total() == 0 {
+			delete(t.entries, fp)
+		}
+	}
+}
+
+// evict forgets the fingerprint with the fewest errors in the window, the
+// one tracked least recently among equals. Entries must have been advanced
+// by prune.
+func (t *Tracker) evict() {
+	var fewest string
+	var min *trackerEntry
+	for fp, e := range t.entries {
+		if min == nil || e.total() < min.total() || e.total() == min.total() && e.seen.Before(min.seen) {
+			fewest, min = fp, e
+		}
+	}
+	delete(t.entries, fewest)
+}
+
+// advance slides e's window forward to slot, clearing the slots that expired.
+func (e *trackerEntry) advance(slot int64) {
+	if slot <= e.slot {
+		return
+	}
+	if slot-e.slot >= trackerSlots {
+		e.counts = [trackerSlots]uint64{}
+	} else {
+		for s := e.slot + 1; s <= slot; s++ {
+			e.counts[s%trackerSlots] = 0
+		}
+	}
+	e.slot = slot
+}
+
+// total returns the number of errors counted in e's window.
+func (e *trackerEntry) total() uint64 {
+	var n uint64
+	for _, c := range e.counts {
+		n += c
+	}
+	return n
+}
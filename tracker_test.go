@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	c := &fakeClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	SetClock(c)
+	defer SetClock(nil)
+
+	tr := NewTracker(time.Minute)
+	var fired []TrackerCount
+	tr.OnThreshold(3, func(tc TrackerCount) { fired = append(fired, tc) })
+
+	eof := Wrap(io.EOF, "reading")
+	other := New("other")
+	tr.Track(nil)
+	for i := 0; i < 4; i++ {
+		tr.Track(eof)
+	}
+	tr.Track(other)
+
+	top := tr.Top(1)
+	if len(top) != 1 || top[0].Fingerprint != Fingerprint(eof) || top[0].Count != 4 || top[0].Err != eof {
+		t.Errorf("Top(1): got %+v", top)
+	}
+	if all := tr.Top(0); len(all) != 2 || all[1].Count != 1 {
+		t.Errorf("Top(0): got %+v", all)
+	}
+	if len(fired) != 1 || fired[0].Count != 3 {
+		t.Errorf("OnThreshold: got %+v, want one call at 3", fired)
+	}
+
+	c.Advance(30 * time.Second)
+	tr.Track(other)
+	if n := tr.Count(Fingerprint(other)); n != 2 {
+		t.Errorf("Count after 30s: got %d, want 2", n)
+	}
+	c.Advance(40 * time.Second)
+	if n := tr.Count(Fingerprint(eof)); n != 0 {
+		t.Errorf("Count after the window: got %d, want 0", n)
+	}
+	if n := tr.Count(Fingerprint(other)); n != 1 {
+		t.Errorf("Count of the later error: got %d, want 1", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		tr.Track(eof)
+	}
+	if len(fired) != 2 {
+		t.Errorf("OnThreshold: got %d calls, want the threshold to re-arm", len(fired))
+	}
+}
+
+func TestTrackerCap(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	defer SetClock(SetClock(ClockFunc(func() time.Time {
+		at = at.Add(time.Microsecond)
+		return at
+	})))
+	fp := func(i int) error { return WithData(io.EOF, KeyFingerprint, strconv.Itoa(i)) }
+
+	tr := NewTracker(time.Hour)
+	tr.Track(fp(0))
+	tr.Track(fp(0))
+	for i := 1; i < maxTrackerEntries+10; i++ {
+		tr.Track(fp(i))
+	}
+	if n := len(tr.entries); n != maxTrackerEntries {
+		t.Errorf("entries: got %d, want %d", n, maxTrackerEntries)
+	}
+	if got := tr.Count("0"); got != 2 {
+		t.Errorf("Count of the most frequent fingerprint: got %d, want 2", got)
+	}
+	if got := tr.Count("1"); got != 0 {
+		t.Errorf("Count of the oldest single error: got %d, want it evicted", got)
+	}
+	if got := tr.Count(strconv.Itoa(maxTrackerEntries + 9)); got != 1 {
+		t.Errorf("Count of the newest fingerprint: got %d, want 1", got)
+	}
+}
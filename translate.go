@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// A Translation maps the errors it matches to the domain error To. An error
+// matches if it matches From according to Is, if its Code is Code, or if
+// Match reports true for it; unset fields are ignored.
+type Translation struct {
+	From  error
+	Code  string
+	Match func(error) bool
+	To    error
+}
+
+// matches reports whether err matches t.
+func (t Translation) matches(err error) bool {
+	return t.From != nil && Is(err, t.From) ||
+		t.Code != "" && Code(err) == t.Code ||
+		t.Match != nil && t.Match(err)
+}
+
+// A Translator maps low-level errors to domain errors at a package boundary,
+// such as a repository, so that callers depend on the domain errors only.
+// It is configured declaratively:
+//
+//	var translate = errors.Translator{
+//	        {From: sql.ErrNoRows, To: ErrLockNotFound},
+//	        {Code: "23505", To: ErrLockExists},
+//	}
+//
+//	func (r *Repo) Get(ctx context.Context, id string) (*Lock, error) {
+//	        ...
+//	        return nil, translate.Translate(err)
+//	}
+type Translator []Translation
+
+// Translate returns err translated by the first Translation that matches it,
+// or err unchanged if none does. The translated error has the message of
+// the domain error and matches it with Is and As, keeps err as its cause,
+// and carries the data of both, the domain error's winning. It records a
+// stack trace at the point Translate is called.
+// If err is nil, Translate returns nil.
+func (tr Translator) Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, t := range tr {
+		if t.matches(err) {
+			return runHooks(&withStack{
+				&translated{t.To, err},
+				callers(),
+			}, HookWrap)
+		}
+	}
+	return err
+}
+
+// translated is a domain error standing for the low-level error it wraps.
+type translated struct {
+	to    error
+	cause error
+}
+
+func (t *translated) Error() string { return t.to.Error() }
+
+func (t *translated) Unwrap() error { return t.cause }
+
+func (t *translated) Is(target error) bool { return Is(t.to, target) }
+
+func (t *translated) As(target interface{}) bool { return As(t.to, target) }
+
+// DataCache returns the data of the domain error merged over that of the
+// cause.
+func (t *translated) DataCache() map[string]interface{} {
+	kv := treeData(t.cause)
+	for k, v := range treeData(t.to) {
+		kv[k] = v
+	}
+	return kv
+}
+
+func (t *translated) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, t.GoString())
+			return
+		}
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v%s%s", t.cause, formatConfig().Separator, t.to.Error())
+			if isDeepest(t.cause) {
+				formatGlobalData(s)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, t.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", t.Error())
+	}
+}
+
+// GoString returns a Go expression building an error like t.
+func (t *translated) GoString() string {
+	return fmt.Sprintf("errors.Translator{{Match: func(error) bool { return true }, To: %#v}}.Translate(%#v)", t.to, t.cause)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTranslator(t *testing.T) {
+	errNotFound := WithKind(New("lock not found"), KindNotFound)
+	errExists := New("lock exists")
+	tr := Translator{
+		{From: io.EOF, To: errNotFound},
+		{Code: "23505", To: errExists},
+		{Match: func(err error) bool { return err.Error() == "busy" }, To: errExists},
+	}
+
+	low := WrapWithData(io.EOF, "scanning row", "table", "locks")
+	err := tr.Translate(low)
+	if err.Error() != "lock not found" {
+		t.Errorf("Error: got %q, want %q", err.Error(), "lock not found")
+	}
+	if !Is(err, errNotFound) || !Is(err, io.EOF) || Cause(err) != io.EOF {
+		t.Error("Translate: want the domain error and the cause both in the chain")
+	}
+	if KindOf(err) != KindNotFound {
+		t.Errorf("KindOf: got %v, want the domain error's kind", KindOf(err))
+	}
+	if v, _ := GetValue(err, "table"); v != "locks" {
+		t.Errorf("GetValue: got %v, want the cause's data", v)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "EOF\nscanning row") {
+		t.Errorf("%%+v: got %q, want the cause first", got)
+	}
+	if got := Layers(err)[1].Message; got != "lock not found" {
+		t.Errorf("Layers: got message %q, want the domain error's", got)
+	}
+
+	if !Is(tr.Translate(WithCode(New("duplicate key"), "23505")), errExists) {
+		t.Error("Translate by code: no match")
+	}
+	if !Is(tr.Translate(New("busy")), errExists) {
+		t.Error("Translate by Match: no match")
+	}
+	other := New("other")
+	if tr.Translate(other) != other || tr.Translate(nil) != nil {
+		t.Error("Translate without a match: want err unchanged")
+	}
+}
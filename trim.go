@@ -0,0 +1,96 @@
+package errors
+
+// Trim returns err with its chain shortened to at most maxDepth Layers, for
+// chains built up by deeply nested middlewares that overwhelm logs and
+// their readers. The outermost Layers are kept, along with the root cause
+// and the Layer recording the origin stack (the deepest stack trace, see
+// TopFrame), which are kept even if that exceeds maxDepth; the Layers in
+// between are dropped. Layers recording nothing, such as those of
+// MarkPermanent or MarkLogged, are kept without counting towards maxDepth,
+// so that the behavior they mark survives. Chains that fit maxDepth are
+// returned unchanged.
+// If err is nil, Trim returns nil.
+func Trim(err error, maxDepth int) error {
+	layers := Layers(err)
+	if len(layers) <= maxDepth {
+		return err
+	}
+	keep := make([]bool, len(layers))
+	keep[len(layers)-1] = true
+	kept := 1
+	for i, l := range layers {
+		keep[i] = keep[i] || emptyLayer(l)
+	}
+	if o := originLayer(layers); o >= 0 && !keep[o] {
+		keep[o] = true
+		kept++
+	}
+	for i := 0; kept < maxDepth && i < len(layers); i++ {
+		if !keep[i] {
+			keep[i] = true
+			kept++
+		}
+	}
+	return pruneLayers(layers, func(i int) bool { return keep[i] })
+}
+
+// Prune returns err with the Layers for which keep returns false dropped
+// from its chain:
+//
+//	short := errors.Prune(err, func(l errors.Layer) bool {
+//	        return l.Message != "" || len(l.Data) > 0
+//	})
+//
+// The root cause, the Layer recording the origin stack, and the Layers
+// recording nothing, such as those of MarkPermanent, are always kept, and
+// keep is not called for them. As with MapChain, the errors of the
+// chain below the first dropped Layer are kept as they are, wrappers of
+// this package above it are copied, and other errors are replaced by errors
+// of this package. If err is nil, Prune returns nil.
+func Prune(err error, keep func(Layer) bool) error {
+	layers := Layers(err)
+	return pruneLayers(layers, func(i int) bool { return keep(copyLayer(layers[i])) })
+}
+
+// pruneLayers rebuilds the chain of layers, outermost first, with only the
+// root cause, the origin stack, and the Layers at the indexes for which keep
+// returns true.
+func pruneLayers(layers []Layer, keep func(i int) bool) error {
+	origin := originLayer(layers)
+	var cur error
+	same := true
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		if i != len(layers)-1 && i != origin && !emptyLayer(l) && !keep(i) {
+			same = false
+			continue
+		}
+		if same {
+			cur = l.Err
+			continue
+		}
+		if err, ok := rewrap(l.Err, cur); ok {
+			cur = err
+			continue
+		}
+		cur = buildLayer(cur, l)
+	}
+	return cur
+}
+
+// emptyLayer reports whether l records no message, data, or stack, as the
+// Layers of the wrappers that only mark behavior, such as MarkPermanent.
+func emptyLayer(l Layer) bool {
+	return l.Message == "" && len(l.Data) == 0 && len(l.Stack) == 0
+}
+
+// originLayer returns the index of the deepest of layers recording a stack
+// trace, or -1 if none does.
+func originLayer(layers []Layer) int {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if len(layers[i].Stack) > 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func deepChain(depth int) error {
+	err := Wrap(io.EOF, "reading")
+	for i := 0; i < depth; i++ {
+		err = WithMessage(err, fmt.Sprintf("layer %d", i))
+	}
+	return err
+}
+
+func TestTrim(t *testing.T) {
+	err := deepChain(10)
+	origin := FormatStack(err)
+
+	short := Trim(err, 4)
+	if got := len(Layers(short)); got != 4 {
+		t.Errorf("Trim: got %d layers, want 4", got)
+	}
+	if got, want := short.Error(), "layer 9: layer 8: EOF"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if !Is(short, io.EOF) {
+		t.Error("Is: the root cause was not kept")
+	}
+	if got := FormatStack(short); got == "" || got != origin {
+		t.Errorf("FormatStack: got %q, want the origin stack %q", got, origin)
+	}
+
+	if got := len(Layers(Trim(err, 0))); got != 2 {
+		t.Errorf("Trim to 0: got %d layers, want the root cause and origin stack", got)
+	}
+	if Trim(err, 100) != err {
+		t.Error("Trim of a chain that fits: got a rebuilt chain")
+	}
+	if Trim(nil, 1) != nil {
+		t.Error("Trim(nil): want nil")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	err := WithData(deepChain(3), "lock_id", 7)
+
+	short := Prune(err, func(l Layer) bool { return l.Message != "layer 1" && l.Message != "reading" })
+	if got, want := short.Error(), "layer 2: layer 0: EOF"; got != want {
+		t.Errorf("Error: got %q, want %q", got, want)
+	}
+	if v, _ := GetValue(short, "lock_id"); v != 7 {
+		t.Errorf("GetValue: got %v, want the data that was kept", v)
+	}
+	if !Is(short, io.EOF) || FormatStack(short) != FormatStack(err) {
+		t.Error("Prune: the root cause and origin stack were not kept")
+	}
+
+	short = Prune(err, func(Layer) bool { return false })
+	if got, want := len(Layers(short)), 2; got != want {
+		t.Errorf("Prune of every layer: got %d layers, want %d", got, want)
+	}
+	if Prune(nil, func(Layer) bool { return false }) != nil {
+		t.Error("Prune(nil): want nil")
+	}
+}
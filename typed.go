@@ -0,0 +1,38 @@
+package errors
+
+// Typed is an error carrying a payload of type T, such as the state of the
+// resource an operation failed on. Unlike values recorded with WithData,
+// the payload keeps its static type: it is retrieved with PayloadAs rather
+// than by key and type assertion.
+type Typed[T any] struct {
+	Base
+	Payload T
+}
+
+// WithPayload annotates err with payload, retrievable with PayloadAs[T].
+// If err is nil, WithPayload returns nil.
+func WithPayload[T any](err error, payload T) error {
+	if err == nil {
+		return nil
+	}
+	return Typed[T]{Base{err}, payload}
+}
+
+func (t Typed[T]) rewrap(inner error) error { return Typed[T]{Base{inner}, t.Payload} }
+
+// PayloadAs returns the payload of the shallowest Typed[T] in err's chain,
+// and whether there was one. Payloads of other types are ignored:
+//
+//	err = errors.WithPayload(err, LockState{Bolt: "jammed"})
+//	...
+//	if st, ok := errors.PayloadAs[LockState](err); ok {
+//	        log.Printf("bolt %s", st.Bolt)
+//	}
+func PayloadAs[T any](err error) (T, bool) {
+	var t Typed[T]
+	if As(err, &t) {
+		return t.Payload, true
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type lockState struct {
+	Bolt    string
+	Battery int
+}
+
+func TestPayloadAs(t *testing.T) {
+	if err := WithPayload(nil, 1); err != nil {
+		t.Errorf("WithPayload(nil): got %v, want nil", err)
+	}
+
+	err := Wrap(WithPayload(WithPayload(io.EOF, lockState{"jammed", 40}), 7), "unlocking")
+	err = WithPayload(err, lockState{"open", 90})
+
+	if st, ok := PayloadAs[lockState](err); !ok || st != (lockState{"open", 90}) {
+		t.Errorf("PayloadAs[lockState]: got %v, %v, want the shallowest payload", st, ok)
+	}
+	if n, ok := PayloadAs[int](err); !ok || n != 7 {
+		t.Errorf("PayloadAs[int]: got %v, %v, want 7", n, ok)
+	}
+	if s, ok := PayloadAs[string](err); ok || s != "" {
+		t.Errorf("PayloadAs[string]: got %q, %v, want none", s, ok)
+	}
+	if !Is(err, io.EOF) || err.Error() != "unlocking: EOF" {
+		t.Errorf("chain: got %q", err.Error())
+	}
+	if got := fmt.Sprintf("%v", err); got != "unlocking: EOF" {
+		t.Errorf("%%v: got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package errors
+
+// KeyUserMessage is the data key under which WithUserMessage records a
+// message that is safe to show to end users.
+const KeyUserMessage = "user_message"
+
+// WithUserMessage annotates err with a message that is safe to show to end
+// users, unlike Error() which may expose internal details.
+// If err is nil, WithUserMessage returns nil.
+func WithUserMessage(err error, message string) error {
+	return WithData(err, KeyUserMessage, message)
+}
+
+// UserMessage returns the shallowest message recorded with WithUserMessage in
+// err's chain, or "" if there is none.
+func UserMessage(err error) string {
+	if v, ok := GetValue(err, KeyUserMessage); ok {
+		if msg, ok := v.(string); ok {
+			return msg
+		}
+	}
+	return ""
+}
+
+// KeyTitle is the data key under which WithTitle records the headline of an
+// error.
+const KeyTitle = "title"
+
+// WithTitle annotates err with a short headline that is safe to show to end
+// users, such as "Lock unreachable", which renderers display above the
+// detailed message recorded with WithUserMessage rather than truncating it.
+// If err is nil, WithTitle returns nil.
+func WithTitle(err error, title string) error {
+	return WithData(err, KeyTitle, title)
+}
+
+// Title returns the shallowest headline recorded with WithTitle in err's
+// chain, or "" if there is none.
+func Title(err error) string {
+	if v, ok := GetValue(err, KeyTitle); ok {
+		if title, ok := v.(string); ok {
+			return title
+		}
+	}
+	return ""
+}
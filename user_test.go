@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestUserMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithUserMessage(io.EOF, "lock is offline"), "lock is offline"},
+		{Wrap(WithUserMessage(io.EOF, "lock is offline"), "reading state"), "lock is offline"},
+		{WithUserMessage(WithUserMessage(io.EOF, "inner"), "outer"), "outer"},
+	}
+
+	for i, tt := range tests {
+		if got := UserMessage(tt.err); got != tt.want {
+			t.Errorf("test %d: UserMessage(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithUserMessage(nil, "msg"); got != nil {
+		t.Errorf("WithUserMessage(nil): got %#v, expected nil", got)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithTitle(io.EOF, "Lock unreachable"), "Lock unreachable"},
+		{Wrap(WithTitle(WithUserMessage(io.EOF, "lock is offline"), "Lock unreachable"), "reading state"), "Lock unreachable"},
+	}
+
+	for i, tt := range tests {
+		if got := Title(tt.err); got != tt.want {
+			t.Errorf("test %d: Title(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	if got := WithTitle(nil, "title"); got != nil {
+		t.Errorf("WithTitle(nil): got %#v, expected nil", got)
+	}
+}
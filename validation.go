@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a single input field that failed validation.
+type FieldError struct {
+	// Field is the path of the field, e.g. "schedule.days[2]".
+	Field string
+	// Rule names the rule the value broke, e.g. "required" or "max".
+	Rule string
+	// Value is the offending value.
+	Value interface{}
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Rule }
+
+// Validation aggregates FieldErrors so that every problem with an input can be
+// reported at once. The zero value is ready to use:
+//
+//	var v errors.Validation
+//	if req.Name == "" {
+//	        v.Add("name", "required", req.Name)
+//	}
+//	if req.Retries > 5 {
+//	        v.Add("retries", "max", req.Retries)
+//	}
+//	return v.Err()
+//
+// Validation implements DataError; its data records KindInvalid and the
+// field/rule map returned by Fields.
+type Validation struct {
+	Errors []*FieldError
+}
+
+// KeyFields is the data key under which a Validation reports its Fields.
+const KeyFields = "fields"
+
+// Add records that field broke rule with value.
+func (v *Validation) Add(field, rule string, value interface{}) {
+	v.Errors = append(v.Errors, &FieldError{Field: field, Rule: rule, Value: value})
+}
+
+// Check records that field broke rule with value if ok is false, and reports
+// ok.
+func (v *Validation) Check(ok bool, field, rule string, value interface{}) bool {
+	if !ok {
+		v.Add(field, rule, value)
+	}
+	return ok
+}
+
+// Err returns v annotated with a stack trace at the point Err is called, or
+// nil if no FieldErrors were recorded.
+func (v *Validation) Err() error {
+	if v == nil || len(v.Errors) == 0 {
+		return nil
+	}
+	return runHooks(&withStack{
+		v,
+		callers(),
+	}, HookNew)
+}
+
+// Fields returns the rules broken by each field, suitable for rendering in
+// JSON responses.
+func (v *Validation) Fields() map[string][]string {
+	fields := make(map[string][]string, len(v.Errors))
+	for _, e := range v.Errors {
+		fields[e.Field] = append(fields[e.Field], e.Rule)
+	}
+	return fields
+}
+
+func (v *Validation) Error() string {
+	msgs := make([]string, len(v.Errors))
+	for i, e := range v.Errors {
+		msgs[i] = e.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// DataCache returns KindInvalid under KeyKind and Fields under KeyFields.
+func (v *Validation) DataCache() map[string]interface{} {
+	return map[string]interface{}{
+		KeyKind:   KindInvalid,
+		KeyFields: v.Fields(),
+	}
+}
+
+func (v *Validation) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if formatRegistered(s, v) {
+				return
+			}
+			io.WriteString(s, "validation failed:")
+			c := formatConfig()
+			errs := append([]*FieldError(nil), v.Errors...)
+			sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+			for _, e := range errs {
+				fmt.Fprintf(s, "%s%s%s: %s (value: %+v)", c.Separator, c.FrameIndent, e.Field, e.Rule, e.Value)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, v.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", v.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestValidationEmpty(t *testing.T) {
+	var v Validation
+	if err := v.Err(); err != nil {
+		t.Errorf("Validation.Err(): got %#v, expected nil", err)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	var v Validation
+	v.Add("name", "required", "")
+	v.Check(3 <= 5, "retries", "max", 3)
+	v.Check(9 <= 5, "retries", "max", 9)
+	v.Add("retries", "odd", 9)
+
+	err := Wrap(v.Err(), "creating schedule")
+	if got, want := err.Error(), "creating schedule: validation failed: name: required; retries: max; retries: odd"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got := KindOf(err); got != KindInvalid {
+		t.Errorf("KindOf(): got %v, want %v", got, KindInvalid)
+	}
+
+	var d DataError
+	if !As(err, &d) {
+		t.Fatalf("Validation is not a DataError %v", reflect.TypeOf(err))
+	}
+	wantFields := map[string][]string{"name": {"required"}, "retries": {"max", "odd"}}
+	if got, _ := GetValue(err, KeyFields); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("GetValue(KeyFields): got %v, want %v", got, wantFields)
+	}
+
+	var target *Validation
+	if !As(err, &target) || len(target.Errors) != 3 {
+		t.Errorf("As(*Validation): got %v", target)
+	}
+
+	want := "validation failed:\n\tname: required (value: )\n\tretries: max (value: 9)\n\tretries: odd (value: 9)"
+	if got := fmt.Sprintf("%+v", target); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	defer SetFormatConfig(SetFormatConfig(FormatConfig{Separator: " | "}))
+	want = strings.ReplaceAll(want, "\n", " | ")
+	if got := fmt.Sprintf("%+v", target); got != want {
+		t.Errorf("%%+v with a separator: got %q, want %q", got, want)
+	}
+
+	SetFormatConfig(FormatConfig{Separator: " | ", FrameIndent: "- "})
+	want = strings.ReplaceAll(want, "\t", "- ")
+	if got := fmt.Sprintf("%+v", target); got != want {
+		t.Errorf("%%+v with a frame indent: got %q, want %q", got, want)
+	}
+}
+
+func TestValidationFieldsKeyPolicy(t *testing.T) {
+	defer SetKeyPolicy(SetKeyPolicy(KeyPattern(regexp.MustCompile(`^acme\.`))))
+
+	var v Validation
+	v.Add("name", "required", "")
+	want := v.Fields()
+	relayed := WithData(New("validation failed"), KeyKind, KindInvalid, KeyFields, want, "fields_extra", 1)
+	if got, _ := GetValue(relayed, KeyFields); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetValue(KeyFields) with KeyPattern: got %v, want %v", got, want)
+	}
+	if _, ok := GetValue(relayed, "fields_extra"); ok {
+		t.Error("KeyPattern: a key outside the policy was kept")
+	}
+}
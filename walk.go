@@ -0,0 +1,85 @@
+package errors
+
+// Walk calls fn for err and then, depth first, for every error err wraps,
+// until fn returns false. Besides Unwrap() error, Walk follows errors that
+// wrap several others:
+//
+//	Unwrap() []error         errors built with Join or the standard library
+//	WrappedErrors() []error  github.com/hashicorp/go-multierror
+//	Errors() []error         go.uber.org/multierr
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn)
+}
+
+// walk implements Walk, returning false once fn has returned false.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	if errs, ok := multiErrors(err); ok {
+		for _, e := range errs {
+			if !walk(e, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	return walk(Unwrap(err), fn)
+}
+
+// multiErrors returns the errors wrapped by err if it wraps several.
+func multiErrors(err error) ([]error, bool) {
+	switch e := err.(type) {
+	case interface{ WrappedErrors() []error }:
+		return e.WrappedErrors(), true
+	case interface{ Errors() []error }:
+		return e.Errors(), true
+	case interface{ Unwrap() []error }:
+		return e.Unwrap(), true
+	}
+	return nil, false
+}
+
+// Leaves returns the errors found by Walk that wrap no other error, in the
+// order Walk finds them. For a chain without multi-errors, this is just
+// the root cause.
+func Leaves(err error) []error {
+	var leaves []error
+	Walk(err, func(e error) bool {
+		if errs, ok := multiErrors(e); ok {
+			if len(errs) == 0 {
+				leaves = append(leaves, e)
+			}
+		} else if Unwrap(e) == nil {
+			leaves = append(leaves, e)
+		}
+		return true
+	})
+	return leaves
+}
+
+// treeData returns the key/value pairs recorded anywhere Walk reaches from
+// err. For a duplicated key, the value found first wins.
+func treeData(err error) map[string]interface{} {
+	kv := make(map[string]interface{})
+	add := func(data map[string]interface{}) {
+		for k, v := range data {
+			if _, ok := kv[k]; !ok {
+				kv[k] = v
+			}
+		}
+	}
+	Walk(err, func(e error) bool {
+		switch e := e.(type) {
+		case *withData:
+			add(e.data)
+		case DataError:
+			add(e.DataCache())
+		}
+		return true
+	})
+	return kv
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// hashiError mimics *multierror.Error of github.com/hashicorp/go-multierror.
+type hashiError struct{ errs []error }
+
+func (e *hashiError) Error() string          { return "hashi" }
+func (e *hashiError) WrappedErrors() []error { return e.errs }
+
+func TestWalk(t *testing.T) {
+	a := New("a")
+	b := WithData(io.EOF, "b", 2)
+	err := Wrap(&hashiError{[]error{a, Join(b, io.ErrClosedPipe)}}, "outer")
+
+	var msgs []string
+	Walk(err, func(e error) bool {
+		msgs = append(msgs, e.Error())
+		return true
+	})
+	want := []string{"outer: hashi", "outer: hashi", "hashi", "a", "EOF; io: read/write on closed pipe", "EOF", "EOF", "io: read/write on closed pipe"}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Errorf("Walk: got %q, want %q", msgs, want)
+	}
+
+	var n int
+	Walk(err, func(e error) bool {
+		n++
+		return e != a
+	})
+	if n != 4 {
+		t.Errorf("Walk: visited %d errors after stopping, want 4", n)
+	}
+
+	if got, want := Leaves(err), []error{a, io.EOF, io.ErrClosedPipe}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaves: got %v, want %v", got, want)
+	}
+	if got := Leaves(Wrap(io.EOF, "x")); !reflect.DeepEqual(got, []error{io.EOF}) {
+		t.Errorf("Leaves: got %v, want [EOF]", got)
+	}
+	if got := Leaves(nil); got != nil {
+		t.Errorf("Leaves(nil): got %v, want nil", got)
+	}
+}
+
+func TestMultiData(t *testing.T) {
+	err := WithData(&hashiError{[]error{
+		WithData(New("a"), "lock_id", 7, "dup", "a"),
+		WithData(New("b"), "user", "bob", "dup", "b"),
+	}}, "outer", true)
+
+	if v, ok := GetValue(err, "user"); !ok || v != "bob" {
+		t.Errorf("GetValue: got %v, %v, want bob", v, ok)
+	}
+	want := map[string]interface{}{"outer": true, "lock_id": 7, "user": "bob", "dup": "a"}
+	if got := GetAllData(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllData: got %v, want %v", got, want)
+	}
+	if got := err.(DataError).DataCache(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DataCache: got %v, want %v", got, want)
+	}
+	if !strings.Contains(err.Error(), "hashi") {
+		t.Errorf("Error: got %q", err.Error())
+	}
+}